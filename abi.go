@@ -0,0 +1,462 @@
+package gosolc
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ABI is the typed application binary interface of a contract.
+type ABI struct {
+	Constructor *ABIEntry   // Constructor, nil if the contract has none
+	Fallback    *ABIEntry   // Fallback function, nil if the contract has none
+	Receive     *ABIEntry   // Receive function, nil if the contract has none
+	Functions   []*ABIEntry // Functions, in the order of the ABI
+	Events      []*ABIEntry // Events, in the order of the ABI
+	Errors      []*ABIEntry // Custom errors, in the order of the ABI
+}
+
+// ABIEntry is a single entry of an ABI: a function, event, error, constructor, fallback or receive.
+type ABIEntry struct {
+	Type            string         `json:"type"`                      // "function", "event", "error", "constructor", "fallback" or "receive"
+	Name            string         `json:"name,omitempty"`            // Name of the function, event or error
+	Inputs          []ABIParameter `json:"inputs"`                    // Parameters (function, event, error and constructor)
+	Outputs         []ABIParameter `json:"outputs,omitempty"`         // Return values (function)
+	StateMutability string         `json:"stateMutability,omitempty"` // "pure", "view", "nonpayable" or "payable"
+	Anonymous       bool           `json:"anonymous,omitempty"`       // Whether the event is anonymous
+}
+
+// ABIParameter is a parameter of an ABI entry. Tuples carry their fields in Components.
+type ABIParameter struct {
+	Name         string         `json:"name"`
+	Type         string         `json:"type"`                   // ABI type, e.g. "uint256", "tuple[]" or "bytes32[2]"
+	InternalType string         `json:"internalType,omitempty"` // Solidity type, e.g. "struct Pool.Position[]"
+	Components   []ABIParameter `json:"components,omitempty"`   // Fields of tuple types
+	Indexed      bool           `json:"indexed,omitempty"`      // Whether the event parameter is indexed
+}
+
+// ABITypeKind is the kind of an ABI type.
+type ABITypeKind int
+
+const (
+	UintKind       ABITypeKind = iota // uint8 ... uint256
+	IntKind                           // int8 ... int256
+	AddressKind                       // address
+	BoolKind                          // bool
+	FixedBytesKind                    // bytes1 ... bytes32
+	FunctionKind                      // function (an address followed by a selector, encoded as bytes24)
+	BytesKind                         // bytes
+	StringKind                        // string
+	SliceKind                         // T[]
+	ArrayKind                         // T[k]
+	TupleKind                         // (T1,...,Tn)
+)
+
+// ABIType is a parsed ABI type.
+type ABIType struct {
+	Kind       ABITypeKind
+	Size       int        // Bits for int/uint, bytes for fixed bytes
+	Length     int        // Length of fixed size arrays
+	Elem       *ABIType   // Element type of arrays and slices
+	Components []*ABIType // Field types of tuples
+	Names      []string   // Field names of tuples
+}
+
+// ParseABI parses the JSON ABI of a contract.
+func ParseABI(data []byte) (*ABI, error) {
+	var entries []*ABIEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse ABI: %v", err)
+	}
+	return newABI(entries)
+}
+
+// newABI sorts ABI entries by type and validates their parameter types.
+func newABI(entries []*ABIEntry) (*ABI, error) {
+	abi := &ABI{}
+	for _, entry := range entries {
+		for _, params := range [][]ABIParameter{entry.Inputs, entry.Outputs} {
+			for _, param := range params {
+				if _, err := param.ABIType(); err != nil {
+					return nil, fmt.Errorf("invalid parameter %q of %s %s: %v", param.Name, entry.Type, entry.Name, err)
+				}
+			}
+		}
+
+		switch entry.Type {
+		case "function", "":
+			entry.Type = "function"
+			abi.Functions = append(abi.Functions, entry)
+		case "event":
+			abi.Events = append(abi.Events, entry)
+		case "error":
+			abi.Errors = append(abi.Errors, entry)
+		case "constructor":
+			abi.Constructor = entry
+		case "fallback":
+			abi.Fallback = entry
+		case "receive":
+			abi.Receive = entry
+		default:
+			return nil, fmt.Errorf("unknown ABI entry type %q", entry.Type)
+		}
+	}
+	return abi, nil
+}
+
+// Entries returns all entries of the ABI.
+func (abi *ABI) Entries() []*ABIEntry {
+	var entries []*ABIEntry
+	if abi.Constructor != nil {
+		entries = append(entries, abi.Constructor)
+	}
+	entries = append(entries, abi.Functions...)
+	entries = append(entries, abi.Events...)
+	entries = append(entries, abi.Errors...)
+	if abi.Fallback != nil {
+		entries = append(entries, abi.Fallback)
+	}
+	if abi.Receive != nil {
+		entries = append(entries, abi.Receive)
+	}
+	return entries
+}
+
+// MarshalJSON encodes the ABI in the solc JSON format.
+func (abi *ABI) MarshalJSON() ([]byte, error) {
+	entries := abi.Entries()
+	if entries == nil {
+		entries = []*ABIEntry{}
+	}
+	return json.Marshal(entries)
+}
+
+// UnmarshalJSON decodes an ABI in the solc JSON format.
+func (abi *ABI) UnmarshalJSON(data []byte) error {
+	parsed, err := ParseABI(data)
+	if err != nil {
+		return err
+	}
+	*abi = *parsed
+	return nil
+}
+
+// Function returns the function with the given name or signature (e.g. "transfer(address,uint256)").
+// Overloaded functions must be looked up by signature.
+func (abi *ABI) Function(nameOrSignature string) (*ABIEntry, error) {
+	return findABIEntry(abi.Functions, "function", nameOrSignature)
+}
+
+// Event returns the event with the given name or signature.
+func (abi *ABI) Event(nameOrSignature string) (*ABIEntry, error) {
+	return findABIEntry(abi.Events, "event", nameOrSignature)
+}
+
+// Error returns the custom error with the given name or signature.
+func (abi *ABI) Error(nameOrSignature string) (*ABIEntry, error) {
+	return findABIEntry(abi.Errors, "error", nameOrSignature)
+}
+
+func findABIEntry(entries []*ABIEntry, kind string, nameOrSignature string) (*ABIEntry, error) {
+	var matches []*ABIEntry
+	for _, entry := range entries {
+		if entry.Name == nameOrSignature || entry.Signature() == nameOrSignature {
+			matches = append(matches, entry)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("%s %s not found in ABI", kind, nameOrSignature)
+	case 1:
+		return matches[0], nil
+	default:
+		signatures := make([]string, len(matches))
+		for i, entry := range matches {
+			signatures[i] = entry.Signature()
+		}
+		return nil, fmt.Errorf("%s %s is overloaded, use one of: %s", kind, nameOrSignature, strings.Join(signatures, ", "))
+	}
+}
+
+// MethodIdentifiers returns the selectors of all functions keyed by signature, like solc's evm.methodIdentifiers.
+func (abi *ABI) MethodIdentifiers() map[string]string {
+	ids := make(map[string]string, len(abi.Functions))
+	for _, function := range abi.Functions {
+		selector := function.Selector()
+		ids[function.Signature()] = hex.EncodeToString(selector[:])
+	}
+	return ids
+}
+
+// MarshalJSON encodes the entry in the solc JSON format: functions, events, errors and constructors
+// always carry their inputs (functions their outputs and events the indexed and anonymous flags),
+// fallback and receive functions have none.
+func (entry *ABIEntry) MarshalJSON() ([]byte, error) {
+	type plainEntry ABIEntry
+	e := plainEntry(*entry)
+	if e.Inputs == nil {
+		e.Inputs = []ABIParameter{}
+	}
+	switch e.Type {
+	case "fallback", "receive":
+		return json.Marshal(struct {
+			Type            string `json:"type"`
+			StateMutability string `json:"stateMutability"`
+		}{e.Type, e.StateMutability})
+	case "function":
+		outputs := e.Outputs
+		if outputs == nil {
+			outputs = []ABIParameter{}
+		}
+		return json.Marshal(struct {
+			plainEntry
+			Outputs []ABIParameter `json:"outputs"`
+		}{e, outputs})
+	case "event":
+		type eventParameter struct {
+			ABIParameter
+			Indexed bool `json:"indexed"`
+		}
+		inputs := make([]eventParameter, len(e.Inputs))
+		for i, input := range e.Inputs {
+			inputs[i] = eventParameter{input, input.Indexed}
+		}
+		return json.Marshal(struct {
+			plainEntry
+			Inputs    []eventParameter `json:"inputs"`
+			Anonymous bool             `json:"anonymous"`
+		}{e, inputs, e.Anonymous})
+	}
+	return json.Marshal(e)
+}
+
+// Signature returns the canonical signature of the entry, e.g. "transfer(address,uint256)".
+// Tuples are expanded to their component types, as used for selector computation.
+func (entry *ABIEntry) Signature() string {
+	types := make([]string, len(entry.Inputs))
+	for i, input := range entry.Inputs {
+		t, err := input.ABIType()
+		if err != nil {
+			types[i] = input.Type
+			continue
+		}
+		types[i] = t.String()
+	}
+	return entry.Name + "(" + strings.Join(types, ",") + ")"
+}
+
+// Selector returns the 4-byte selector of a function or error: the first bytes of keccak256(signature).
+func (entry *ABIEntry) Selector() [4]byte {
+	var selector [4]byte
+	copy(selector[:], keccak256([]byte(entry.Signature())))
+	return selector
+}
+
+// Topic returns the topic0 of an event: keccak256(signature). Anonymous events have no topic0.
+func (entry *ABIEntry) Topic() [32]byte {
+	var topic [32]byte
+	copy(topic[:], keccak256([]byte(entry.Signature())))
+	return topic
+}
+
+// InputTypes returns the parsed types of the entry inputs.
+func (entry *ABIEntry) InputTypes() ([]*ABIType, error) {
+	return parameterTypes(entry.Inputs)
+}
+
+// OutputTypes returns the parsed types of the entry outputs.
+func (entry *ABIEntry) OutputTypes() ([]*ABIType, error) {
+	return parameterTypes(entry.Outputs)
+}
+
+func parameterTypes(params []ABIParameter) ([]*ABIType, error) {
+	types := make([]*ABIType, len(params))
+	for i, param := range params {
+		t, err := param.ABIType()
+		if err != nil {
+			return nil, fmt.Errorf("invalid parameter %q: %v", param.Name, err)
+		}
+		types[i] = t
+	}
+	return types, nil
+}
+
+// ABIType parses the type of the parameter, including tuple components.
+func (param ABIParameter) ABIType() (*ABIType, error) {
+	return parseABIType(param.Type, param.Components)
+}
+
+// parseABIType parses an ABI type string. Array suffixes are applied from left to right,
+// so "uint256[2][]" is a slice of arrays of length 2.
+func parseABIType(typ string, components []ABIParameter) (*ABIType, error) {
+	if i := strings.LastIndex(typ, "["); i >= 0 && strings.HasSuffix(typ, "]") {
+		elem, err := parseABIType(typ[:i], components)
+		if err != nil {
+			return nil, err
+		}
+
+		length := typ[i+1 : len(typ)-1]
+		if length == "" {
+			return &ABIType{Kind: SliceKind, Elem: elem}, nil
+		}
+		n, err := strconv.Atoi(length)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid array length in type %q", typ)
+		}
+		return &ABIType{Kind: ArrayKind, Length: n, Elem: elem}, nil
+	}
+
+	switch {
+	case typ == "address":
+		return &ABIType{Kind: AddressKind, Size: 160}, nil
+	case typ == "bool":
+		return &ABIType{Kind: BoolKind}, nil
+	case typ == "string":
+		return &ABIType{Kind: StringKind}, nil
+	case typ == "bytes":
+		return &ABIType{Kind: BytesKind}, nil
+	case typ == "function":
+		return &ABIType{Kind: FunctionKind, Size: 24}, nil
+	case typ == "tuple":
+		t := &ABIType{Kind: TupleKind}
+		for _, component := range components {
+			ct, err := component.ABIType()
+			if err != nil {
+				return nil, err
+			}
+			t.Components = append(t.Components, ct)
+			t.Names = append(t.Names, component.Name)
+		}
+		return t, nil
+	case strings.HasPrefix(typ, "bytes"):
+		n, err := strconv.Atoi(typ[len("bytes"):])
+		if err != nil || n < 1 || n > 32 {
+			return nil, fmt.Errorf("invalid type %q", typ)
+		}
+		return &ABIType{Kind: FixedBytesKind, Size: n}, nil
+	case strings.HasPrefix(typ, "uint"), strings.HasPrefix(typ, "int"):
+		kind, prefix := IntKind, "int"
+		if strings.HasPrefix(typ, "uint") {
+			kind, prefix = UintKind, "uint"
+		}
+		bits := 256
+		if typ != prefix {
+			n, err := strconv.Atoi(typ[len(prefix):])
+			if err != nil || n < 8 || n > 256 || n%8 != 0 {
+				return nil, fmt.Errorf("invalid type %q", typ)
+			}
+			bits = n
+		}
+		return &ABIType{Kind: kind, Size: bits}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported type %q", typ)
+}
+
+// String returns the canonical type string, e.g. "uint256", "(address,bytes)[]" or "bytes32[2]".
+func (t *ABIType) String() string {
+	switch t.Kind {
+	case UintKind:
+		return "uint" + strconv.Itoa(t.Size)
+	case IntKind:
+		return "int" + strconv.Itoa(t.Size)
+	case AddressKind:
+		return "address"
+	case BoolKind:
+		return "bool"
+	case FixedBytesKind:
+		return "bytes" + strconv.Itoa(t.Size)
+	case FunctionKind:
+		return "function"
+	case BytesKind:
+		return "bytes"
+	case StringKind:
+		return "string"
+	case SliceKind:
+		return t.Elem.String() + "[]"
+	case ArrayKind:
+		return t.Elem.String() + "[" + strconv.Itoa(t.Length) + "]"
+	case TupleKind:
+		types := make([]string, len(t.Components))
+		for i, component := range t.Components {
+			types[i] = component.String()
+		}
+		return "(" + strings.Join(types, ",") + ")"
+	}
+	return ""
+}
+
+// IsDynamic reports whether the type is dynamically sized in the ABI encoding.
+func (t *ABIType) IsDynamic() bool {
+	switch t.Kind {
+	case BytesKind, StringKind, SliceKind:
+		return true
+	case ArrayKind:
+		return t.Elem.IsDynamic()
+	case TupleKind:
+		for _, component := range t.Components {
+			if component.IsDynamic() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// GetContractABIs retrieves the typed ABI of each contract in the compiler output.
+// It returns a map where the keys are contract names and the values are their respective ABIs.
+func (contracts CompilerOutput) GetContractABIs() (map[string]*ABI, error) {
+	abis := make(map[string]*ABI)
+
+	for _, fileContracts := range contracts {
+		for name, contract := range fileContracts.(map[string]interface{}) {
+			abi, err := contractABI(contract.(map[string]interface{}))
+			if err != nil {
+				return nil, fmt.Errorf("invalid abi output for contract %s: %v", name, err)
+			}
+			abis[name] = abi
+		}
+	}
+
+	return abis, nil
+}
+
+// ContractABI retrieves the typed ABI of a single contract by fully qualified or unique bare name.
+func (contracts CompilerOutput) ContractABI(fqName string) (*ABI, error) {
+	contract, err := contracts.Contract(fqName)
+	if err != nil {
+		return nil, err
+	}
+
+	abi, err := contractABI(contract)
+	if err != nil {
+		return nil, fmt.Errorf("invalid abi output for contract %s: %v", fqName, err)
+	}
+	return abi, nil
+}
+
+// GetMethodIdentifiers retrieves solc's evm.methodIdentifiers of a contract, keyed by function signature.
+func (contracts CompilerOutput) GetMethodIdentifiers(fqName string) (map[string]string, error) {
+	contract, err := contracts.Contract(fqName)
+	if err != nil {
+		return nil, err
+	}
+
+	evm, _ := contract["evm"].(map[string]interface{})
+	var ids map[string]string
+	if err := decodeOutput(evm["methodIdentifiers"], &ids); err != nil || ids == nil {
+		return nil, fmt.Errorf("invalid methodIdentifiers output for contract %s", fqName)
+	}
+	return ids, nil
+}
+
+func contractABI(contract map[string]interface{}) (*ABI, error) {
+	var entries []*ABIEntry
+	if err := decodeOutput(contract["abi"], &entries); err != nil {
+		return nil, err
+	}
+	return newABI(entries)
+}
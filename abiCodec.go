@@ -0,0 +1,764 @@
+package gosolc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// ABI values are converted as follows.
+//
+// Encoding accepts:
+//   - uint/int: *big.Int, big.Int, any Go integer type, or a decimal / 0x hex string
+//   - address: Address, [20]byte, []byte or a hex string
+//   - bool: bool
+//   - bytesN and function: [N]byte, []byte of length N, Hash (bytes32) or a hex string
+//   - bytes: []byte or a 0x prefixed hex string
+//   - string: string
+//   - T[] and T[k]: any slice or array of values accepted for T
+//   - tuple: []interface{} (positional), map[string]interface{} (by component name), or a struct
+//     whose fields match the component names (ignoring case and underscores, or set with an `abi:"name"` tag)
+//
+// Decoding returns *big.Int for uint/int, Address, bool, []byte for bytesN, function and bytes,
+// string, []interface{} for arrays and slices, and []interface{} (positional) for tuples.
+
+// Log is an EVM log emitted by a contract.
+type Log struct {
+	Address Address
+	Topics  []Hash
+	Data    []byte
+}
+
+// Revert is decoded revert data: an Error(string) reason, a Panic(uint256) code or a custom error.
+type Revert struct {
+	Error     *ABIEntry     // Error definition; Error(string) and Panic(uint256) have builtin entries
+	Args      []interface{} // Decoded error arguments
+	Reason    string        // Reason of Error(string) reverts
+	PanicCode *big.Int      // Code of Panic(uint256) reverts
+}
+
+var (
+	// errorStringEntry is the builtin Error(string) revert.
+	errorStringEntry = &ABIEntry{Type: "error", Name: "Error", Inputs: []ABIParameter{{Name: "reason", Type: "string"}}}
+	// panicEntry is the builtin Panic(uint256) revert.
+	panicEntry = &ABIEntry{Type: "error", Name: "Panic", Inputs: []ABIParameter{{Name: "code", Type: "uint256"}}}
+
+	// panicReasons describes the panic codes emitted by solc.
+	panicReasons = map[uint64]string{
+		0x00: "generic compiler inserted panic",
+		0x01: "assertion failed",
+		0x11: "arithmetic underflow or overflow",
+		0x12: "division or modulo by zero",
+		0x21: "invalid enum value",
+		0x22: "invalid storage byte array encoding",
+		0x31: "pop on empty array",
+		0x32: "array index out of bounds",
+		0x41: "out of memory",
+		0x51: "call to zero-initialized internal function",
+	}
+)
+
+// String describes the revert, e.g. `Error("insufficient balance")` or `Panic(0x11: arithmetic underflow or overflow)`.
+func (r *Revert) String() string {
+	switch r.Error {
+	case errorStringEntry:
+		return fmt.Sprintf("Error(%q)", r.Reason)
+	case panicEntry:
+		if r.PanicCode.IsUint64() {
+			if reason, ok := panicReasons[r.PanicCode.Uint64()]; ok {
+				return fmt.Sprintf("Panic(0x%x: %s)", r.PanicCode, reason)
+			}
+		}
+		return fmt.Sprintf("Panic(0x%x)", r.PanicCode)
+	}
+
+	args := make([]string, len(r.Args))
+	for i, arg := range r.Args {
+		args[i] = fmt.Sprint(formatABIValue(arg))
+	}
+	return r.Error.Name + "(" + strings.Join(args, ", ") + ")"
+}
+
+// formatABIValue makes decoded byte values readable when printed.
+func formatABIValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case []byte:
+		return "0x" + hex.EncodeToString(v)
+	case []interface{}:
+		formatted := make([]interface{}, len(v))
+		for i, item := range v {
+			formatted[i] = formatABIValue(item)
+		}
+		return formatted
+	}
+	return v
+}
+
+// EncodeABIValues ABI-encodes values of the given types as a tuple.
+func EncodeABIValues(types []*ABIType, values []interface{}) ([]byte, error) {
+	if len(types) != len(values) {
+		return nil, fmt.Errorf("expected %d values, got %d", len(types), len(values))
+	}
+	return encodeTuple(types, values)
+}
+
+// DecodeABIValues decodes ABI-encoded data as a tuple of the given types.
+func DecodeABIValues(types []*ABIType, data []byte) ([]interface{}, error) {
+	return decodeTuple(types, data)
+}
+
+// EncodeInputs ABI-encodes the arguments of a function, constructor, event or error, without selector.
+func (entry *ABIEntry) EncodeInputs(args ...interface{}) ([]byte, error) {
+	types, err := entry.InputTypes()
+	if err != nil {
+		return nil, err
+	}
+	if len(args) != len(types) {
+		return nil, fmt.Errorf("%s expects %d arguments, got %d", entry.describe(), len(types), len(args))
+	}
+
+	encoded, err := encodeTuple(types, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode arguments of %s: %v", entry.describe(), err)
+	}
+	return encoded, nil
+}
+
+// EncodeCall returns the calldata of a function call: its selector followed by the encoded arguments.
+func (entry *ABIEntry) EncodeCall(args ...interface{}) ([]byte, error) {
+	encoded, err := entry.EncodeInputs(args...)
+	if err != nil {
+		return nil, err
+	}
+	selector := entry.Selector()
+	return append(selector[:], encoded...), nil
+}
+
+// DecodeInputs decodes ABI-encoded arguments of the entry (calldata or error data without selector).
+func (entry *ABIEntry) DecodeInputs(data []byte) ([]interface{}, error) {
+	types, err := entry.InputTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := decodeTuple(types, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode arguments of %s: %v", entry.describe(), err)
+	}
+	return values, nil
+}
+
+// DecodeOutputs decodes the return data of a function.
+func (entry *ABIEntry) DecodeOutputs(data []byte) ([]interface{}, error) {
+	types, err := entry.OutputTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := decodeTuple(types, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode return data of %s: %v", entry.describe(), err)
+	}
+	return values, nil
+}
+
+// DecodeLog decodes the indexed (topics) and non-indexed (data) parameters of an event, keyed by parameter name.
+// Unnamed parameters are keyed by their position ("arg0", "arg1", ...). Indexed parameters of dynamic
+// types (string, bytes, arrays and tuples) are only available as their keccak256 Hash.
+func (entry *ABIEntry) DecodeLog(log *Log) (map[string]interface{}, error) {
+	topics := log.Topics
+	if !entry.Anonymous {
+		if len(topics) == 0 || topics[0] != Hash(entry.Topic()) {
+			return nil, fmt.Errorf("log is not a %s event", entry.Signature())
+		}
+		topics = topics[1:]
+	}
+
+	var dataTypes []*ABIType
+	var dataNames []string
+	values := map[string]interface{}{}
+	for i, input := range entry.Inputs {
+		t, err := input.ABIType()
+		if err != nil {
+			return nil, err
+		}
+
+		name := input.Name
+		if name == "" {
+			name = fmt.Sprintf("arg%d", i)
+		}
+
+		if !input.Indexed {
+			dataTypes = append(dataTypes, t)
+			dataNames = append(dataNames, name)
+			continue
+		}
+
+		if len(topics) == 0 {
+			return nil, fmt.Errorf("log of %s has too few topics", entry.Signature())
+		}
+		topic := topics[0]
+		topics = topics[1:]
+
+		switch t.Kind {
+		case StringKind, BytesKind, SliceKind, ArrayKind, TupleKind:
+			values[name] = topic
+		default:
+			value, err := decodeValue(t, topic[:])
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode indexed parameter %s of %s: %v", name, entry.Signature(), err)
+			}
+			values[name] = value
+		}
+	}
+	if len(topics) > 0 {
+		return nil, fmt.Errorf("log of %s has too many topics", entry.Signature())
+	}
+
+	data, err := decodeTuple(dataTypes, log.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode data of %s: %v", entry.Signature(), err)
+	}
+	for i, name := range dataNames {
+		values[name] = data[i]
+	}
+
+	return values, nil
+}
+
+// describe returns a short description of the entry for error messages.
+func (entry *ABIEntry) describe() string {
+	if entry.Type == "constructor" {
+		return "constructor"
+	}
+	return entry.Type + " " + entry.Signature()
+}
+
+// EncodeCall returns the calldata of a call to the function with the given name or signature.
+func (abi *ABI) EncodeCall(nameOrSignature string, args ...interface{}) ([]byte, error) {
+	function, err := abi.Function(nameOrSignature)
+	if err != nil {
+		return nil, err
+	}
+	return function.EncodeCall(args...)
+}
+
+// EncodeConstructor ABI-encodes constructor arguments, to be appended to the creation bytecode.
+func (abi *ABI) EncodeConstructor(args ...interface{}) ([]byte, error) {
+	if abi.Constructor == nil {
+		if len(args) > 0 {
+			return nil, fmt.Errorf("contract has no constructor but %d arguments were given", len(args))
+		}
+		return []byte{}, nil
+	}
+	return abi.Constructor.EncodeInputs(args...)
+}
+
+// DecodeCall decodes calldata into the called function and its arguments.
+func (abi *ABI) DecodeCall(calldata []byte) (*ABIEntry, []interface{}, error) {
+	if len(calldata) < 4 {
+		return nil, nil, fmt.Errorf("calldata is shorter than a selector")
+	}
+	for _, function := range abi.Functions {
+		if selector := function.Selector(); bytes.Equal(selector[:], calldata[:4]) {
+			args, err := function.DecodeInputs(calldata[4:])
+			return function, args, err
+		}
+	}
+	return nil, nil, fmt.Errorf("no function with selector %x in ABI", calldata[:4])
+}
+
+// DecodeReturn decodes the return data of the function with the given name or signature.
+func (abi *ABI) DecodeReturn(nameOrSignature string, data []byte) ([]interface{}, error) {
+	function, err := abi.Function(nameOrSignature)
+	if err != nil {
+		return nil, err
+	}
+	return function.DecodeOutputs(data)
+}
+
+// DecodeLog finds the event of a log by its topic0 and decodes its parameters.
+// Anonymous events can't be identified and have to be decoded with ABIEntry.DecodeLog.
+func (abi *ABI) DecodeLog(log *Log) (*ABIEntry, map[string]interface{}, error) {
+	if len(log.Topics) == 0 {
+		return nil, nil, fmt.Errorf("log has no topics")
+	}
+	for _, event := range abi.Events {
+		if !event.Anonymous && Hash(event.Topic()) == log.Topics[0] {
+			values, err := event.DecodeLog(log)
+			return event, values, err
+		}
+	}
+	return nil, nil, fmt.Errorf("no event with topic %s in ABI", log.Topics[0])
+}
+
+// DecodeRevert decodes revert data into an Error(string) reason, a Panic(uint256) code or
+// one of the custom errors of the ABI.
+func (abi *ABI) DecodeRevert(data []byte) (*Revert, error) {
+	if len(data) < 4 {
+		return nil, fmt.Errorf("revert data is shorter than a selector")
+	}
+
+	entries := append([]*ABIEntry{errorStringEntry, panicEntry}, abi.Errors...)
+	for _, entry := range entries {
+		if selector := entry.Selector(); !bytes.Equal(selector[:], data[:4]) {
+			continue
+		}
+
+		args, err := entry.DecodeInputs(data[4:])
+		if err != nil {
+			return nil, err
+		}
+
+		revert := &Revert{Error: entry, Args: args}
+		switch entry {
+		case errorStringEntry:
+			revert.Reason = args[0].(string)
+		case panicEntry:
+			revert.PanicCode = args[0].(*big.Int)
+		}
+		return revert, nil
+	}
+
+	return nil, fmt.Errorf("no error with selector %x in ABI", data[:4])
+}
+
+// staticSize returns the size of the head of a value of the type in a tuple encoding.
+func (t *ABIType) staticSize() int {
+	if t.IsDynamic() {
+		return 32
+	}
+	switch t.Kind {
+	case ArrayKind:
+		return t.Length * t.Elem.staticSize()
+	case TupleKind:
+		size := 0
+		for _, component := range t.Components {
+			size += component.staticSize()
+		}
+		return size
+	}
+	return 32
+}
+
+func encodeTuple(types []*ABIType, values []interface{}) ([]byte, error) {
+	headSize := 0
+	for _, t := range types {
+		headSize += t.staticSize()
+	}
+
+	var head, tail []byte
+	for i, t := range types {
+		encoded, err := encodeValue(t, values[i])
+		if err != nil {
+			return nil, fmt.Errorf("value %d (%s): %v", i, t, err)
+		}
+		if t.IsDynamic() {
+			head = append(head, encodeUint(big.NewInt(int64(headSize+len(tail))))...)
+			tail = append(tail, encoded...)
+		} else {
+			head = append(head, encoded...)
+		}
+	}
+
+	return append(head, tail...), nil
+}
+
+func encodeValue(t *ABIType, v interface{}) ([]byte, error) {
+	switch t.Kind {
+	case UintKind, IntKind:
+		n, err := toBigInt(v)
+		if err != nil {
+			return nil, err
+		}
+		return encodeInteger(t, n)
+	case AddressKind:
+		b, err := toFixedBytes(v, 20)
+		if err != nil {
+			return nil, err
+		}
+		return leftPad(b), nil
+	case BoolKind:
+		b, ok := v.(bool)
+		if !ok {
+			return nil, fmt.Errorf("expected bool, got %T", v)
+		}
+		if b {
+			return encodeUint(big.NewInt(1)), nil
+		}
+		return make([]byte, 32), nil
+	case FixedBytesKind, FunctionKind:
+		b, err := toFixedBytes(v, t.Size)
+		if err != nil {
+			return nil, err
+		}
+		return rightPad(b), nil
+	case BytesKind:
+		b, err := toBytes(v)
+		if err != nil {
+			return nil, err
+		}
+		return append(encodeUint(big.NewInt(int64(len(b)))), rightPad(b)...), nil
+	case StringKind:
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected string, got %T", v)
+		}
+		return append(encodeUint(big.NewInt(int64(len(s)))), rightPad([]byte(s))...), nil
+	case SliceKind, ArrayKind:
+		items, err := toItems(v)
+		if err != nil {
+			return nil, err
+		}
+		if t.Kind == ArrayKind && len(items) != t.Length {
+			return nil, fmt.Errorf("expected %d items, got %d", t.Length, len(items))
+		}
+		types := make([]*ABIType, len(items))
+		for i := range types {
+			types[i] = t.Elem
+		}
+		encoded, err := encodeTuple(types, items)
+		if err != nil {
+			return nil, err
+		}
+		if t.Kind == SliceKind {
+			return append(encodeUint(big.NewInt(int64(len(items)))), encoded...), nil
+		}
+		return encoded, nil
+	case TupleKind:
+		fields, err := toTupleFields(t, v)
+		if err != nil {
+			return nil, err
+		}
+		return encodeTuple(t.Components, fields)
+	}
+	return nil, fmt.Errorf("unsupported type %s", t)
+}
+
+// encodeInteger encodes n as a two's complement 32 byte word after checking it fits the type.
+func encodeInteger(t *ABIType, n *big.Int) ([]byte, error) {
+	if t.Kind == UintKind {
+		if n.Sign() < 0 || n.BitLen() > t.Size {
+			return nil, fmt.Errorf("%s out of range for %s", n, t)
+		}
+		return encodeUint(n), nil
+	}
+
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(t.Size-1))
+	if n.Cmp(limit) >= 0 || n.Cmp(new(big.Int).Neg(limit)) < 0 {
+		return nil, fmt.Errorf("%s out of range for %s", n, t)
+	}
+	if n.Sign() < 0 {
+		n = new(big.Int).Add(n, new(big.Int).Lsh(big.NewInt(1), 256))
+	}
+	return encodeUint(n), nil
+}
+
+func encodeUint(n *big.Int) []byte {
+	return n.FillBytes(make([]byte, 32))
+}
+
+func leftPad(b []byte) []byte {
+	padded := make([]byte, 32)
+	copy(padded[32-len(b):], b)
+	return padded
+}
+
+func rightPad(b []byte) []byte {
+	padded := make([]byte, (len(b)+31)/32*32)
+	copy(padded, b)
+	return padded
+}
+
+func toBigInt(v interface{}) (*big.Int, error) {
+	switch v := v.(type) {
+	case *big.Int:
+		if v == nil {
+			return nil, fmt.Errorf("nil *big.Int")
+		}
+		return v, nil
+	case big.Int:
+		return &v, nil
+	case string:
+		n, ok := new(big.Int).SetString(v, 0)
+		if !ok {
+			return nil, fmt.Errorf("invalid integer %q", v)
+		}
+		return n, nil
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return big.NewInt(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return new(big.Int).SetUint64(rv.Uint()), nil
+	}
+	return nil, fmt.Errorf("expected integer, got %T", v)
+}
+
+// toFixedBytes converts byte arrays, byte slices and hex strings to exactly size bytes.
+func toFixedBytes(v interface{}, size int) ([]byte, error) {
+	var b []byte
+	switch v := v.(type) {
+	case string:
+		decoded, err := decodeHex(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hex %q", v)
+		}
+		b = decoded
+	case []byte:
+		b = v
+	default:
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Array || rv.Type().Elem().Kind() != reflect.Uint8 {
+			return nil, fmt.Errorf("expected %d bytes, got %T", size, v)
+		}
+		b = make([]byte, rv.Len())
+		reflect.Copy(reflect.ValueOf(b), rv)
+	}
+
+	if len(b) != size {
+		return nil, fmt.Errorf("expected %d bytes, got %d", size, len(b))
+	}
+	return b, nil
+}
+
+// toBytes converts byte slices, byte arrays and 0x prefixed hex strings to bytes.
+func toBytes(v interface{}) ([]byte, error) {
+	switch v := v.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		if !strings.HasPrefix(v, "0x") {
+			return nil, fmt.Errorf("expected 0x prefixed hex string, got %q", v)
+		}
+		return decodeHex(v)
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Array && rv.Type().Elem().Kind() == reflect.Uint8 {
+		return toFixedBytes(v, rv.Len())
+	}
+	return nil, fmt.Errorf("expected bytes, got %T", v)
+}
+
+// toItems converts any slice or array to its items.
+func toItems(v interface{}) ([]interface{}, error) {
+	if items, ok := v.([]interface{}); ok {
+		return items, nil
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, fmt.Errorf("expected slice or array, got %T", v)
+	}
+	items := make([]interface{}, rv.Len())
+	for i := range items {
+		items[i] = rv.Index(i).Interface()
+	}
+	return items, nil
+}
+
+// toTupleFields converts positional values, maps and structs to the fields of a tuple, in order.
+func toTupleFields(t *ABIType, v interface{}) ([]interface{}, error) {
+	switch v := v.(type) {
+	case []interface{}:
+		if len(v) != len(t.Components) {
+			return nil, fmt.Errorf("expected %d tuple fields, got %d", len(t.Components), len(v))
+		}
+		return v, nil
+	case map[string]interface{}:
+		fields := make([]interface{}, len(t.Components))
+		for i, name := range t.Names {
+			field, ok := v[name]
+			if !ok {
+				return nil, fmt.Errorf("missing tuple field %q", name)
+			}
+			fields[i] = field
+		}
+		return fields, nil
+	}
+
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expected tuple, got %T", v)
+	}
+
+	fields := make([]interface{}, len(t.Components))
+	for i, name := range t.Names {
+		field, ok := structField(rv, name)
+		if !ok {
+			return nil, fmt.Errorf("struct %s has no field for tuple component %q", rv.Type(), name)
+		}
+		fields[i] = field.Interface()
+	}
+	return fields, nil
+}
+
+// structField finds the exported field of a struct matching an ABI component name.
+func structField(rv reflect.Value, name string) (reflect.Value, bool) {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		if tag, ok := rt.Field(i).Tag.Lookup("abi"); ok && tag == name {
+			return rv.Field(i), true
+		}
+	}
+	normalize := func(s string) string {
+		return strings.ToLower(strings.Map(func(r rune) rune {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				return r
+			}
+			return -1
+		}, s))
+	}
+	for i := 0; i < rt.NumField(); i++ {
+		if rt.Field(i).IsExported() && normalize(rt.Field(i).Name) == normalize(name) {
+			return rv.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+func decodeTuple(types []*ABIType, data []byte) ([]interface{}, error) {
+	values := make([]interface{}, len(types))
+	pos := 0
+	for i, t := range types {
+		var err error
+		if t.IsDynamic() {
+			offset, err := readLength(data, pos)
+			if err != nil {
+				return nil, fmt.Errorf("value %d (%s): %v", i, t, err)
+			}
+			if offset > len(data) {
+				return nil, fmt.Errorf("value %d (%s): offset %d out of bounds", i, t, offset)
+			}
+			values[i], err = decodeValue(t, data[offset:])
+			if err != nil {
+				return nil, fmt.Errorf("value %d (%s): %v", i, t, err)
+			}
+			pos += 32
+			continue
+		}
+
+		size := t.staticSize()
+		if pos+size > len(data) {
+			return nil, fmt.Errorf("value %d (%s): data too short", i, t)
+		}
+		values[i], err = decodeValue(t, data[pos:pos+size])
+		if err != nil {
+			return nil, fmt.Errorf("value %d (%s): %v", i, t, err)
+		}
+		pos += size
+	}
+	return values, nil
+}
+
+func decodeValue(t *ABIType, data []byte) (interface{}, error) {
+	switch t.Kind {
+	case SliceKind, ArrayKind:
+		n := t.Length
+		if t.Kind == SliceKind {
+			var err error
+			n, err = readLength(data, 0)
+			if err != nil {
+				return nil, err
+			}
+			data = data[32:]
+			// every item takes at least one word in the head
+			if n > len(data)/32 {
+				return nil, fmt.Errorf("length %d exceeds data size", n)
+			}
+		}
+		types := make([]*ABIType, n)
+		for i := range types {
+			types[i] = t.Elem
+		}
+		return decodeTuple(types, data)
+	case TupleKind:
+		return decodeTuple(t.Components, data)
+	case BytesKind, StringKind:
+		n, err := readLength(data, 0)
+		if err != nil {
+			return nil, err
+		}
+		if 32+n > len(data) {
+			return nil, fmt.Errorf("length %d exceeds data size", n)
+		}
+		b := append([]byte{}, data[32:32+n]...)
+		if t.Kind == StringKind {
+			return string(b), nil
+		}
+		return b, nil
+	}
+
+	if len(data) < 32 {
+		return nil, fmt.Errorf("data too short")
+	}
+	word := data[:32]
+
+	switch t.Kind {
+	case UintKind:
+		n := new(big.Int).SetBytes(word)
+		if n.BitLen() > t.Size {
+			return nil, fmt.Errorf("value out of range for %s", t)
+		}
+		return n, nil
+	case IntKind:
+		n := new(big.Int).SetBytes(word)
+		if word[0]&0x80 != 0 {
+			n.Sub(n, new(big.Int).Lsh(big.NewInt(1), 256))
+		}
+		limit := new(big.Int).Lsh(big.NewInt(1), uint(t.Size-1))
+		if n.Cmp(limit) >= 0 || n.Cmp(new(big.Int).Neg(limit)) < 0 {
+			return nil, fmt.Errorf("value out of range for %s", t)
+		}
+		return n, nil
+	case AddressKind:
+		if !isZero(word[:12]) {
+			return nil, fmt.Errorf("dirty upper bytes for address")
+		}
+		var a Address
+		copy(a[:], word[12:])
+		return a, nil
+	case BoolKind:
+		if !isZero(word[:31]) || word[31] > 1 {
+			return nil, fmt.Errorf("invalid bool")
+		}
+		return word[31] == 1, nil
+	case FixedBytesKind, FunctionKind:
+		if !isZero(word[t.Size:]) {
+			return nil, fmt.Errorf("dirty lower bytes for %s", t)
+		}
+		return append([]byte{}, word[:t.Size]...), nil
+	}
+	return nil, fmt.Errorf("unsupported type %s", t)
+}
+
+// readLength reads a word at pos that is used as an offset or length.
+func readLength(data []byte, pos int) (int, error) {
+	if pos+32 > len(data) {
+		return 0, fmt.Errorf("data too short")
+	}
+	n := new(big.Int).SetBytes(data[pos : pos+32])
+	if !n.IsInt64() || n.Int64() > int64(len(data)) {
+		return 0, fmt.Errorf("offset or length %s out of bounds", n)
+	}
+	return int(n.Int64()), nil
+}
+
+func isZero(b []byte) bool {
+	for _, c := range b {
+		if c != 0 {
+			return false
+		}
+	}
+	return true
+}
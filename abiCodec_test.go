@@ -0,0 +1,234 @@
+package gosolc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := decodeHex(strings.Join(strings.Fields(s), ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestABIEncodeDynamicValues(t *testing.T) {
+	// Examples from the Solidity ABI specification
+	f := &ABIEntry{Type: "function", Name: "f", Inputs: []ABIParameter{
+		{Type: "uint256"}, {Type: "uint32[]"}, {Type: "bytes10"}, {Type: "bytes"},
+	}}
+	calldata, err := f.EncodeCall(0x123, []uint32{0x456, 0x789}, []byte("1234567890"), []byte("Hello, world!"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := mustDecodeHex(t, `8be65246
+		0000000000000000000000000000000000000000000000000000000000000123
+		0000000000000000000000000000000000000000000000000000000000000080
+		3132333435363738393000000000000000000000000000000000000000000000
+		00000000000000000000000000000000000000000000000000000000000000e0
+		0000000000000000000000000000000000000000000000000000000000000002
+		0000000000000000000000000000000000000000000000000000000000000456
+		0000000000000000000000000000000000000000000000000000000000000789
+		000000000000000000000000000000000000000000000000000000000000000d
+		48656c6c6f2c20776f726c642100000000000000000000000000000000000000`)
+	if !bytes.Equal(calldata, expected) {
+		t.Fatalf("unexpected calldata %x", calldata)
+	}
+
+	g := &ABIEntry{Type: "function", Name: "g", Inputs: []ABIParameter{{Type: "uint256[][]"}, {Type: "string[]"}}}
+	calldata, err = g.EncodeCall([][]int{{1, 2}, {3}}, []string{"one", "two", "three"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = mustDecodeHex(t, `2289b18c
+		0000000000000000000000000000000000000000000000000000000000000040
+		0000000000000000000000000000000000000000000000000000000000000140
+		0000000000000000000000000000000000000000000000000000000000000002
+		0000000000000000000000000000000000000000000000000000000000000040
+		00000000000000000000000000000000000000000000000000000000000000a0
+		0000000000000000000000000000000000000000000000000000000000000002
+		0000000000000000000000000000000000000000000000000000000000000001
+		0000000000000000000000000000000000000000000000000000000000000002
+		0000000000000000000000000000000000000000000000000000000000000001
+		0000000000000000000000000000000000000000000000000000000000000003
+		0000000000000000000000000000000000000000000000000000000000000003
+		0000000000000000000000000000000000000000000000000000000000000060
+		00000000000000000000000000000000000000000000000000000000000000a0
+		00000000000000000000000000000000000000000000000000000000000000e0
+		0000000000000000000000000000000000000000000000000000000000000003
+		6f6e650000000000000000000000000000000000000000000000000000000000
+		0000000000000000000000000000000000000000000000000000000000000003
+		74776f0000000000000000000000000000000000000000000000000000000000
+		0000000000000000000000000000000000000000000000000000000000000005
+		7468726565000000000000000000000000000000000000000000000000000000`)
+	if !bytes.Equal(calldata, expected) {
+		t.Fatalf("unexpected calldata %x", calldata)
+	}
+
+	args, err := g.DecodeInputs(calldata[4:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := []interface{}{
+		[]interface{}{[]interface{}{big.NewInt(1), big.NewInt(2)}, []interface{}{big.NewInt(3)}},
+		[]interface{}{"one", "two", "three"},
+	}
+	if !reflect.DeepEqual(args, decoded) {
+		t.Fatalf("unexpected decoded arguments %v", args)
+	}
+}
+
+func TestABITupleRoundTrip(t *testing.T) {
+	abi, err := loadTestOutput(t, "abi_output.json").ContractABI("Token")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	target, _ := HexToAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+	type call3 struct {
+		Target       Address
+		AllowFailure bool
+		CallData     []byte
+	}
+	calldata, err := abi.EncodeCall("aggregate3", []call3{{target, true, []byte{0xde, 0xad}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	function, args, err := abi.DecodeCall(calldata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []interface{}{[]interface{}{[]interface{}{target, true, []byte{0xde, 0xad}}}}
+	if function.Name != "aggregate3" || !reflect.DeepEqual(args, expected) {
+		t.Fatalf("unexpected decoded call %s %v", function.Name, args)
+	}
+
+	if _, err := abi.EncodeCall("decimals", 1); err == nil {
+		t.Fatal("expected an error for a wrong number of arguments")
+	}
+	if _, err := abi.EncodeCall("approve", target, -1); err == nil {
+		t.Fatal("expected an error for a negative uint")
+	}
+}
+
+func TestABIEncodeStructFieldNames(t *testing.T) {
+	entry := &ABIEntry{Type: "function", Name: "fill", Inputs: []ABIParameter{{
+		Name: "order",
+		Type: "tuple",
+		Components: []ABIParameter{
+			{Name: "_maker", Type: "address"},
+			{Name: "fee_bps", Type: "uint16"},
+		},
+	}}}
+
+	maker, _ := HexToAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+	type order struct {
+		Maker  Address
+		FeeBps uint16
+	}
+	fromStruct, err := entry.EncodeInputs(order{maker, 30})
+	if err != nil {
+		t.Fatal(err)
+	}
+	positional, err := entry.EncodeInputs([]interface{}{maker, 30})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(fromStruct, positional) {
+		t.Fatalf("struct fields not matched to components:\n%x\n%x", fromStruct, positional)
+	}
+}
+
+func TestABIDecodeLog(t *testing.T) {
+	abi, err := loadTestOutput(t, "abi_output.json").ContractABI("Token")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	from, _ := HexToAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+	var to Address
+	transfer, _ := abi.Event("Transfer")
+	log := &Log{
+		Topics: []Hash{transfer.Topic(), Hash(leftPad(from[:])), Hash(leftPad(to[:]))},
+		Data:   encodeUint(big.NewInt(1000)),
+	}
+
+	event, values, err := abi.DecodeLog(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{"from": from, "to": to, "value": big.NewInt(1000)}
+	if event != transfer || !reflect.DeepEqual(values, expected) {
+		t.Fatalf("unexpected decoded log %v", values)
+	}
+}
+
+func TestABIDecodeRevert(t *testing.T) {
+	abi, err := loadTestOutput(t, "abi_output.json").ContractABI("Token")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := mustDecodeHex(t, `08c379a0
+		0000000000000000000000000000000000000000000000000000000000000020
+		000000000000000000000000000000000000000000000000000000000000001a
+		4e6f7420656e6f7567682045746865722070726f76696465642e000000000000`)
+	revert, err := abi.DecodeRevert(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if revert.Reason != "Not enough Ether provided." {
+		t.Fatalf("unexpected reason %q", revert.Reason)
+	}
+
+	revert, err = abi.DecodeRevert(mustDecodeHex(t, "4e487b71"+hex.EncodeToString(encodeUint(big.NewInt(0x11)))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if revert.String() != "Panic(0x11: arithmetic underflow or overflow)" {
+		t.Fatalf("unexpected panic %s", revert)
+	}
+
+	insufficient, _ := abi.Error("ERC20InsufficientBalance")
+	data, err = insufficient.EncodeCall("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", 1, "0x2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	revert, err = abi.DecodeRevert(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if revert.String() != "ERC20InsufficientBalance(0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed, 1, 2)" {
+		t.Fatalf("unexpected custom error %s", revert)
+	}
+}
+
+func TestABIEncodeSignedIntegers(t *testing.T) {
+	types := []*ABIType{{Kind: IntKind, Size: 8}, {Kind: IntKind, Size: 256}}
+	encoded, err := EncodeABIValues(types, []interface{}{-128, big.NewInt(-1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(encoded) != strings.Repeat("ff", 31)+"80"+strings.Repeat("ff", 32) {
+		t.Fatalf("unexpected encoding %x", encoded)
+	}
+
+	values, err := DecodeABIValues(types, encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values[0].(*big.Int).Int64() != -128 || values[1].(*big.Int).Int64() != -1 {
+		t.Fatalf("unexpected values %v", values)
+	}
+
+	if _, err := EncodeABIValues(types[:1], []interface{}{128}); err == nil {
+		t.Fatal("expected an error for an int8 overflow")
+	}
+}
@@ -0,0 +1,400 @@
+package gosolc
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ABIChangeKind classifies a difference between two ABIs of a contract.
+type ABIChangeKind string
+
+const (
+	ABIContractRemoved    ABIChangeKind = "contract-removed"    // The contract no longer exists
+	ABIContractAdded      ABIChangeKind = "contract-added"      // A new contract
+	ABIFunctionRemoved    ABIChangeKind = "function-removed"    // The function no longer exists
+	ABIFunctionAdded      ABIChangeKind = "function-added"      // A new function
+	ABIFunctionRenamed    ABIChangeKind = "function-renamed"    // A function with the same parameter types has another name, its selector changed
+	ABIParametersChanged  ABIChangeKind = "parameters-changed"  // The parameter types of a function changed, its selector changed
+	ABIParametersRenamed  ABIChangeKind = "parameters-renamed"  // Only the parameter names of a function, event or error changed
+	ABIOutputsChanged     ABIChangeKind = "outputs-changed"     // The return types of a function changed
+	ABIMutabilityChanged  ABIChangeKind = "mutability-changed"  // The state mutability of a function changed
+	ABIEventRemoved       ABIChangeKind = "event-removed"       // The event no longer exists
+	ABIEventAdded         ABIChangeKind = "event-added"         // A new event
+	ABIEventChanged       ABIChangeKind = "event-changed"       // The parameters, indexed flags or anonymity of an event changed, so its topics changed
+	ABIErrorRemoved       ABIChangeKind = "error-removed"       // The custom error no longer exists
+	ABIErrorAdded         ABIChangeKind = "error-added"         // A new custom error
+	ABIErrorChanged       ABIChangeKind = "error-changed"       // The parameter types of a custom error changed, its selector changed
+	ABIConstructorChanged ABIChangeKind = "constructor-changed" // The constructor parameters changed, which only affects deployments
+	ABIFallbackRemoved    ABIChangeKind = "fallback-removed"    // The fallback or receive function no longer exists
+	ABIFallbackAdded      ABIChangeKind = "fallback-added"      // A new fallback or receive function
+)
+
+// ABIChange is a difference between the ABIs of two versions of a contract.
+type ABIChange struct {
+	Kind     ABIChangeKind
+	Contract string    // Fully qualified name of the contract
+	Old      *ABIEntry // Entry of the old ABI, nil if added
+	New      *ABIEntry // Matching entry of the new ABI, nil if removed
+	Message  string
+}
+
+// Breaking reports whether the change breaks callers or indexers built against the old ABI. Additions,
+// parameter renames and constructor changes are not breaking, nor are state mutability changes that only
+// restrict what the function does (e.g. nonpayable to view) or accept ether.
+func (c *ABIChange) Breaking() bool {
+	switch c.Kind {
+	case ABIContractAdded, ABIFunctionAdded, ABIParametersRenamed, ABIEventAdded, ABIErrorAdded, ABIConstructorChanged, ABIFallbackAdded:
+		return false
+	case ABIMutabilityChanged:
+		if c.Old.StateMutability == "nonpayable" && c.New.StateMutability == "payable" {
+			return false
+		}
+		return stateMutabilityRank[c.New.StateMutability] > stateMutabilityRank[c.Old.StateMutability] ||
+			c.Old.StateMutability == "payable"
+	}
+	return true
+}
+
+// String returns the change in the "kind: message" format, prefixed with "breaking" for breaking changes.
+func (c *ABIChange) String() string {
+	if c.Breaking() {
+		return "breaking " + string(c.Kind) + ": " + c.Message
+	}
+	return string(c.Kind) + ": " + c.Message
+}
+
+// stateMutabilityRank orders state mutabilities from the most to the least restrictive.
+var stateMutabilityRank = map[string]int{"pure": 0, "view": 1, "nonpayable": 2, "payable": 3}
+
+// CompareABIs returns the differences between two ABIs of a contract. Functions are matched by signature,
+// events by signature and indexed flags, and errors by signature; an unmatched function is reported as
+// having changed parameters if a single unmatched function of the new ABI has its name, and as renamed if one
+// has its parameter and return types.
+func CompareABIs(contract string, oldABI, newABI *ABI) []*ABIChange {
+	d := &abiDiff{contract: contract}
+	d.compareFunctions(oldABI.Functions, newABI.Functions)
+	d.compareEvents(oldABI.Events, newABI.Events)
+	d.compareErrors(oldABI.Errors, newABI.Errors)
+
+	if oldABI.Constructor != nil || newABI.Constructor != nil {
+		oldConstructor, newConstructor := oldABI.Constructor, newABI.Constructor
+		if entrySignature(oldConstructor) != entrySignature(newConstructor) {
+			d.add(ABIConstructorChanged, oldConstructor, newConstructor, fmt.Sprintf("constructor%s is now constructor%s",
+				strings.TrimPrefix(entrySignature(oldConstructor), "constructor"), strings.TrimPrefix(entrySignature(newConstructor), "constructor")))
+		}
+	}
+	for _, kind := range []string{"fallback", "receive"} {
+		oldEntry, newEntry := oldABI.Fallback, newABI.Fallback
+		if kind == "receive" {
+			oldEntry, newEntry = oldABI.Receive, newABI.Receive
+		}
+		switch {
+		case oldEntry != nil && newEntry == nil:
+			d.add(ABIFallbackRemoved, oldEntry, nil, kind+" function removed")
+		case oldEntry == nil && newEntry != nil:
+			d.add(ABIFallbackAdded, nil, newEntry, kind+" function added")
+		case oldEntry != nil && oldEntry.StateMutability != newEntry.StateMutability:
+			d.add(ABIMutabilityChanged, oldEntry, newEntry, fmt.Sprintf("%s function changed from %s to %s", kind, oldEntry.StateMutability, newEntry.StateMutability))
+		}
+	}
+	return d.changes
+}
+
+// CompareOutputABIs compares the ABIs of the contracts of two compiler outputs, e.g. the builds of two
+// git revisions or two artifact directories (see ReadArtifacts). Contracts are matched by fully qualified
+// name, or by bare name if it is unique in both outputs, so that moved files are still compared.
+func CompareOutputABIs(oldOutput, newOutput CompilerOutput) ([]*ABIChange, error) {
+	var changes []*ABIChange
+	matched := map[string]bool{}
+	for _, oldName := range oldOutput.FullyQualifiedNames() {
+		newName, ok := matchContractName(oldName, oldOutput, newOutput)
+		if !ok {
+			changes = append(changes, &ABIChange{Kind: ABIContractRemoved, Contract: oldName, Message: fmt.Sprintf("contract %s removed", oldName)})
+			continue
+		}
+		matched[newName] = true
+
+		oldABI, err := oldOutput.ContractABI(oldName)
+		if err != nil {
+			return nil, err
+		}
+		newABI, err := newOutput.ContractABI(newName)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, CompareABIs(newName, oldABI, newABI)...)
+	}
+	for _, newName := range newOutput.FullyQualifiedNames() {
+		if !matched[newName] {
+			changes = append(changes, &ABIChange{Kind: ABIContractAdded, Contract: newName, Message: fmt.Sprintf("contract %s added", newName)})
+		}
+	}
+	return changes, nil
+}
+
+// matchContractName returns the name of the contract of the new output matching a contract of the old output.
+func matchContractName(oldName string, oldOutput, newOutput CompilerOutput) (string, bool) {
+	if _, err := newOutput.Contract(oldName); err == nil {
+		return oldName, true
+	}
+	_, name := splitFullyQualifiedName(oldName)
+	if _, _, err := oldOutput.resolveContract(name); err != nil {
+		return "", false
+	}
+	newName, _, err := newOutput.resolveContract(name)
+	if err != nil {
+		return "", false
+	}
+	// a contract of the new output under the old name is compared to that one
+	if _, err := oldOutput.Contract(newName); err == nil {
+		return "", false
+	}
+	return newName, true
+}
+
+// abiDiff collects the changes of CompareABIs.
+type abiDiff struct {
+	contract string
+	changes  []*ABIChange
+}
+
+func (d *abiDiff) add(kind ABIChangeKind, oldEntry, newEntry *ABIEntry, message string) {
+	d.changes = append(d.changes, &ABIChange{Kind: kind, Contract: d.contract, Old: oldEntry, New: newEntry, Message: message})
+}
+
+func (d *abiDiff) compareFunctions(oldFunctions, newFunctions []*ABIEntry) {
+	removed, added := d.matchEntries(oldFunctions, newFunctions, entrySignature, func(oldFunction, newFunction *ABIEntry) {
+		if canonicalTypes(oldFunction.Outputs) != canonicalTypes(newFunction.Outputs) {
+			d.add(ABIOutputsChanged, oldFunction, newFunction, fmt.Sprintf("%s returns (%s) instead of (%s)",
+				oldFunction.Signature(), canonicalTypes(newFunction.Outputs), canonicalTypes(oldFunction.Outputs)))
+		}
+		if oldFunction.StateMutability != newFunction.StateMutability {
+			d.add(ABIMutabilityChanged, oldFunction, newFunction, fmt.Sprintf("%s changed from %s to %s",
+				oldFunction.Signature(), oldFunction.StateMutability, newFunction.StateMutability))
+		}
+		d.compareParameterNames(oldFunction, newFunction)
+	})
+
+	for _, oldFunction := range removed {
+		newFunction := uniqueEntry(added, func(entry *ABIEntry) bool { return entry.Name == oldFunction.Name })
+		kind := ABIParametersChanged
+		if newFunction == nil {
+			newFunction = uniqueEntry(added, func(entry *ABIEntry) bool {
+				return canonicalTypes(entry.Inputs) == canonicalTypes(oldFunction.Inputs) &&
+					canonicalTypes(entry.Outputs) == canonicalTypes(oldFunction.Outputs)
+			})
+			kind = ABIFunctionRenamed
+		}
+		if newFunction == nil {
+			d.add(ABIFunctionRemoved, oldFunction, nil, fmt.Sprintf("%s (%s) removed", oldFunction.Signature(), selectorHex(oldFunction)))
+			continue
+		}
+		added = removeEntry(added, newFunction)
+		d.add(kind, oldFunction, newFunction, fmt.Sprintf("%s is now %s, selector %s changed to %s",
+			oldFunction.Signature(), newFunction.Signature(), selectorHex(oldFunction), selectorHex(newFunction)))
+	}
+	for _, newFunction := range added {
+		d.add(ABIFunctionAdded, nil, newFunction, fmt.Sprintf("%s (%s) added", newFunction.Signature(), selectorHex(newFunction)))
+	}
+}
+
+func (d *abiDiff) compareEvents(oldEvents, newEvents []*ABIEntry) {
+	removed, added := d.matchEntries(oldEvents, newEvents, eventSignature, d.compareParameterNames)
+	for _, oldEvent := range removed {
+		newEvent := uniqueEntry(added, func(entry *ABIEntry) bool { return entry.Name == oldEvent.Name })
+		if newEvent == nil {
+			d.add(ABIEventRemoved, oldEvent, nil, fmt.Sprintf("event %s removed", eventSignature(oldEvent)))
+			continue
+		}
+		added = removeEntry(added, newEvent)
+		d.add(ABIEventChanged, oldEvent, newEvent, fmt.Sprintf("event %s is now %s", eventSignature(oldEvent), eventSignature(newEvent)))
+	}
+	for _, newEvent := range added {
+		d.add(ABIEventAdded, nil, newEvent, fmt.Sprintf("event %s added", eventSignature(newEvent)))
+	}
+}
+
+func (d *abiDiff) compareErrors(oldErrors, newErrors []*ABIEntry) {
+	removed, added := d.matchEntries(oldErrors, newErrors, entrySignature, d.compareParameterNames)
+	for _, oldError := range removed {
+		newError := uniqueEntry(added, func(entry *ABIEntry) bool { return entry.Name == oldError.Name })
+		if newError == nil {
+			d.add(ABIErrorRemoved, oldError, nil, fmt.Sprintf("error %s (%s) removed", oldError.Signature(), selectorHex(oldError)))
+			continue
+		}
+		added = removeEntry(added, newError)
+		d.add(ABIErrorChanged, oldError, newError, fmt.Sprintf("error %s is now %s, selector %s changed to %s",
+			oldError.Signature(), newError.Signature(), selectorHex(oldError), selectorHex(newError)))
+	}
+	for _, newError := range added {
+		d.add(ABIErrorAdded, nil, newError, fmt.Sprintf("error %s (%s) added", newError.Signature(), selectorHex(newError)))
+	}
+}
+
+// matchEntries calls compare for the entries of both ABIs with the same key and returns the unmatched ones.
+func (d *abiDiff) matchEntries(oldEntries, newEntries []*ABIEntry, key func(*ABIEntry) string, compare func(oldEntry, newEntry *ABIEntry)) (removed, added []*ABIEntry) {
+	byKey := make(map[string]*ABIEntry, len(newEntries))
+	for _, entry := range newEntries {
+		byKey[key(entry)] = entry
+	}
+	matched := map[*ABIEntry]bool{}
+	for _, oldEntry := range oldEntries {
+		newEntry, ok := byKey[key(oldEntry)]
+		if !ok {
+			removed = append(removed, oldEntry)
+			continue
+		}
+		matched[newEntry] = true
+		compare(oldEntry, newEntry)
+	}
+	for _, entry := range newEntries {
+		if !matched[entry] {
+			added = append(added, entry)
+		}
+	}
+	return removed, added
+}
+
+// compareParameterNames reports renamed input parameters of entries with the same signature.
+func (d *abiDiff) compareParameterNames(oldEntry, newEntry *ABIEntry) {
+	var oldNames, newNames []string
+	for i := range oldEntry.Inputs {
+		oldNames = append(oldNames, oldEntry.Inputs[i].Name)
+		newNames = append(newNames, newEntry.Inputs[i].Name)
+	}
+	if strings.Join(oldNames, ",") != strings.Join(newNames, ",") {
+		d.add(ABIParametersRenamed, oldEntry, newEntry, fmt.Sprintf("%s %s parameters renamed from (%s) to (%s)",
+			oldEntry.Type, oldEntry.Signature(), strings.Join(oldNames, ", "), strings.Join(newNames, ", ")))
+	}
+}
+
+// entrySignature returns the signature of an entry, "" for nil.
+func entrySignature(entry *ABIEntry) string {
+	if entry == nil {
+		return ""
+	}
+	if entry.Type == "constructor" {
+		return "constructor(" + canonicalTypes(entry.Inputs) + ")"
+	}
+	return entry.Signature()
+}
+
+// eventSignature returns the signature of an event with its indexed parameters and anonymity, which
+// determine its topics.
+func eventSignature(event *ABIEntry) string {
+	params := make([]string, len(event.Inputs))
+	for i, input := range event.Inputs {
+		params[i] = canonicalType(input)
+		if input.Indexed {
+			params[i] += " indexed"
+		}
+	}
+	s := event.Name + "(" + strings.Join(params, ",") + ")"
+	if event.Anonymous {
+		s += " anonymous"
+	}
+	return s
+}
+
+// canonicalTypes returns the comma separated canonical types of parameters.
+func canonicalTypes(params []ABIParameter) string {
+	types := make([]string, len(params))
+	for i, param := range params {
+		types[i] = canonicalType(param)
+	}
+	return strings.Join(types, ",")
+}
+
+func canonicalType(param ABIParameter) string {
+	t, err := param.ABIType()
+	if err != nil {
+		return param.Type
+	}
+	return t.String()
+}
+
+// selectorHex returns the 0x prefixed selector of a function or error.
+func selectorHex(entry *ABIEntry) string {
+	selector := entry.Selector()
+	return "0x" + hex.EncodeToString(selector[:])
+}
+
+// uniqueEntry returns the single entry matching f, nil if none or several do.
+func uniqueEntry(entries []*ABIEntry, f func(*ABIEntry) bool) *ABIEntry {
+	var found *ABIEntry
+	for _, entry := range entries {
+		if f(entry) {
+			if found != nil {
+				return nil
+			}
+			found = entry
+		}
+	}
+	return found
+}
+
+func removeEntry(entries []*ABIEntry, entry *ABIEntry) []*ABIEntry {
+	for i, e := range entries {
+		if e == entry {
+			return append(entries[:i:i], entries[i+1:]...)
+		}
+	}
+	return entries
+}
+
+// SemverBump is the version increment recommended for a set of ABI changes.
+type SemverBump string
+
+const (
+	SemverMajor SemverBump = "major" // Breaking changes
+	SemverMinor SemverBump = "minor" // Backwards compatible additions
+	SemverPatch SemverBump = "patch" // No ABI changes, or only renamed parameters
+)
+
+// RecommendSemverBump returns the version increment for ABI changes: major if any change is breaking, minor
+// if anything was added or changed compatibly, patch otherwise.
+func RecommendSemverBump(changes []*ABIChange) SemverBump {
+	bump := SemverPatch
+	for _, change := range changes {
+		if change.Breaking() {
+			return SemverMajor
+		}
+		if change.Kind != ABIParametersRenamed {
+			bump = SemverMinor
+		}
+	}
+	return bump
+}
+
+// Next returns the version following a "major.minor.patch" version (optionally prefixed with "v"). For 0.x
+// versions, breaking changes bump the minor version and other changes the patch version.
+func (b SemverBump) Next(version string) (string, error) {
+	prefix := ""
+	if strings.HasPrefix(version, "v") {
+		prefix, version = "v", version[1:]
+	}
+	parts := strings.Split(version, ".")
+	if len(parts) != 3 {
+		return "", fmt.Errorf("invalid version %q, expected major.minor.patch", version)
+	}
+	var numbers [3]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return "", fmt.Errorf("invalid version %q", version)
+		}
+		numbers[i] = n
+	}
+
+	index := map[SemverBump]int{SemverMajor: 0, SemverMinor: 1, SemverPatch: 2}[b]
+	if numbers[0] == 0 && index < 2 {
+		index++
+	}
+	numbers[index]++
+	for i := index + 1; i < 3; i++ {
+		numbers[i] = 0
+	}
+	return fmt.Sprintf("%s%d.%d.%d", prefix, numbers[0], numbers[1], numbers[2]), nil
+}
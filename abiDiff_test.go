@@ -0,0 +1,139 @@
+package gosolc
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCompareABIs(t *testing.T) {
+	oldABI, err := ParseABI([]byte(`[
+		{"type": "constructor", "inputs": [{"name": "supply", "type": "uint256"}], "stateMutability": "nonpayable"},
+		{"type": "function", "name": "transfer", "inputs": [{"name": "to", "type": "address"}, {"name": "amount", "type": "uint256"}], "outputs": [{"name": "", "type": "bool"}], "stateMutability": "nonpayable"},
+		{"type": "function", "name": "burn", "inputs": [{"name": "amount", "type": "uint256"}], "outputs": [], "stateMutability": "nonpayable"},
+		{"type": "function", "name": "mint", "inputs": [{"name": "amount", "type": "uint256"}], "outputs": [], "stateMutability": "nonpayable"},
+		{"type": "function", "name": "owner", "inputs": [], "outputs": [{"name": "", "type": "address"}], "stateMutability": "view"},
+		{"type": "function", "name": "deposit", "inputs": [], "outputs": [], "stateMutability": "nonpayable"},
+		{"type": "function", "name": "total", "inputs": [], "outputs": [{"name": "", "type": "uint256"}], "stateMutability": "view"},
+		{"type": "function", "name": "version", "inputs": [], "outputs": [{"name": "", "type": "uint256"}], "stateMutability": "view"},
+		{"type": "event", "name": "Transfer", "inputs": [{"name": "from", "type": "address", "indexed": true}, {"name": "value", "type": "uint256", "indexed": false}], "anonymous": false},
+		{"type": "event", "name": "Paused", "inputs": [], "anonymous": false},
+		{"type": "error", "name": "Unauthorized", "inputs": [{"name": "account", "type": "address"}]},
+		{"type": "receive", "stateMutability": "payable"}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	newABI, err := ParseABI([]byte(`[
+		{"type": "constructor", "inputs": [], "stateMutability": "nonpayable"},
+		{"type": "function", "name": "transfer", "inputs": [{"name": "recipient", "type": "address"}, {"name": "amount", "type": "uint256"}], "outputs": [{"name": "", "type": "bool"}], "stateMutability": "nonpayable"},
+		{"type": "function", "name": "burn", "inputs": [{"name": "amount", "type": "uint128"}], "outputs": [], "stateMutability": "nonpayable"},
+		{"type": "function", "name": "issue", "inputs": [{"name": "amount", "type": "uint256"}], "outputs": [], "stateMutability": "nonpayable"},
+		{"type": "function", "name": "owner", "inputs": [], "outputs": [{"name": "", "type": "address"}], "stateMutability": "pure"},
+		{"type": "function", "name": "deposit", "inputs": [], "outputs": [], "stateMutability": "payable"},
+		{"type": "function", "name": "total", "inputs": [], "outputs": [{"name": "", "type": "uint128"}], "stateMutability": "view"},
+		{"type": "function", "name": "paused", "inputs": [], "outputs": [{"name": "", "type": "bool"}], "stateMutability": "view"},
+		{"type": "event", "name": "Transfer", "inputs": [{"name": "from", "type": "address", "indexed": true}, {"name": "value", "type": "uint256", "indexed": true}], "anonymous": false},
+		{"type": "error", "name": "Unauthorized", "inputs": [{"name": "account", "type": "address"}, {"name": "role", "type": "bytes32"}]},
+		{"type": "error", "name": "Paused", "inputs": []}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changes := CompareABIs("Token.sol:Token", oldABI, newABI)
+	var kinds []string
+	for _, change := range changes {
+		kinds = append(kinds, fmt.Sprintf("%s %v", change.Kind, change.Breaking()))
+	}
+	expected := []string{
+		"parameters-renamed false",  // transfer(to, amount)
+		"mutability-changed false",  // owner: view to pure
+		"mutability-changed false",  // deposit: nonpayable to payable
+		"outputs-changed true",      // total returns uint128
+		"parameters-changed true",   // burn(uint128)
+		"function-renamed true",     // mint to issue
+		"function-removed true",     // version
+		"function-added false",      // paused
+		"event-changed true",        // Transfer value indexed
+		"event-removed true",        // Paused
+		"error-changed true",        // Unauthorized(address,bytes32)
+		"error-added false",         // Paused
+		"constructor-changed false", // constructor()
+		"fallback-removed true",     // receive
+	}
+	if fmt.Sprint(kinds) != fmt.Sprint(expected) {
+		t.Errorf("expected %v, got:\n%v", expected, changes)
+	}
+	if changes[4].Message != "burn(uint256) is now burn(uint128), selector 0x42966c68 changed to 0x90bc1693" {
+		t.Errorf("unexpected message %q", changes[4].Message)
+	}
+
+	if bump := RecommendSemverBump(changes); bump != SemverMajor {
+		t.Errorf("expected a major bump, got %s", bump)
+	}
+	if bump := RecommendSemverBump(changes[:3]); bump != SemverMinor {
+		t.Errorf("expected a minor bump, got %s", bump)
+	}
+	if bump := RecommendSemverBump(changes[:1]); bump != SemverPatch {
+		t.Errorf("expected a patch bump, got %s", bump)
+	}
+	for _, test := range []struct {
+		bump     SemverBump
+		version  string
+		expected string
+	}{
+		{SemverMajor, "v1.4.2", "v2.0.0"},
+		{SemverMinor, "1.4.2", "1.5.0"},
+		{SemverPatch, "1.4.2", "1.4.3"},
+		{SemverMajor, "0.3.1", "0.4.0"},
+	} {
+		if next, err := test.bump.Next(test.version); err != nil || next != test.expected {
+			t.Errorf("%s bump of %s: expected %s, got %s (%v)", test.bump, test.version, test.expected, next, err)
+		}
+	}
+}
+
+func TestCompareOutputABIs(t *testing.T) {
+	output := loadTestOutput(t, "testdata_output.json")
+
+	// write the artifacts, without metadata, and read them back
+	dir := t.TempDir()
+	for _, name := range []string{"ERC20", "Token"} {
+		contract, err := output.Contract(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := json.Marshal(map[string]interface{}{"abi": contract["abi"]})
+		if err := os.WriteFile(filepath.Join(dir, name+".json"), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	artifacts, err := ReadArtifacts(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := artifacts.FullyQualifiedNames(); fmt.Sprint(names) != "[ERC20.json:ERC20 Token.json:Token]" {
+		t.Errorf("unexpected artifact names %v", names)
+	}
+
+	// contracts are matched by bare name
+	changes, err := CompareOutputABIs(artifacts, output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("expected no changes, got %v", changes)
+	}
+
+	delete(artifacts, "Token.json")
+	changes, err = CompareOutputABIs(output, artifacts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || changes[0].Kind != ABIContractRemoved || !changes[0].Breaking() {
+		t.Errorf("expected the removal of Token, got %v", changes)
+	}
+}
@@ -0,0 +1,147 @@
+package gosolc
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// loadTestOutput loads a compiler output fixture from testdata/output.
+func loadTestOutput(t *testing.T, name string) CompilerOutput {
+	t.Helper()
+	data, err := os.ReadFile("testdata/output/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var contracts CompilerOutput
+	if err := json.Unmarshal(data, &contracts); err != nil {
+		t.Fatal(err)
+	}
+	return contracts
+}
+
+func TestABIMethodIdentifiersParity(t *testing.T) {
+	contracts := loadTestOutput(t, "abi_output.json")
+
+	abi, err := contracts.ContractABI("Token.sol:Token")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, err := contracts.GetMethodIdentifiers("Token")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ids := abi.MethodIdentifiers(); !reflect.DeepEqual(ids, expected) {
+		t.Fatalf("method identifiers differ from solc:\nexpected %v\ngot      %v", expected, ids)
+	}
+}
+
+func TestABIEventTopicsAndErrorSelectors(t *testing.T) {
+	abi, err := loadTestOutput(t, "abi_output.json").ContractABI("Token")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	topics := map[string]string{
+		"Transfer":      "ddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef",
+		"Approval":      "8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925",
+		"TransferBatch": "4a39dc06d4c0dbc64b70af90fd698a233a518aa5d07e595d983b8c0526c8f7fb",
+	}
+	for name, expected := range topics {
+		event, err := abi.Event(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if topic := event.Topic(); hex.EncodeToString(topic[:]) != expected {
+			t.Errorf("expected topic of %s to be %s, got %x", event.Signature(), expected, topic)
+		}
+	}
+
+	abiError, err := abi.Error("ERC20InsufficientBalance")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if selector := abiError.Selector(); hex.EncodeToString(selector[:]) != "e450d38c" {
+		t.Errorf("unexpected selector %x for %s", selector, abiError.Signature())
+	}
+
+	if _, err := abi.Function("safeTransferFrom"); err == nil {
+		t.Error("expected an error when looking up an overloaded function by name")
+	}
+	if abi.Constructor == nil || abi.Fallback == nil || abi.Receive == nil {
+		t.Error("expected constructor, fallback and receive entries")
+	}
+}
+
+func TestABIJSONRoundTrip(t *testing.T) {
+	contract, err := loadTestOutput(t, "abi_output.json").Contract("Token")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	abi, err := contractABI(contract)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(abi)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Entries are grouped by type, so compare them regardless of order
+	var decoded []interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	original := contract["abi"].([]interface{})
+	if len(decoded) != len(original) {
+		t.Fatalf("expected %d entries after round trip, got %d", len(original), len(decoded))
+	}
+	for _, entry := range decoded {
+		found := false
+		for _, o := range original {
+			if reflect.DeepEqual(entry, o) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("entry changed after round trip: %v", entry)
+		}
+	}
+}
+
+func TestE2EABIParity(t *testing.T) {
+	if solcJS_0_8_29 == "" {
+		t.Skip("embedded soljson is not available")
+	}
+
+	c, err := NewDefaultCompiler("testdata/contracts")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	compiled, err := c.Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, fqName := range compiled.FullyQualifiedNames() {
+		abi, err := compiled.ContractABI(fqName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected, err := compiled.GetMethodIdentifiers(fqName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ids := abi.MethodIdentifiers(); !reflect.DeepEqual(ids, expected) {
+			t.Errorf("method identifiers of %s differ from solc: expected %v, got %v", fqName, expected, ids)
+		}
+	}
+}
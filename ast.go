@@ -0,0 +1,667 @@
+package gosolc
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Node is a node of solc's compact JSON AST. Nodes are decoded into the typed structs below according
+// to their nodeType; node types without a struct are decoded as *UnknownNode.
+type Node interface {
+	Base() *NodeBase
+}
+
+// NodeBase holds the attributes common to all AST nodes.
+type NodeBase struct {
+	ID       int64  `json:"id"`
+	NodeType string `json:"nodeType"`
+	Src      string `json:"src"` // "start:length:sourceId", see StandardOutput.Location
+}
+
+// Base returns the common attributes of the node.
+func (n *NodeBase) Base() *NodeBase {
+	return n
+}
+
+// TypeDescriptions describe the type of an expression or declaration.
+type TypeDescriptions struct {
+	TypeIdentifier string `json:"typeIdentifier"`
+	TypeString     string `json:"typeString"`
+}
+
+// ExpressionBase holds the attributes common to all expressions.
+type ExpressionBase struct {
+	NodeBase
+	TypeDescriptions TypeDescriptions   `json:"typeDescriptions"`
+	ArgumentTypes    []TypeDescriptions `json:"argumentTypes"`
+	IsConstant       bool               `json:"isConstant"`
+	IsLValue         bool               `json:"isLValue"`
+	IsPure           bool               `json:"isPure"`
+	LValueRequested  bool               `json:"lValueRequested"`
+}
+
+// UnknownNode is a node whose type has no typed model; Raw holds its JSON. Its children are not walked.
+type UnknownNode struct {
+	NodeBase
+	Raw json.RawMessage `json:"-"`
+}
+
+// Source units and directives
+
+// SourceUnit is the SourceUnit node at the root of the AST of a source file.
+type SourceUnit struct {
+	NodeBase
+	AbsolutePath    string             `json:"absolutePath"`
+	ExportedSymbols map[string][]int64 `json:"exportedSymbols"`
+	License         string             `json:"license"`
+	Nodes           []Node             `json:"nodes"`
+}
+
+// PragmaDirective is a PragmaDirective node, e.g. `pragma solidity ^0.8.0;`.
+type PragmaDirective struct {
+	NodeBase
+	Literals []string `json:"literals"`
+}
+
+// ImportDirective is an ImportDirective node.
+type ImportDirective struct {
+	NodeBase
+	AbsolutePath  string          `json:"absolutePath"`
+	File          string          `json:"file"`
+	Scope         int64           `json:"scope"`
+	SourceUnit    int64           `json:"sourceUnit"`
+	UnitAlias     string          `json:"unitAlias"`
+	SymbolAliases json.RawMessage `json:"symbolAliases"`
+}
+
+// UsingForDirective is a UsingForDirective node, e.g. `using SafeERC20 for IERC20;`.
+type UsingForDirective struct {
+	NodeBase
+	LibraryName  Node            `json:"libraryName"`
+	TypeName     Node            `json:"typeName"`
+	FunctionList json.RawMessage `json:"functionList"`
+	Global       bool            `json:"global"`
+}
+
+// StructuredDocumentation is a StructuredDocumentation node holding a NatSpec comment.
+type StructuredDocumentation struct {
+	NodeBase
+	Text string `json:"text"`
+}
+
+// Declarations
+
+// ContractDefinition is a ContractDefinition node: a contract, interface or library.
+type ContractDefinition struct {
+	NodeBase
+	Name                    string                   `json:"name"`
+	ContractKind            string                   `json:"contractKind"` // "contract", "interface" or "library"
+	Abstract                bool                     `json:"abstract"`
+	FullyImplemented        bool                     `json:"fullyImplemented"`
+	LinearizedBaseContracts []int64                  `json:"linearizedBaseContracts"`
+	Scope                   int64                    `json:"scope"`
+	Documentation           *StructuredDocumentation `json:"documentation"`
+	BaseContracts           []*InheritanceSpecifier  `json:"baseContracts"`
+	Nodes                   []Node                   `json:"nodes"`
+}
+
+// InheritanceSpecifier is an InheritanceSpecifier node, a base of a contract with its constructor arguments.
+type InheritanceSpecifier struct {
+	NodeBase
+	BaseName  Node   `json:"baseName"`
+	Arguments []Node `json:"arguments"`
+}
+
+// StructDefinition is a StructDefinition node.
+type StructDefinition struct {
+	NodeBase
+	Name          string                   `json:"name"`
+	CanonicalName string                   `json:"canonicalName"`
+	Visibility    string                   `json:"visibility"`
+	Scope         int64                    `json:"scope"`
+	Documentation *StructuredDocumentation `json:"documentation"`
+	Members       []*VariableDeclaration   `json:"members"`
+}
+
+// EnumDefinition is an EnumDefinition node.
+type EnumDefinition struct {
+	NodeBase
+	Name          string                   `json:"name"`
+	CanonicalName string                   `json:"canonicalName"`
+	Documentation *StructuredDocumentation `json:"documentation"`
+	Members       []*EnumValue             `json:"members"`
+}
+
+// EnumValue is an EnumValue node, a member of an enum.
+type EnumValue struct {
+	NodeBase
+	Name string `json:"name"`
+}
+
+// UserDefinedValueTypeDefinition is a UserDefinedValueTypeDefinition node, e.g. `type Price is uint128;`.
+type UserDefinedValueTypeDefinition struct {
+	NodeBase
+	Name           string `json:"name"`
+	CanonicalName  string `json:"canonicalName"`
+	UnderlyingType Node   `json:"underlyingType"`
+}
+
+// ErrorDefinition is an ErrorDefinition node, a custom error.
+type ErrorDefinition struct {
+	NodeBase
+	Name          string                   `json:"name"`
+	ErrorSelector string                   `json:"errorSelector"`
+	Documentation *StructuredDocumentation `json:"documentation"`
+	Parameters    *ParameterList           `json:"parameters"`
+}
+
+// EventDefinition is an EventDefinition node.
+type EventDefinition struct {
+	NodeBase
+	Name          string                   `json:"name"`
+	Anonymous     bool                     `json:"anonymous"`
+	EventSelector string                   `json:"eventSelector"`
+	Documentation *StructuredDocumentation `json:"documentation"`
+	Parameters    *ParameterList           `json:"parameters"`
+}
+
+// FunctionDefinition is a FunctionDefinition node, including constructors, fallback and receive functions.
+type FunctionDefinition struct {
+	NodeBase
+	Name             string                   `json:"name"`
+	Kind             string                   `json:"kind"` // "function", "constructor", "fallback", "receive" or "freeFunction"
+	StateMutability  string                   `json:"stateMutability"`
+	Visibility       string                   `json:"visibility"`
+	Virtual          bool                     `json:"virtual"`
+	Implemented      bool                     `json:"implemented"`
+	FunctionSelector string                   `json:"functionSelector"`
+	BaseFunctions    []int64                  `json:"baseFunctions"`
+	Scope            int64                    `json:"scope"`
+	Documentation    *StructuredDocumentation `json:"documentation"`
+	Overrides        *OverrideSpecifier       `json:"overrides"`
+	Parameters       *ParameterList           `json:"parameters"`
+	ReturnParameters *ParameterList           `json:"returnParameters"`
+	Modifiers        []*ModifierInvocation    `json:"modifiers"`
+	Body             *Block                   `json:"body"`
+}
+
+// ModifierDefinition is a ModifierDefinition node.
+type ModifierDefinition struct {
+	NodeBase
+	Name          string                   `json:"name"`
+	Visibility    string                   `json:"visibility"`
+	Virtual       bool                     `json:"virtual"`
+	BaseModifiers []int64                  `json:"baseModifiers"`
+	Documentation *StructuredDocumentation `json:"documentation"`
+	Overrides     *OverrideSpecifier       `json:"overrides"`
+	Parameters    *ParameterList           `json:"parameters"`
+	Body          *Block                   `json:"body"`
+}
+
+// ModifierInvocation is a ModifierInvocation node, a modifier or base constructor call of a function.
+type ModifierInvocation struct {
+	NodeBase
+	Kind         string `json:"kind"` // "modifierInvocation" or "baseConstructorSpecifier"
+	ModifierName Node   `json:"modifierName"`
+	Arguments    []Node `json:"arguments"`
+}
+
+// OverrideSpecifier is an OverrideSpecifier node, the `override` of a function, modifier or state variable.
+type OverrideSpecifier struct {
+	NodeBase
+	Overrides []Node `json:"overrides"`
+}
+
+// ParameterList is a ParameterList node, the parameters or return values of a function, event or error.
+type ParameterList struct {
+	NodeBase
+	Parameters []*VariableDeclaration `json:"parameters"`
+}
+
+// VariableDeclaration is a VariableDeclaration node: a state or local variable, parameter or struct member.
+type VariableDeclaration struct {
+	NodeBase
+	Name             string                   `json:"name"`
+	Constant         bool                     `json:"constant"`
+	Mutability       string                   `json:"mutability"` // "mutable", "immutable" or "constant"
+	StateVariable    bool                     `json:"stateVariable"`
+	StorageLocation  string                   `json:"storageLocation"`
+	Visibility       string                   `json:"visibility"`
+	Indexed          bool                     `json:"indexed"`
+	FunctionSelector string                   `json:"functionSelector"`
+	Scope            int64                    `json:"scope"`
+	TypeDescriptions TypeDescriptions         `json:"typeDescriptions"`
+	Documentation    *StructuredDocumentation `json:"documentation"`
+	Overrides        *OverrideSpecifier       `json:"overrides"`
+	TypeName         Node                     `json:"typeName"`
+	Value            Node                     `json:"value"`
+}
+
+// Type names
+
+// ElementaryTypeName is an ElementaryTypeName node, e.g. `uint256` or `address payable`.
+type ElementaryTypeName struct {
+	NodeBase
+	Name             string           `json:"name"`
+	StateMutability  string           `json:"stateMutability"`
+	TypeDescriptions TypeDescriptions `json:"typeDescriptions"`
+}
+
+// UserDefinedTypeName is a UserDefinedTypeName node naming a contract, struct, enum or value type.
+type UserDefinedTypeName struct {
+	NodeBase
+	Name                  string           `json:"name"`
+	ReferencedDeclaration int64            `json:"referencedDeclaration"`
+	TypeDescriptions      TypeDescriptions `json:"typeDescriptions"`
+	PathNode              *IdentifierPath  `json:"pathNode"`
+}
+
+// IdentifierPath is an IdentifierPath node, a possibly qualified name such as `IERC20.Approval`.
+type IdentifierPath struct {
+	NodeBase
+	Name                  string `json:"name"`
+	ReferencedDeclaration int64  `json:"referencedDeclaration"`
+}
+
+// Mapping is a Mapping type name node.
+type Mapping struct {
+	NodeBase
+	KeyName          string           `json:"keyName"`
+	ValueName        string           `json:"valueName"`
+	TypeDescriptions TypeDescriptions `json:"typeDescriptions"`
+	KeyType          Node             `json:"keyType"`
+	ValueType        Node             `json:"valueType"`
+}
+
+// ArrayTypeName is an ArrayTypeName node.
+type ArrayTypeName struct {
+	NodeBase
+	TypeDescriptions TypeDescriptions `json:"typeDescriptions"`
+	BaseType         Node             `json:"baseType"`
+	Length           Node             `json:"length"`
+}
+
+// FunctionTypeName is a FunctionTypeName node, the type of function pointers.
+type FunctionTypeName struct {
+	NodeBase
+	Visibility           string           `json:"visibility"`
+	StateMutability      string           `json:"stateMutability"`
+	TypeDescriptions     TypeDescriptions `json:"typeDescriptions"`
+	ParameterTypes       *ParameterList   `json:"parameterTypes"`
+	ReturnParameterTypes *ParameterList   `json:"returnParameterTypes"`
+}
+
+// Statements
+
+// Block is a Block statement node.
+type Block struct {
+	NodeBase
+	Statements []Node `json:"statements"`
+}
+
+// UncheckedBlock is an UncheckedBlock statement node.
+type UncheckedBlock struct {
+	NodeBase
+	Statements []Node `json:"statements"`
+}
+
+// ExpressionStatement is an ExpressionStatement node.
+type ExpressionStatement struct {
+	NodeBase
+	Expression Node `json:"expression"`
+}
+
+// VariableDeclarationStatement is a VariableDeclarationStatement node declaring local variables.
+type VariableDeclarationStatement struct {
+	NodeBase
+	Assignments  []*int64               `json:"assignments"`
+	Declarations []*VariableDeclaration `json:"declarations"` // nil for skipped tuple components
+	InitialValue Node                   `json:"initialValue"`
+}
+
+// IfStatement is an IfStatement node.
+type IfStatement struct {
+	NodeBase
+	Condition Node `json:"condition"`
+	TrueBody  Node `json:"trueBody"`
+	FalseBody Node `json:"falseBody"`
+}
+
+// ForStatement is a ForStatement node.
+type ForStatement struct {
+	NodeBase
+	InitializationExpression Node `json:"initializationExpression"`
+	Condition                Node `json:"condition"`
+	LoopExpression           Node `json:"loopExpression"`
+	Body                     Node `json:"body"`
+}
+
+// WhileStatement is a WhileStatement node.
+type WhileStatement struct {
+	NodeBase
+	Condition Node `json:"condition"`
+	Body      Node `json:"body"`
+}
+
+// DoWhileStatement is a DoWhileStatement node.
+type DoWhileStatement struct {
+	NodeBase
+	Body      Node `json:"body"`
+	Condition Node `json:"condition"`
+}
+
+// Return is a Return statement node.
+type Return struct {
+	NodeBase
+	FunctionReturnParameters int64 `json:"functionReturnParameters"`
+	Expression               Node  `json:"expression"`
+}
+
+// EmitStatement is an EmitStatement node.
+type EmitStatement struct {
+	NodeBase
+	EventCall Node `json:"eventCall"`
+}
+
+// RevertStatement is a RevertStatement node reverting with a custom error.
+type RevertStatement struct {
+	NodeBase
+	ErrorCall Node `json:"errorCall"`
+}
+
+// TryStatement is a TryStatement node.
+type TryStatement struct {
+	NodeBase
+	ExternalCall Node              `json:"externalCall"`
+	Clauses      []*TryCatchClause `json:"clauses"`
+}
+
+// TryCatchClause is a TryCatchClause node of a try statement.
+type TryCatchClause struct {
+	NodeBase
+	ErrorName  string         `json:"errorName"`
+	Parameters *ParameterList `json:"parameters"`
+	Block      *Block         `json:"block"`
+}
+
+// Break is a Break statement node.
+type Break struct {
+	NodeBase
+}
+
+// Continue is a Continue statement node.
+type Continue struct {
+	NodeBase
+}
+
+// PlaceholderStatement is a PlaceholderStatement node, the `_` of a modifier.
+type PlaceholderStatement struct {
+	NodeBase
+}
+
+// InlineAssembly is an assembly block; its Yul AST is kept as JSON.
+type InlineAssembly struct {
+	NodeBase
+	EVMVersion         string          `json:"evmVersion"`
+	Flags              []string        `json:"flags"`
+	AST                json.RawMessage `json:"AST"`
+	ExternalReferences json.RawMessage `json:"externalReferences"`
+}
+
+// Expressions
+
+// Assignment is an Assignment expression node, e.g. `a += b`.
+type Assignment struct {
+	ExpressionBase
+	Operator      string `json:"operator"`
+	LeftHandSide  Node   `json:"leftHandSide"`
+	RightHandSide Node   `json:"rightHandSide"`
+}
+
+// BinaryOperation is a BinaryOperation expression node.
+type BinaryOperation struct {
+	ExpressionBase
+	Operator        string           `json:"operator"`
+	CommonType      TypeDescriptions `json:"commonType"`
+	LeftExpression  Node             `json:"leftExpression"`
+	RightExpression Node             `json:"rightExpression"`
+}
+
+// UnaryOperation is a UnaryOperation expression node, e.g. `!a` or `i++`.
+type UnaryOperation struct {
+	ExpressionBase
+	Operator      string `json:"operator"`
+	Prefix        bool   `json:"prefix"`
+	SubExpression Node   `json:"subExpression"`
+}
+
+// Conditional is a Conditional expression node, `a ? b : c`.
+type Conditional struct {
+	ExpressionBase
+	Condition       Node `json:"condition"`
+	TrueExpression  Node `json:"trueExpression"`
+	FalseExpression Node `json:"falseExpression"`
+}
+
+// FunctionCall is a FunctionCall expression node, also used for type conversions and struct constructors.
+type FunctionCall struct {
+	ExpressionBase
+	Kind       string   `json:"kind"` // "functionCall", "typeConversion" or "structConstructorCall"
+	TryCall    bool     `json:"tryCall"`
+	Names      []string `json:"names"`
+	Expression Node     `json:"expression"`
+	Arguments  []Node   `json:"arguments"`
+}
+
+// FunctionCallOptions is a FunctionCallOptions expression node, e.g. `f{value: 1}`.
+type FunctionCallOptions struct {
+	ExpressionBase
+	Names      []string `json:"names"`
+	Expression Node     `json:"expression"`
+	Options    []Node   `json:"options"`
+}
+
+// MemberAccess is a MemberAccess expression node, e.g. `msg.sender`.
+type MemberAccess struct {
+	ExpressionBase
+	MemberName            string `json:"memberName"`
+	ReferencedDeclaration *int64 `json:"referencedDeclaration"`
+	Expression            Node   `json:"expression"`
+}
+
+// IndexAccess is an IndexAccess expression node, e.g. `balances[owner]`.
+type IndexAccess struct {
+	ExpressionBase
+	BaseExpression  Node `json:"baseExpression"`
+	IndexExpression Node `json:"indexExpression"`
+}
+
+// IndexRangeAccess is an IndexRangeAccess expression node, a calldata slice such as `data[4:]`.
+type IndexRangeAccess struct {
+	ExpressionBase
+	BaseExpression  Node `json:"baseExpression"`
+	StartExpression Node `json:"startExpression"`
+	EndExpression   Node `json:"endExpression"`
+}
+
+// Identifier is an Identifier expression node.
+type Identifier struct {
+	ExpressionBase
+	Name                   string  `json:"name"`
+	ReferencedDeclaration  int64   `json:"referencedDeclaration"`
+	OverloadedDeclarations []int64 `json:"overloadedDeclarations"`
+}
+
+// Literal is a Literal expression node: a number, string, hex string or boolean.
+type Literal struct {
+	ExpressionBase
+	Kind            string `json:"kind"` // "bool", "number", "string", "hexString" or "unicodeString"
+	Value           string `json:"value"`
+	HexValue        string `json:"hexValue"`
+	Subdenomination string `json:"subdenomination"`
+}
+
+// TupleExpression is a TupleExpression node, also used for inline arrays.
+type TupleExpression struct {
+	ExpressionBase
+	IsInlineArray bool   `json:"isInlineArray"`
+	Components    []Node `json:"components"` // nil for omitted components
+}
+
+// ElementaryTypeNameExpression is an ElementaryTypeNameExpression node, an elementary type used in an expression, e.g. `address(0)`.
+type ElementaryTypeNameExpression struct {
+	ExpressionBase
+	TypeName *ElementaryTypeName `json:"typeName"`
+}
+
+// NewExpression is a NewExpression node, e.g. `new Token()`.
+type NewExpression struct {
+	ExpressionBase
+	TypeName Node `json:"typeName"`
+}
+
+// astNodeTypes maps nodeType values to their typed model.
+var astNodeTypes = map[string]reflect.Type{}
+
+func init() {
+	for _, node := range []Node{
+		&SourceUnit{}, &PragmaDirective{}, &ImportDirective{}, &UsingForDirective{}, &StructuredDocumentation{},
+		&ContractDefinition{}, &InheritanceSpecifier{}, &StructDefinition{}, &EnumDefinition{}, &EnumValue{},
+		&UserDefinedValueTypeDefinition{}, &ErrorDefinition{}, &EventDefinition{}, &FunctionDefinition{},
+		&ModifierDefinition{}, &ModifierInvocation{}, &OverrideSpecifier{}, &ParameterList{}, &VariableDeclaration{},
+		&ElementaryTypeName{}, &UserDefinedTypeName{}, &IdentifierPath{}, &Mapping{}, &ArrayTypeName{},
+		&FunctionTypeName{}, &Block{}, &UncheckedBlock{}, &ExpressionStatement{}, &VariableDeclarationStatement{},
+		&IfStatement{}, &ForStatement{}, &WhileStatement{}, &DoWhileStatement{}, &Return{}, &EmitStatement{},
+		&RevertStatement{}, &TryStatement{}, &TryCatchClause{}, &Break{}, &Continue{}, &PlaceholderStatement{},
+		&InlineAssembly{}, &Assignment{}, &BinaryOperation{}, &UnaryOperation{}, &Conditional{}, &FunctionCall{},
+		&FunctionCallOptions{}, &MemberAccess{}, &IndexAccess{}, &IndexRangeAccess{}, &Identifier{}, &Literal{},
+		&TupleExpression{}, &ElementaryTypeNameExpression{}, &NewExpression{},
+	} {
+		t := reflect.TypeOf(node).Elem()
+		astNodeTypes[t.Name()] = t
+	}
+}
+
+var nodeInterfaceType = reflect.TypeOf((*Node)(nil)).Elem()
+
+// DecodeAST decodes a compact JSON AST node, e.g. the "ast" of a source in the standard JSON output.
+func DecodeAST(data []byte) (Node, error) {
+	return decodeNode(data)
+}
+
+// decodeASTValue decodes an AST node from the untyped compiler output.
+func decodeASTValue(value interface{}) (Node, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return decodeNode(data)
+}
+
+// decodeNode decodes a node into its typed model. Null decodes to a nil Node.
+func decodeNode(data json.RawMessage) (Node, error) {
+	if isJSONNull(data) {
+		return nil, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("invalid AST node: %v", err)
+	}
+	var nodeType string
+	if err := json.Unmarshal(fields["nodeType"], &nodeType); err != nil {
+		return nil, fmt.Errorf("invalid AST node: missing nodeType")
+	}
+
+	t, ok := astNodeTypes[nodeType]
+	if !ok {
+		node := &UnknownNode{Raw: data}
+		if err := json.Unmarshal(data, &node.NodeBase); err != nil {
+			return nil, fmt.Errorf("invalid %s node: %v", nodeType, err)
+		}
+		return node, nil
+	}
+
+	node := reflect.New(t)
+	if err := decodeNodeFields(node.Elem(), fields); err != nil {
+		return nil, fmt.Errorf("invalid %s node: %v", nodeType, err)
+	}
+	return node.Interface().(Node), nil
+}
+
+// decodeNodeFields decodes the JSON fields of a node into the fields of its struct. Fields holding
+// nodes are decoded with decodeNode, the other fields with encoding/json.
+func decodeNodeFields(v reflect.Value, fields map[string]json.RawMessage) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous {
+			if err := decodeNodeFields(v.Field(i), fields); err != nil {
+				return err
+			}
+			continue
+		}
+
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		raw, ok := fields[name]
+		if name == "" || name == "-" || !ok {
+			continue
+		}
+
+		if err := decodeNodeField(v.Field(i), raw); err != nil {
+			return fmt.Errorf("field %s: %v", name, err)
+		}
+	}
+	return nil
+}
+
+func decodeNodeField(v reflect.Value, raw json.RawMessage) error {
+	switch {
+	case isNodeType(v.Type()):
+		node, err := decodeNode(raw)
+		if err != nil || node == nil {
+			return err
+		}
+		value := reflect.ValueOf(node)
+		if !value.Type().AssignableTo(v.Type()) {
+			return fmt.Errorf("expected %s, got %s", v.Type().Elem().Name(), node.Base().NodeType)
+		}
+		v.Set(value)
+		return nil
+	case v.Kind() == reflect.Slice && isNodeType(v.Type().Elem()):
+		var items []json.RawMessage
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return err
+		}
+		if items == nil {
+			return nil
+		}
+		slice := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := decodeNodeField(slice.Index(i), item); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+		return nil
+	}
+	return json.Unmarshal(raw, v.Addr().Interface())
+}
+
+// isNodeType reports whether a field type holds AST nodes: the Node interface or a pointer to a node struct.
+func isNodeType(t reflect.Type) bool {
+	if t == nodeInterfaceType {
+		return true
+	}
+	if t.Kind() != reflect.Pointer {
+		return false
+	}
+	_, ok := astNodeTypes[t.Elem().Name()]
+	return ok && astNodeTypes[t.Elem().Name()] == t.Elem()
+}
+
+func isJSONNull(data []byte) bool {
+	return len(data) == 0 || string(data) == "null"
+}
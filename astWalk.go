@@ -0,0 +1,90 @@
+package gosolc
+
+import "reflect"
+
+// Visitor is called by Walk for every node. If the returned visitor w is not nil, Walk visits the
+// children of the node with w, followed by a call of w.Visit(nil).
+type Visitor interface {
+	Visit(node Node) (w Visitor)
+}
+
+// Walk traverses an AST in depth-first order, like go/ast.Walk: it starts by calling v.Visit(node),
+// then walks the children of the node with the returned visitor, in source order.
+func Walk(v Visitor, node Node) {
+	if v = v.Visit(node); v == nil {
+		return
+	}
+	for _, child := range Children(node) {
+		Walk(v, child)
+	}
+	v.Visit(nil)
+}
+
+// inspector adapts a function to the Visitor interface.
+type inspector func(Node) bool
+
+func (f inspector) Visit(node Node) Visitor {
+	if f(node) {
+		return f
+	}
+	return nil
+}
+
+// Inspect traverses an AST in depth-first order, calling f for every node and f(nil) after the
+// children of a node. The children of a node are skipped if f returns false.
+func Inspect(node Node, f func(Node) bool) {
+	Walk(inspector(f), node)
+}
+
+// Children returns the direct children of a node, in the order of its fields. Nil children
+// (e.g. omitted tuple components) are skipped.
+func Children(node Node) []Node {
+	if node == nil || reflect.ValueOf(node).IsNil() {
+		return nil
+	}
+	var children []Node
+	collectChildren(reflect.ValueOf(node).Elem(), &children)
+	return children
+}
+
+func collectChildren(v reflect.Value, children *[]Node) {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		if v.Type().Field(i).Anonymous {
+			continue
+		}
+
+		switch {
+		case isNodeType(field.Type()):
+			appendChild(field, children)
+		case field.Kind() == reflect.Slice && isNodeType(field.Type().Elem()):
+			for j := 0; j < field.Len(); j++ {
+				appendChild(field.Index(j), children)
+			}
+		}
+	}
+}
+
+func appendChild(v reflect.Value, children *[]Node) {
+	if v.IsNil() {
+		return
+	}
+	node := v.Interface().(Node)
+	if reflect.ValueOf(node).IsNil() {
+		return
+	}
+	*children = append(*children, node)
+}
+
+// FindNodes returns the nodes of type T in an AST, in depth-first order, e.g.
+// FindNodes[*FunctionDefinition](sourceUnit).
+func FindNodes[T Node](root Node) []T {
+	var nodes []T
+	Inspect(root, func(node Node) bool {
+		if n, ok := node.(T); ok {
+			nodes = append(nodes, n)
+		}
+		return true
+	})
+	return nodes
+}
@@ -0,0 +1,103 @@
+package gosolc
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func loadASTTestOutput(t *testing.T) *StandardOutput {
+	t.Helper()
+	data, err := os.ReadFile("testdata/output/ast_output.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile("testdata/contracts_ast/Counter.sol")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var output map[string]interface{}
+	if err := json.Unmarshal(data, &output); err != nil {
+		t.Fatal(err)
+	}
+	out, err := newStandardOutput(output, map[string]string{"Counter.sol": string(content)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func TestASTWalk(t *testing.T) {
+	out := loadASTTestOutput(t)
+	unit := out.Sources["Counter.sol"].AST
+
+	var nodeTypes []string
+	depth, maxDepth := 0, 0
+	Inspect(unit, func(node Node) bool {
+		if node == nil {
+			depth--
+			return true
+		}
+		depth++
+		if depth > maxDepth {
+			maxDepth = depth
+		}
+		nodeTypes = append(nodeTypes, node.Base().NodeType)
+		return true
+	})
+	if depth != 0 {
+		t.Errorf("unbalanced walk, depth %d", depth)
+	}
+
+	expected := []string{
+		"SourceUnit", "PragmaDirective", "ContractDefinition", "StructuredDocumentation",
+		"VariableDeclaration", "ElementaryTypeName",
+		"EventDefinition", "ParameterList", "VariableDeclaration", "ElementaryTypeName", "VariableDeclaration", "ElementaryTypeName",
+		"FunctionDefinition", "ParameterList", "VariableDeclaration", "ElementaryTypeName", "ParameterList",
+		"Block", "ExpressionStatement", "Assignment", "Identifier", "Identifier",
+		"EmitStatement", "FunctionCall", "Identifier", "MemberAccess", "Identifier", "Identifier",
+	}
+	if !reflect.DeepEqual(nodeTypes, expected) {
+		t.Errorf("unexpected walk order:\nexpected %v\ngot      %v", expected, nodeTypes)
+	}
+
+	functions := FindNodes[*FunctionDefinition](unit)
+	if len(functions) != 1 || functions[0].Name != "increment" || functions[0].FunctionSelector != "7cf5dab0" {
+		t.Fatalf("unexpected functions %v", functions)
+	}
+	assignment := functions[0].Body.Statements[0].(*ExpressionStatement).Expression.(*Assignment)
+	if assignment.Operator != "+=" || assignment.TypeDescriptions.TypeString != "uint256" {
+		t.Errorf("unexpected assignment %+v", assignment)
+	}
+}
+
+func TestASTNodeByIDAndLocation(t *testing.T) {
+	out := loadASTTestOutput(t)
+
+	identifiers := FindNodes[*Identifier](out.Sources["Counter.sol"].AST)
+	declaration, ok := out.NodeByID(identifiers[0].ReferencedDeclaration)
+	if !ok {
+		t.Fatalf("declaration %d not found", identifiers[0].ReferencedDeclaration)
+	}
+	variable, ok := declaration.(*VariableDeclaration)
+	if !ok || variable.Name != "count" || !variable.StateVariable {
+		t.Fatalf("unexpected declaration %+v", declaration)
+	}
+
+	location, err := out.NodeLocation(variable)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if location.String() != "Counter.sol:6:5" || location.EndLine != 6 || location.EndColumn != 25 {
+		t.Errorf("unexpected location %+v", location)
+	}
+
+	if _, err := out.Location("0:10:7"); err == nil {
+		t.Error("expected an error for an unknown source id")
+	}
+	if len(out.Warnings) != 1 {
+		t.Errorf("expected 1 warning, got %v", out.Warnings)
+	}
+}
@@ -0,0 +1,320 @@
+package gosolc
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"reflect"
+)
+
+// ContractCaller executes read-only calls against a deployed contract (eth_call).
+type ContractCaller interface {
+	CallContract(ctx context.Context, to Address, data []byte) ([]byte, error)
+}
+
+// ContractTransactor sends transactions; to is nil for contract deployments.
+type ContractTransactor interface {
+	SendTransaction(ctx context.Context, to *Address, data []byte, value *big.Int) (Hash, error)
+}
+
+// ContractFilterer retrieves the logs of a contract (eth_getLogs). topics[i] lists the accepted values
+// of topic i, an empty list matches any value.
+type ContractFilterer interface {
+	FilterLogs(ctx context.Context, address Address, topics [][]Hash, fromBlock, toBlock *big.Int) ([]Log, error)
+}
+
+// ContractBackend is the interface generated Go bindings use to interact with the chain.
+type ContractBackend interface {
+	ContractCaller
+	ContractTransactor
+	ContractFilterer
+}
+
+// BoundContract is a contract ABI bound to a deployed address, used by the generated Go bindings.
+type BoundContract struct {
+	Address Address
+	ABI     *ABI
+	Backend ContractBackend
+}
+
+// NewBoundContract binds a contract ABI to an address and backend.
+func NewBoundContract(address Address, abi *ABI, backend ContractBackend) *BoundContract {
+	return &BoundContract{Address: address, ABI: abi, Backend: backend}
+}
+
+// DeployContract sends a transaction deploying bytecode (hex) with the ABI-encoded constructor arguments
+// and returns its hash.
+func DeployContract(ctx context.Context, backend ContractTransactor, abi *ABI, bytecode string, value *big.Int, args ...interface{}) (Hash, error) {
+	code, err := decodeHex(bytecode)
+	if err != nil {
+		return Hash{}, fmt.Errorf("invalid bytecode: %v", err)
+	}
+
+	encodedArgs, err := abi.EncodeConstructor(args...)
+	if err != nil {
+		return Hash{}, err
+	}
+
+	return backend.SendTransaction(ctx, nil, append(code, encodedArgs...), value)
+}
+
+// Call calls a function by name or signature and returns its decoded return values.
+// Reverts with data decodable by the ABI are returned as *RevertError.
+func (c *BoundContract) Call(ctx context.Context, method string, args ...interface{}) ([]interface{}, error) {
+	function, err := c.ABI.Function(method)
+	if err != nil {
+		return nil, err
+	}
+
+	calldata, err := function.EncodeCall(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := c.Backend.CallContract(ctx, c.Address, calldata)
+	if err != nil {
+		return nil, c.wrapRevert(err)
+	}
+	return function.DecodeOutputs(output)
+}
+
+// Transact sends a transaction calling a function by name or signature and returns its hash.
+func (c *BoundContract) Transact(ctx context.Context, value *big.Int, method string, args ...interface{}) (Hash, error) {
+	calldata, err := c.ABI.EncodeCall(method, args...)
+	if err != nil {
+		return Hash{}, err
+	}
+
+	hash, err := c.Backend.SendTransaction(ctx, &c.Address, calldata, value)
+	if err != nil {
+		return Hash{}, c.wrapRevert(err)
+	}
+	return hash, nil
+}
+
+// FilterLogs retrieves the logs of an event. query lists the accepted values of each indexed parameter,
+// in order; a nil or empty list matches any value.
+func (c *BoundContract) FilterLogs(ctx context.Context, event string, fromBlock, toBlock *big.Int, query ...[]interface{}) ([]Log, error) {
+	entry, err := c.ABI.Event(event)
+	if err != nil {
+		return nil, err
+	}
+
+	topics, err := eventTopics(entry, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Backend.FilterLogs(ctx, c.Address, topics, fromBlock, toBlock)
+}
+
+// RevertError is returned by bound calls when the backend error carries revert data decoded by the ABI.
+type RevertError struct {
+	*Revert
+	Err error // Original backend error
+}
+
+// Error implements the error interface.
+func (e *RevertError) Error() string {
+	return "execution reverted: " + e.Revert.String()
+}
+
+// Unwrap returns the original backend error.
+func (e *RevertError) Unwrap() error {
+	return e.Err
+}
+
+// RevertDataError can be implemented by backend errors that carry the revert data of a failed call.
+type RevertDataError interface {
+	error
+	RevertData() []byte
+}
+
+// wrapRevert decodes the revert data of backend errors implementing RevertDataError.
+func (c *BoundContract) wrapRevert(err error) error {
+	dataErr, ok := err.(RevertDataError)
+	if !ok {
+		return err
+	}
+	revert, decodeErr := c.ABI.DecodeRevert(dataErr.RevertData())
+	if decodeErr != nil {
+		return err
+	}
+	return &RevertError{Revert: revert, Err: err}
+}
+
+// eventTopics builds the topic filter of an event from the accepted values of its indexed parameters.
+func eventTopics(entry *ABIEntry, query [][]interface{}) ([][]Hash, error) {
+	var topics [][]Hash
+	if !entry.Anonymous {
+		topics = append(topics, []Hash{entry.Topic()})
+	}
+
+	i := 0
+	for _, input := range entry.Inputs {
+		if !input.Indexed {
+			continue
+		}
+		if i >= len(query) {
+			break
+		}
+
+		t, err := input.ABIType()
+		if err != nil {
+			return nil, err
+		}
+
+		var values []Hash
+		for _, value := range query[i] {
+			topic, err := topicValue(t, value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid filter value for %s: %v", input.Name, err)
+			}
+			values = append(values, topic)
+		}
+		topics = append(topics, values)
+		i++
+	}
+	if i < len(query) {
+		return nil, fmt.Errorf("event %s has only %d indexed parameters", entry.Signature(), i)
+	}
+
+	return topics, nil
+}
+
+// topicValue returns the topic of an indexed event parameter value: its encoding for value types,
+// or the keccak256 hash of the content for strings and bytes.
+func topicValue(t *ABIType, value interface{}) (Hash, error) {
+	if h, ok := value.(Hash); ok {
+		return h, nil
+	}
+
+	var encoded []byte
+	var err error
+	switch t.Kind {
+	case StringKind:
+		s, ok := value.(string)
+		if !ok {
+			return Hash{}, fmt.Errorf("expected string, got %T", value)
+		}
+		encoded = keccak256([]byte(s))
+	case BytesKind:
+		var b []byte
+		if b, err = toBytes(value); err == nil {
+			encoded = keccak256(b)
+		}
+	case SliceKind, ArrayKind, TupleKind:
+		return Hash{}, fmt.Errorf("indexed %s parameters can only be filtered by Hash", t)
+	default:
+		encoded, err = encodeValue(t, value)
+	}
+	if err != nil {
+		return Hash{}, err
+	}
+	return Hash(encoded), nil
+}
+
+// FilterValues converts typed filter values to the []interface{} accepted by BoundContract.FilterLogs.
+func FilterValues[T any](values []T) []interface{} {
+	if values == nil {
+		return nil
+	}
+	result := make([]interface{}, len(values))
+	for i, value := range values {
+		result[i] = value
+	}
+	return result
+}
+
+// ConvertABIValue stores a decoded ABI value into target, which must be a pointer to a compatible Go value:
+// *big.Int and Go integers for integers, byte arrays or slices for bytes types, slices and arrays for
+// ABI arrays, and structs (matched by component order) for tuples. It is used by generated Go bindings.
+func ConvertABIValue(value interface{}, target interface{}) error {
+	rv := reflect.ValueOf(target)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("target must be a non-nil pointer, got %T", target)
+	}
+	return convertValue(reflect.ValueOf(value), rv.Elem())
+}
+
+var bigIntType = reflect.TypeOf((*big.Int)(nil))
+
+func convertValue(src reflect.Value, dst reflect.Value) error {
+	if src.Kind() == reflect.Interface {
+		src = src.Elem()
+	}
+	if !src.IsValid() {
+		return fmt.Errorf("cannot convert nil to %s", dst.Type())
+	}
+	if src.Type().AssignableTo(dst.Type()) {
+		dst.Set(src)
+		return nil
+	}
+
+	if n, ok := src.Interface().(*big.Int); ok {
+		switch dst.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if !n.IsInt64() || dst.OverflowInt(n.Int64()) {
+				return fmt.Errorf("%s overflows %s", n, dst.Type())
+			}
+			dst.SetInt(n.Int64())
+			return nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if !n.IsUint64() || dst.OverflowUint(n.Uint64()) {
+				return fmt.Errorf("%s overflows %s", n, dst.Type())
+			}
+			dst.SetUint(n.Uint64())
+			return nil
+		}
+	}
+
+	switch dst.Kind() {
+	case reflect.Array:
+		if src.Kind() != reflect.Slice && src.Kind() != reflect.Array {
+			break
+		}
+		if src.Len() != dst.Len() {
+			return fmt.Errorf("cannot convert %d items to %s", src.Len(), dst.Type())
+		}
+		for i := 0; i < src.Len(); i++ {
+			if err := convertValue(src.Index(i), dst.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Slice:
+		if src.Kind() != reflect.Slice && src.Kind() != reflect.Array {
+			break
+		}
+		slice := reflect.MakeSlice(dst.Type(), src.Len(), src.Len())
+		for i := 0; i < src.Len(); i++ {
+			if err := convertValue(src.Index(i), slice.Index(i)); err != nil {
+				return err
+			}
+		}
+		dst.Set(slice)
+		return nil
+	case reflect.Struct:
+		if src.Kind() != reflect.Slice || src.Len() != dst.NumField() {
+			break
+		}
+		for i := 0; i < src.Len(); i++ {
+			if err := convertValue(src.Index(i), dst.Field(i)); err != nil {
+				return fmt.Errorf("field %s: %v", dst.Type().Field(i).Name, err)
+			}
+		}
+		return nil
+	case reflect.Pointer:
+		if dst.Type() == bigIntType {
+			break
+		}
+		ptr := reflect.New(dst.Type().Elem())
+		if err := convertValue(src, ptr.Elem()); err != nil {
+			return err
+		}
+		dst.Set(ptr)
+		return nil
+	}
+
+	return fmt.Errorf("cannot convert %s to %s", src.Type(), dst.Type())
+}
@@ -0,0 +1,266 @@
+package gosolc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// stubBackend records the requests of a BoundContract and answers them with canned results.
+type stubBackend struct {
+	output []byte
+	logs   []Log
+	err    error
+
+	to     *Address
+	data   []byte
+	value  *big.Int
+	topics [][]Hash
+}
+
+func (b *stubBackend) CallContract(ctx context.Context, to Address, data []byte) ([]byte, error) {
+	b.to, b.data = &to, data
+	return b.output, b.err
+}
+
+func (b *stubBackend) SendTransaction(ctx context.Context, to *Address, data []byte, value *big.Int) (Hash, error) {
+	b.to, b.data, b.value = to, data, value
+	return Hash{0x01}, b.err
+}
+
+func (b *stubBackend) FilterLogs(ctx context.Context, address Address, topics [][]Hash, fromBlock, toBlock *big.Int) ([]Log, error) {
+	b.to, b.topics = &address, topics
+	return b.logs, b.err
+}
+
+// revertDataError is a backend error carrying revert data.
+type revertDataError struct {
+	data []byte
+}
+
+func (e *revertDataError) Error() string      { return "execution reverted" }
+func (e *revertDataError) RevertData() []byte { return e.data }
+
+func exchangeABI(t *testing.T) *ABI {
+	t.Helper()
+	abi, err := loadTestOutput(t, "bindings_output.json").ContractABI("Exchange")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return abi
+}
+
+func TestBoundContractCall(t *testing.T) {
+	abi := exchangeABI(t)
+	address, _ := HexToAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+	backend := &stubBackend{output: leftPad(big.NewInt(5).Bytes())}
+	c := NewBoundContract(address, abi, backend)
+
+	out, err := c.Call(context.Background(), "swap(uint256)", 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	swap, _ := abi.Function("swap(uint256)")
+	calldata, _ := swap.EncodeCall(7)
+	if *backend.to != address || !bytes.Equal(backend.data, calldata) {
+		t.Errorf("unexpected call to %v with %x", backend.to, backend.data)
+	}
+	if len(out) != 1 || out[0].(*big.Int).Int64() != 5 {
+		t.Errorf("unexpected outputs %v", out)
+	}
+
+	if _, err := c.Call(context.Background(), "swap(uint256)", "seven"); err == nil {
+		t.Error("expected an error for an invalid argument")
+	}
+	if _, err := c.Call(context.Background(), "missing()"); err == nil {
+		t.Error("expected an error for an unknown function")
+	}
+}
+
+func TestBoundContractRevertError(t *testing.T) {
+	abi := exchangeABI(t)
+	bad, _ := abi.Error("Bad(uint256)")
+	data, err := bad.EncodeCall(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	backendErr := &revertDataError{data: data}
+	c := NewBoundContract(Address{}, abi, &stubBackend{err: backendErr})
+
+	_, err = c.Call(context.Background(), "swap(uint256)", 7)
+	var revert *RevertError
+	if !errors.As(err, &revert) || revert.Revert.Error.Signature() != "Bad(uint256)" || revert.Args[0].(*big.Int).Int64() != 3 {
+		t.Fatalf("expected a Bad(uint256) RevertError, got %v", err)
+	}
+	if !errors.Is(err, backendErr) || err.Error() != "execution reverted: Bad(3)" {
+		t.Errorf("unexpected revert error %q", err)
+	}
+
+	if _, err := c.Transact(context.Background(), nil, "swap(uint256)", 7); !errors.As(err, &revert) {
+		t.Errorf("expected a RevertError from Transact, got %v", err)
+	}
+
+	// undecodable revert data and other errors are returned unchanged
+	backendErr.data = []byte{0xde, 0xad, 0xbe, 0xef}
+	if _, err := c.Call(context.Background(), "swap(uint256)", 7); err != backendErr {
+		t.Errorf("expected the backend error, got %v", err)
+	}
+	plain := errors.New("connection refused")
+	c.Backend = &stubBackend{err: plain}
+	if _, err := c.Call(context.Background(), "swap(uint256)", 7); err != plain {
+		t.Errorf("expected the backend error, got %v", err)
+	}
+}
+
+func TestBoundContractTransact(t *testing.T) {
+	abi := exchangeABI(t)
+	address, _ := HexToAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+	backend := &stubBackend{}
+	c := NewBoundContract(address, abi, backend)
+
+	hash, err := c.Transact(context.Background(), big.NewInt(1), "swap(uint256,address)", 7, address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	calldata, _ := abi.EncodeCall("swap(uint256,address)", 7, address)
+	if hash != (Hash{0x01}) || *backend.to != address || backend.value.Int64() != 1 || !bytes.Equal(backend.data, calldata) {
+		t.Errorf("unexpected transaction to %v with %x", backend.to, backend.data)
+	}
+
+	if _, err := c.Transact(context.Background(), nil, "swap(uint256,address)", 7); err == nil {
+		t.Error("expected an error for a missing argument")
+	}
+
+	hash, err = DeployContract(context.Background(), backend, abi, "0x6001", nil, address, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	constructorArgs, _ := abi.EncodeConstructor(address, 10)
+	if hash != (Hash{0x01}) || backend.to != nil || !bytes.Equal(backend.data, append([]byte{0x60, 0x01}, constructorArgs...)) {
+		t.Errorf("unexpected deployment with %x", backend.data)
+	}
+}
+
+func TestBoundContractFilterLogs(t *testing.T) {
+	abi := exchangeABI(t)
+	address, _ := HexToAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+	backend := &stubBackend{logs: []Log{{Address: address}}}
+	c := NewBoundContract(address, abi, backend)
+
+	logs, err := c.FilterLogs(context.Background(), "Ev(address,string,bytes)", big.NewInt(1), nil, []interface{}{address}, []interface{}{"tag"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ev, _ := abi.Event("Ev(address,string,bytes)")
+	var who Hash
+	copy(who[12:], address[:])
+	expected := [][]Hash{{ev.Topic()}, {who}, {Hash(keccak256([]byte("tag")))}}
+	if len(logs) != 1 || *backend.to != address || !reflect.DeepEqual(backend.topics, expected) {
+		t.Errorf("unexpected topics %v", backend.topics)
+	}
+
+	if _, err := c.FilterLogs(context.Background(), "Ev(uint256)", nil, nil, nil, nil); err == nil {
+		t.Error("expected an error for too many query values")
+	}
+}
+
+func TestEventTopics(t *testing.T) {
+	abi := exchangeABI(t)
+	address, _ := HexToAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+
+	// anonymous events have no signature topic, nil queries match any value
+	anon, _ := abi.Event("Anon")
+	topics, err := eventTopics(anon, [][]interface{}{{address}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != 1 || topics[0][0] != Hash(leftPad(address[:])) {
+		t.Errorf("unexpected anonymous event topics %v", topics)
+	}
+	ev, _ := abi.Event("Ev(address,string,bytes)")
+	topics, _ = eventTopics(ev, [][]interface{}{nil, {"a", "b"}})
+	if len(topics) != 3 || topics[1] != nil || topics[2][1] != Hash(keccak256([]byte("b"))) {
+		t.Errorf("unexpected event topics %v", topics)
+	}
+
+	if _, err := eventTopics(ev, [][]interface{}{nil, nil, nil}); err == nil || !strings.Contains(err.Error(), "only 2 indexed parameters") {
+		t.Errorf("expected an error for too many query values, got %v", err)
+	}
+	if _, err := eventTopics(ev, [][]interface{}{nil, {42}}); err == nil {
+		t.Error("expected an error for a non-string value of a string parameter")
+	}
+
+	bytesType := &ABIType{Kind: BytesKind}
+	topic, err := topicValue(bytesType, []byte{0xca, 0xfe})
+	if err != nil || topic != Hash(keccak256([]byte{0xca, 0xfe})) {
+		t.Errorf("unexpected bytes topic %v %v", topic, err)
+	}
+	if topic, err := topicValue(bytesType, Hash{0x02}); err != nil || topic != (Hash{0x02}) {
+		t.Errorf("expected hashes to be used as is, got %v %v", topic, err)
+	}
+	if _, err := topicValue(&ABIType{Kind: TupleKind}, []interface{}{1}); err == nil {
+		t.Error("expected an error for a tuple value")
+	}
+}
+
+func TestConvertABIValue(t *testing.T) {
+	var small uint8
+	if err := ConvertABIValue(big.NewInt(300), &small); err == nil || !strings.Contains(err.Error(), "overflows") {
+		t.Errorf("expected an overflow error, got %v", err)
+	}
+	var unsigned uint64
+	if err := ConvertABIValue(big.NewInt(-1), &unsigned); err == nil {
+		t.Error("expected an error for a negative uint64")
+	}
+	var signed int16
+	if err := ConvertABIValue(big.NewInt(-300), &signed); err != nil || signed != -300 {
+		t.Errorf("unexpected int16 %d %v", signed, err)
+	}
+
+	salt := bytes.Repeat([]byte{0xaa}, 32)
+	var salts [2][32]byte
+	if err := ConvertABIValue([]interface{}{salt, salt}, &salts); err != nil || salts[1][31] != 0xaa {
+		t.Errorf("unexpected array %x %v", salts, err)
+	}
+	if err := ConvertABIValue([]interface{}{salt}, &salts); err == nil {
+		t.Error("expected an error for a wrong array length")
+	}
+	var amounts []uint8
+	if err := ConvertABIValue([]interface{}{big.NewInt(1), big.NewInt(2)}, &amounts); err != nil || !reflect.DeepEqual(amounts, []uint8{1, 2}) {
+		t.Errorf("unexpected slice %v %v", amounts, err)
+	}
+
+	maker, _ := HexToAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+	var order struct {
+		Maker  Address
+		FeeBps *big.Int
+		Salts  [2][32]byte
+	}
+	if err := ConvertABIValue([]interface{}{maker, big.NewInt(30), []interface{}{salt, salt}}, &order); err != nil {
+		t.Fatal(err)
+	}
+	if order.Maker != maker || order.FeeBps.Int64() != 30 || order.Salts[0][0] != 0xaa {
+		t.Errorf("unexpected struct %+v", order)
+	}
+	if err := ConvertABIValue([]interface{}{maker}, &order); err == nil {
+		t.Error("expected an error for a wrong number of tuple fields")
+	}
+
+	var ptr *uint64
+	if err := ConvertABIValue(big.NewInt(7), &ptr); err != nil || ptr == nil || *ptr != 7 {
+		t.Errorf("unexpected pointer target %v %v", ptr, err)
+	}
+	if err := ConvertABIValue(big.NewInt(7), unsigned); err == nil {
+		t.Error("expected an error for a non-pointer target")
+	}
+	if err := ConvertABIValue(nil, &unsigned); err == nil {
+		t.Error("expected an error for a nil value")
+	}
+	if err := ConvertABIValue("7", &unsigned); err == nil {
+		t.Error("expected an error for an incompatible value")
+	}
+}
@@ -0,0 +1,570 @@
+package gosolc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// GenerateGoBindings generates type-safe Go bindings for the contracts of a compiler output, in a single
+// Go source file of package pkg. Every contract gets a binding type with a constructor, a deploy function
+// carrying its compiled bytecode, call methods for view and pure functions, transact methods for the other
+// functions, event types with Parse and Filter methods, and custom error types.
+// Overloaded functions, events and errors get numbered names, e.g. Swap0 or ExchangeBadError0.
+// contracts optionally restricts the generation to the given (fully qualified or bare) contract names.
+//
+// The bindings use the runtime in this package (BoundContract) and a ContractBackend implementation.
+// It can be used from go:generate through the CLI:
+//
+//	//go:generate go run github.com/0xsharma/gosolc/cmd/gosolc bindings -contracts ./contracts -pkg contracts -out bindings.go
+func GenerateGoBindings(output CompilerOutput, pkg string, contracts ...string) ([]byte, error) {
+	fqNames := output.FullyQualifiedNames()
+	if len(contracts) > 0 {
+		fqNames = fqNames[:0]
+		for _, name := range contracts {
+			fqName, _, err := output.resolveContract(name)
+			if err != nil {
+				return nil, err
+			}
+			fqNames = append(fqNames, fqName)
+		}
+	}
+
+	g := &bindingGenerator{structs: map[string]string{}, types: map[string]bool{}}
+	seen := map[string]string{}
+	for _, fqName := range fqNames {
+		_, name := splitFullyQualifiedName(fqName)
+		if other, ok := seen[name]; ok {
+			return nil, fmt.Errorf("contracts %s and %s have the same name, generate their bindings separately", other, fqName)
+		}
+		seen[name] = fqName
+
+		if err := g.generateContract(output, fqName); err != nil {
+			return nil, fmt.Errorf("failed to generate bindings for %s: %w", fqName, err)
+		}
+	}
+
+	return g.source(pkg)
+}
+
+// bindingGenerator accumulates the generated code of all contracts.
+type bindingGenerator struct {
+	body    bytes.Buffer
+	structs map[string]string // Go struct definitions keyed by type name
+	types   map[string]bool   // Names of the generated event and error types, unique across overloads
+}
+
+// source assembles the Go source file with the imports used by the generated code.
+func (g *bindingGenerator) source(pkg string) ([]byte, error) {
+	var src bytes.Buffer
+	body := g.body.String()
+
+	src.WriteString("// Code generated by gosolc. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&src, "package %s\n\nimport (\n", pkg)
+	for _, imp := range []struct{ path, use string }{
+		{"context", "context."},
+		{"encoding/json", "json."},
+		{"errors", "errors."},
+		{"math/big", "big."},
+	} {
+		if strings.Contains(body, imp.use) {
+			fmt.Fprintf(&src, "\t%q\n", imp.path)
+		}
+	}
+	src.WriteString("\n\t\"github.com/0xsharma/gosolc\"\n)\n\n")
+
+	for _, name := range sortedStringKeys(g.structs) {
+		src.WriteString(g.structs[name])
+	}
+	src.WriteString(body)
+
+	formatted, err := format.Source(src.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated bindings: %v", err)
+	}
+	return formatted, nil
+}
+
+func (g *bindingGenerator) generateContract(output CompilerOutput, fqName string) error {
+	contract, err := output.Contract(fqName)
+	if err != nil {
+		return err
+	}
+	abi, err := contractABI(contract)
+	if err != nil {
+		return err
+	}
+
+	// ABI-only outputs (e.g. interfaces from artifacts) get bindings without a deploy function
+	bytecode := ""
+	var references LinkReferences
+	if evm, _ := contract["evm"].(map[string]interface{}); evm["bytecode"] != nil {
+		if bytecode, _, err = contractBytecodes(contract); err != nil {
+			return err
+		}
+		if references, _, err = output.GetLinkReferences(fqName); err != nil {
+			return err
+		}
+	}
+
+	_, contractName := splitFullyQualifiedName(fqName)
+	name := goExportedName(contractName)
+	abiJSON, err := json.Marshal(abi)
+	if err != nil {
+		return err
+	}
+
+	w := &g.body
+	fmt.Fprintf(w, "// %sABI is the ABI of the %s contract.\n", name, fqName)
+	fmt.Fprintf(w, "const %sABI = %s\n\n", name, strconv.Quote(string(abiJSON)))
+	fmt.Fprintf(w, "// %s is a Go binding of the %s contract.\n", name, fqName)
+	fmt.Fprintf(w, "type %s struct {\n\t*gosolc.BoundContract\n}\n\n", name)
+	fmt.Fprintf(w, "// New%s binds a %s contract deployed at address.\n", name, contractName)
+	fmt.Fprintf(w, "func New%s(address gosolc.Address, backend gosolc.ContractBackend) (*%s, error) {\n", name, name)
+	fmt.Fprintf(w, "\tabi, err := gosolc.ParseABI([]byte(%sABI))\n\tif err != nil {\n\t\treturn nil, err\n\t}\n", name)
+	fmt.Fprintf(w, "\treturn &%s{gosolc.NewBoundContract(address, abi, backend)}, nil\n}\n\n", name)
+
+	unpack := ""
+	if len(abi.Errors) > 0 {
+		unpack = "Unpack" + name + "Error"
+	}
+
+	if bytecode != "" {
+		if err := g.generateDeploy(name, contractName, abi, bytecode, references, unpack); err != nil {
+			return err
+		}
+	}
+
+	// methods must not shadow the embedded BoundContract or its fields
+	methods := map[string]bool{"BoundContract": true, "ABI": true, "Address": true, "Backend": true}
+	for _, function := range abi.Functions {
+		if err := g.generateFunction(name, function, methods, unpack); err != nil {
+			return err
+		}
+	}
+	for _, event := range abi.Events {
+		if err := g.generateEvent(name, event, methods); err != nil {
+			return err
+		}
+	}
+	if len(abi.Errors) > 0 {
+		if err := g.generateErrors(name, abi.Errors); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (g *bindingGenerator) generateDeploy(name, contractName string, abi *ABI, bytecode string, references LinkReferences, unpack string) error {
+	w := &g.body
+	fmt.Fprintf(w, "// %sBin is the compiled creation bytecode of the %s contract.\n", name, contractName)
+	fmt.Fprintf(w, "const %sBin = %q\n\n", name, "0x"+bytecode)
+
+	linked := len(references) > 0
+	if linked {
+		referencesJSON, err := json.Marshal(references)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "// %sLinkReferences are the library placeholders of %sBin.\n", name, name)
+		fmt.Fprintf(w, "const %sLinkReferences = %s\n\n", name, strconv.Quote(string(referencesJSON)))
+	}
+
+	var constructorInputs []ABIParameter
+	payable := false
+	if abi.Constructor != nil {
+		constructorInputs = abi.Constructor.Inputs
+		payable = abi.Constructor.StateMutability == "payable"
+	}
+	params, args, err := g.goParams(name, constructorInputs)
+	if err != nil {
+		return err
+	}
+
+	signature := "ctx context.Context, backend gosolc.ContractTransactor"
+	value := "nil"
+	if linked {
+		signature += ", libraries map[string]string"
+	}
+	if payable {
+		signature += ", value *big.Int"
+		value = "value"
+	}
+
+	fmt.Fprintf(w, "// Deploy%s deploys a new %s contract and returns the hash of the deployment transaction.\n", name, contractName)
+	if linked {
+		fmt.Fprintf(w, "// libraries maps fully qualified library names to their deployed addresses.\n")
+	}
+	fmt.Fprintf(w, "func Deploy%s(%s%s) (gosolc.Hash, error) {\n", name, signature, params)
+	fmt.Fprintf(w, "\tabi, err := gosolc.ParseABI([]byte(%sABI))\n\tif err != nil {\n\t\treturn gosolc.Hash{}, err\n\t}\n", name)
+	bin := name + "Bin"
+	if linked {
+		fmt.Fprintf(w, "\tvar references gosolc.LinkReferences\n")
+		fmt.Fprintf(w, "\tif err := json.Unmarshal([]byte(%sLinkReferences), &references); err != nil {\n\t\treturn gosolc.Hash{}, err\n\t}\n", name)
+		fmt.Fprintf(w, "\tbin, err := gosolc.LinkBytecode(%sBin, references, libraries)\n\tif err != nil {\n\t\treturn gosolc.Hash{}, err\n\t}\n", name)
+		bin = "bin"
+	}
+	call := fmt.Sprintf("gosolc.DeployContract(ctx, backend, abi, %s, %s%s)", bin, value, args)
+	if unpack != "" {
+		fmt.Fprintf(w, "\thash, err := %s\n\treturn hash, %s(err)\n}\n\n", call, unpack)
+	} else {
+		fmt.Fprintf(w, "\treturn %s\n}\n\n", call)
+	}
+	return nil
+}
+
+func (g *bindingGenerator) generateFunction(name string, function *ABIEntry, methods map[string]bool, unpack string) error {
+	w := &g.body
+	method := uniqueName(goExportedName(function.Name), methods)
+	signature := function.Signature()
+
+	params, args, err := g.goParams(name, function.Inputs)
+	if err != nil {
+		return err
+	}
+
+	wrap := func(expr string) string {
+		if unpack == "" {
+			return expr
+		}
+		return unpack + "(" + expr + ")"
+	}
+
+	if function.StateMutability == "view" || function.StateMutability == "pure" {
+		var results []string
+		for i, output := range function.Outputs {
+			t, err := g.goType(name, output)
+			if err != nil {
+				return err
+			}
+			results = append(results, fmt.Sprintf("ret%d %s", i, t))
+		}
+		results = append(results, "err error")
+
+		fmt.Fprintf(w, "// %s calls the %s function %s.\n", method, function.StateMutability, signature)
+		fmt.Fprintf(w, "func (c *%s) %s(ctx context.Context%s) (%s) {\n", name, method, params, strings.Join(results, ", "))
+		fmt.Fprintf(w, "\tout, err := c.BoundContract.Call(ctx, %q%s)\n", signature, args)
+		if unpack != "" {
+			fmt.Fprintf(w, "\tif err != nil {\n\t\terr = %s\n\t\treturn\n\t}\n", wrap("err"))
+		} else {
+			fmt.Fprintf(w, "\tif err != nil {\n\t\treturn\n\t}\n")
+		}
+		for i := range function.Outputs {
+			fmt.Fprintf(w, "\tif err = gosolc.ConvertABIValue(out[%d], &ret%d); err != nil {\n\t\treturn\n\t}\n", i, i)
+		}
+		if len(function.Outputs) == 0 {
+			fmt.Fprintf(w, "\t_ = out\n")
+		}
+		fmt.Fprintf(w, "\treturn\n}\n\n")
+		return nil
+	}
+
+	value := "nil"
+	if function.StateMutability == "payable" {
+		params = ", value *big.Int" + params
+		value = "value"
+	}
+	fmt.Fprintf(w, "// %s sends a transaction calling %s and returns its hash.\n", method, signature)
+	fmt.Fprintf(w, "func (c *%s) %s(ctx context.Context%s) (gosolc.Hash, error) {\n", name, method, params)
+	call := fmt.Sprintf("c.BoundContract.Transact(ctx, %s, %q%s)", value, signature, args)
+	if unpack != "" {
+		fmt.Fprintf(w, "\thash, err := %s\n\treturn hash, %s\n}\n\n", call, wrap("err"))
+	} else {
+		fmt.Fprintf(w, "\treturn %s\n}\n\n", call)
+	}
+	return nil
+}
+
+func (g *bindingGenerator) generateEvent(name string, event *ABIEntry, methods map[string]bool) error {
+	w := &g.body
+	eventName := goExportedName(event.Name)
+	typeName := uniqueName(name+eventName, g.types)
+	signature := event.Signature()
+	parse := uniqueName("Parse"+eventName, methods)
+	filter := uniqueName("Filter"+eventName, methods)
+
+	var fields, conversions, filterParams, filterArgs []string
+	used := map[string]bool{"Raw": true}
+	for i, input := range event.Inputs {
+		key := input.Name
+		if key == "" {
+			key = fmt.Sprintf("arg%d", i)
+		}
+		field := uniqueName(goExportedName(key), used)
+
+		goType, err := g.goType(name, input)
+		if err != nil {
+			return err
+		}
+
+		if input.Indexed {
+			t, err := input.ABIType()
+			if err != nil {
+				return err
+			}
+			filterType := goType
+			switch t.Kind {
+			case StringKind, BytesKind, SliceKind, ArrayKind, TupleKind:
+				// only the keccak256 hash of indexed dynamic values is logged
+				goType = "gosolc.Hash"
+				if t.Kind != StringKind && t.Kind != BytesKind {
+					filterType = "gosolc.Hash"
+				}
+			}
+			param := goParamName(key, i)
+			filterParams = append(filterParams, fmt.Sprintf("%s []%s", param, filterType))
+			filterArgs = append(filterArgs, fmt.Sprintf("gosolc.FilterValues(%s)", param))
+		}
+
+		fields = append(fields, fmt.Sprintf("\t%s %s\n", field, goType))
+		conversions = append(conversions, fmt.Sprintf("\tif err := gosolc.ConvertABIValue(values[%q], &event.%s); err != nil {\n\t\treturn nil, err\n\t}\n", key, field))
+	}
+
+	fmt.Fprintf(w, "// %s represents a %s event raised by the %s contract.\n", typeName, event.Name, name)
+	fmt.Fprintf(w, "type %s struct {\n%s\tRaw gosolc.Log // Log the event was decoded from\n}\n\n", typeName, strings.Join(fields, ""))
+
+	fmt.Fprintf(w, "// %s decodes a %s event from a log.\n", parse, signature)
+	fmt.Fprintf(w, "func (c *%s) %s(log gosolc.Log) (*%s, error) {\n", name, parse, typeName)
+	fmt.Fprintf(w, "\tentry, err := c.BoundContract.ABI.Event(%q)\n\tif err != nil {\n\t\treturn nil, err\n\t}\n", signature)
+	fmt.Fprintf(w, "\tvalues, err := entry.DecodeLog(&log)\n\tif err != nil {\n\t\treturn nil, err\n\t}\n")
+	fmt.Fprintf(w, "\tevent := &%s{Raw: log}\n%s\treturn event, nil\n}\n\n", typeName, strings.Join(conversions, ""))
+
+	params := ""
+	if len(filterParams) > 0 {
+		params = ", " + strings.Join(filterParams, ", ")
+	}
+	args := ""
+	if len(filterArgs) > 0 {
+		args = ", " + strings.Join(filterArgs, ", ")
+	}
+	fmt.Fprintf(w, "// %s retrieves the %s events between fromBlock and toBlock (nil for latest).\n", filter, signature)
+	if len(filterParams) > 0 {
+		fmt.Fprintf(w, "// Indexed parameters filter the events when non-empty.\n")
+	}
+	fmt.Fprintf(w, "func (c *%s) %s(ctx context.Context, fromBlock, toBlock *big.Int%s) ([]*%s, error) {\n", name, filter, params, typeName)
+	fmt.Fprintf(w, "\tlogs, err := c.BoundContract.FilterLogs(ctx, %q, fromBlock, toBlock%s)\n\tif err != nil {\n\t\treturn nil, err\n\t}\n", signature, args)
+	fmt.Fprintf(w, "\tevents := make([]*%s, 0, len(logs))\n\tfor _, log := range logs {\n", typeName)
+	fmt.Fprintf(w, "\t\tevent, err := c.%s(log)\n\t\tif err != nil {\n\t\t\treturn nil, err\n\t\t}\n\t\tevents = append(events, event)\n\t}\n\treturn events, nil\n}\n\n", parse)
+	return nil
+}
+
+func (g *bindingGenerator) generateErrors(name string, errs []*ABIEntry) error {
+	w := &g.body
+	var cases []string
+	for _, abiError := range errs {
+		typeName := uniqueName(name+goExportedName(abiError.Name)+"Error", g.types)
+		signature := abiError.Signature()
+
+		var fields, conversions []string
+		used := map[string]bool{}
+		for i, input := range abiError.Inputs {
+			key := input.Name
+			if key == "" {
+				key = fmt.Sprintf("arg%d", i)
+			}
+			field := uniqueName(goExportedName(key), used)
+			goType, err := g.goType(name, input)
+			if err != nil {
+				return err
+			}
+			fields = append(fields, fmt.Sprintf("\t%s %s\n", field, goType))
+			conversions = append(conversions, fmt.Sprintf("\t\tif convErr := gosolc.ConvertABIValue(revert.Args[%d], &e.%s); convErr != nil {\n\t\t\treturn err\n\t\t}\n", i, field))
+		}
+
+		fmt.Fprintf(w, "// %s is the %s custom error of the %s contract.\n", typeName, signature, name)
+		fmt.Fprintf(w, "type %s struct {\n%s}\n\n", typeName, strings.Join(fields, ""))
+		fmt.Fprintf(w, "// Error implements the error interface.\n")
+		fmt.Fprintf(w, "func (e *%s) Error() string {\n\treturn %q\n}\n\n", typeName, "execution reverted: "+signature)
+
+		cases = append(cases, fmt.Sprintf("\tcase %q:\n\t\te := &%s{}\n%s\t\treturn e\n", signature, typeName, strings.Join(conversions, "")))
+	}
+
+	fmt.Fprintf(w, "// Unpack%sError converts reverts with a custom error of the %s contract into the matching error type.\n", name, name)
+	fmt.Fprintf(w, "// Other errors are returned unchanged.\n")
+	fmt.Fprintf(w, "func Unpack%sError(err error) error {\n", name)
+	fmt.Fprintf(w, "\tvar revert *gosolc.RevertError\n\tif !errors.As(err, &revert) {\n\t\treturn err\n\t}\n")
+	fmt.Fprintf(w, "\tswitch revert.Revert.Error.Signature() {\n%s\t}\n\treturn err\n}\n\n", strings.Join(cases, ""))
+	return nil
+}
+
+// goParams returns the Go parameter list and call arguments for ABI inputs, both with a leading ", ".
+func (g *bindingGenerator) goParams(contract string, inputs []ABIParameter) (string, string, error) {
+	var params, args []string
+	used := map[string]bool{}
+	for i, input := range inputs {
+		t, err := g.goType(contract, input)
+		if err != nil {
+			return "", "", err
+		}
+		name := uniqueName(goParamName(input.Name, i), used)
+		params = append(params, name+" "+t)
+		args = append(args, name)
+	}
+	if len(params) == 0 {
+		return "", "", nil
+	}
+	return ", " + strings.Join(params, ", "), ", " + strings.Join(args, ", "), nil
+}
+
+// goType returns the Go type of an ABI parameter, generating struct types for tuples.
+func (g *bindingGenerator) goType(contract string, param ABIParameter) (string, error) {
+	if i := strings.LastIndex(param.Type, "["); i >= 0 && strings.HasSuffix(param.Type, "]") {
+		elem := param
+		elem.Type = param.Type[:i]
+		if j := strings.LastIndex(param.InternalType, "["); j >= 0 {
+			elem.InternalType = param.InternalType[:j]
+		}
+		elemType, err := g.goType(contract, elem)
+		if err != nil {
+			return "", err
+		}
+		return param.Type[i:] + elemType, nil
+	}
+
+	t, err := param.ABIType()
+	if err != nil {
+		return "", err
+	}
+
+	switch t.Kind {
+	case UintKind, IntKind:
+		prefix := "int"
+		if t.Kind == UintKind {
+			prefix = "uint"
+		}
+		switch t.Size {
+		case 8, 16, 32, 64:
+			return prefix + strconv.Itoa(t.Size), nil
+		}
+		return "*big.Int", nil
+	case AddressKind:
+		return "gosolc.Address", nil
+	case BoolKind:
+		return "bool", nil
+	case FixedBytesKind, FunctionKind:
+		return "[" + strconv.Itoa(t.Size) + "]byte", nil
+	case BytesKind:
+		return "[]byte", nil
+	case StringKind:
+		return "string", nil
+	case TupleKind:
+		return g.goStruct(contract, param)
+	}
+	return "", fmt.Errorf("unsupported type %s", param.Type)
+}
+
+// goStruct generates (once) the Go struct of a tuple parameter and returns its name.
+// Structs are named after their Solidity definition (e.g. "struct Multicall3.Call3" becomes Multicall3Call3).
+func (g *bindingGenerator) goStruct(contract string, param ABIParameter) (string, error) {
+	name := ""
+	if strings.HasPrefix(param.InternalType, "struct ") {
+		name = goExportedName(strings.ReplaceAll(strings.TrimPrefix(param.InternalType, "struct "), ".", "_"))
+	} else {
+		t, err := param.ABIType()
+		if err != nil {
+			return "", err
+		}
+		name = fmt.Sprintf("%sStruct%x", contract, keccak256([]byte(t.String()))[:4])
+	}
+
+	var fields []string
+	used := map[string]bool{}
+	for i, component := range param.Components {
+		key := component.Name
+		if key == "" {
+			key = fmt.Sprintf("field%d", i)
+		}
+		t, err := g.goType(contract, component)
+		if err != nil {
+			return "", err
+		}
+		fields = append(fields, fmt.Sprintf("\t%s %s `abi:%q`\n", uniqueName(goExportedName(key), used), t, component.Name))
+	}
+
+	definition := fmt.Sprintf("// %s is the Go type of the Solidity %s.\ntype %s struct {\n%s}\n\n",
+		name, strings.TrimSuffix(param.InternalType, "[]"), name, strings.Join(fields, ""))
+	if param.InternalType == "" {
+		definition = fmt.Sprintf("// %s is the Go type of a Solidity tuple.\ntype %s struct {\n%s}\n\n", name, name, strings.Join(fields, ""))
+	}
+	g.structs[name] = definition
+	return name, nil
+}
+
+// goExportedName converts a Solidity identifier to an exported Go identifier, e.g. "is_active" to "IsActive".
+func goExportedName(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		if r == '_' || r == '$' {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+
+	result := b.String()
+	if result == "" || unicode.IsDigit([]rune(result)[0]) {
+		result = "X" + result
+	}
+	return result
+}
+
+// goParamName converts a Solidity parameter name to an unexported Go identifier that doesn't clash
+// with keywords, predeclared identifiers or the names used by the generated code.
+func goParamName(name string, index int) string {
+	name = strings.Trim(name, "_$")
+	if name == "" {
+		return fmt.Sprintf("arg%d", index)
+	}
+
+	runes := []rune(name)
+	runes[0] = unicode.ToLower(runes[0])
+	name = string(runes)
+
+	if token.IsKeyword(name) || goReservedParams[name] {
+		name += "_"
+	}
+	return name
+}
+
+// goReservedParams are identifiers parameters must not shadow in the generated code.
+var goReservedParams = map[string]bool{
+	"abi": true, "args": true, "backend": true, "bin": true, "c": true, "ctx": true, "err": true, "event": true,
+	"events": true, "fromBlock": true, "gosolc": true, "hash": true, "libraries": true, "log": true, "logs": true,
+	"out": true, "references": true, "toBlock": true, "value": true, "values": true,
+	"big": true, "bool": true, "byte": true, "context": true, "errors": true, "json": true, "string": true,
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true, "uint": true, "uint8": true,
+	"uint16": true, "uint32": true, "uint64": true, "nil": true, "true": true, "false": true, "len": true,
+	"make": true, "new": true, "append": true,
+}
+
+// uniqueName returns name, or name suffixed with a number if it is already used, and marks it used.
+func uniqueName(name string, used map[string]bool) string {
+	unique := name
+	for i := 0; used[unique]; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+	used[unique] = true
+	return unique
+}
+
+// sortedStringKeys returns the keys of a map in ascending order.
+func sortedStringKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,87 @@
+package gosolc
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update", false, "update the golden files")
+
+func TestGenerateGoBindings(t *testing.T) {
+	src, err := GenerateGoBindings(loadTestOutput(t, "bindings_output.json"), "contracts")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	golden := "testdata/bindings/bindings.go.golden"
+	if *updateGolden {
+		if err := os.WriteFile(golden, src, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(src, expected) {
+		t.Errorf("generated bindings differ from %s, run go test -run TestGenerateGoBindings -update to update it", golden)
+	}
+
+	// type-check the bindings against this package, overloaded events and errors included
+	vetBindings(t, src)
+}
+
+func TestGenerateGoBindingsFieldNames(t *testing.T) {
+	output := CompilerOutput{
+		"Registry.sol": map[string]interface{}{
+			"Registry": map[string]interface{}{
+				"abi": []interface{}{
+					map[string]interface{}{"type": "function", "name": "ABI", "stateMutability": "view", "inputs": []interface{}{},
+						"outputs": []interface{}{map[string]interface{}{"name": "", "type": "string"}}},
+					map[string]interface{}{"type": "function", "name": "address", "stateMutability": "view", "inputs": []interface{}{},
+						"outputs": []interface{}{map[string]interface{}{"name": "", "type": "address"}}},
+					map[string]interface{}{"type": "function", "name": "backend", "stateMutability": "nonpayable", "inputs": []interface{}{}, "outputs": []interface{}{}},
+					map[string]interface{}{"type": "event", "name": "Registered", "anonymous": false,
+						"inputs": []interface{}{map[string]interface{}{"name": "id", "type": "uint256", "indexed": true}}},
+				},
+			},
+		},
+	}
+	src, err := GenerateGoBindings(output, "contracts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, method := range []string{"func (c *Registry) ABI0(", "func (c *Registry) Address0(", "func (c *Registry) Backend0(", "c.BoundContract.ABI.Event("} {
+		if !bytes.Contains(src, []byte(method)) {
+			t.Errorf("expected %s in the generated bindings", method)
+		}
+	}
+	vetBindings(t, src)
+}
+
+// vetBindings type-checks generated bindings against this package with go vet.
+func vetBindings(t *testing.T, src []byte) {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("skipping go vet of the generated bindings in short mode")
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command not found")
+	}
+	dir, err := os.MkdirTemp("testdata", "bindings_vet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := os.WriteFile(filepath.Join(dir, "bindings.go"), src, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if out, err := exec.Command(goBin, "vet", "./"+filepath.ToSlash(dir)).CombinedOutput(); err != nil {
+		t.Fatalf("generated bindings don't type-check: %v\n%s", err, out)
+	}
+}
@@ -0,0 +1,235 @@
+package gosolc
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// ErrNoMetadata is returned when bytecode has no CBOR metadata trailer, e.g. when compiled
+// with metadata.appendCBOR disabled.
+var ErrNoMetadata = errors.New("bytecode has no CBOR metadata trailer")
+
+// BytecodeMetadata is the CBOR metadata trailer appended by solc to creation and runtime bytecode.
+type BytecodeMetadata struct {
+	IPFS         string                 // CIDv0 ("Qm...") of the metadata JSON, with bytecodeHash "ipfs"
+	Bzzr0        string                 // Swarm hash (hex) of the metadata JSON, with bytecodeHash "bzzr0"
+	Bzzr1        string                 // Swarm hash (hex) of the metadata JSON, with bytecodeHash "bzzr1"
+	Solc         string                 // Compiler version, e.g. "0.8.29" (the full version string for prereleases)
+	Experimental bool                   // Experimental features were enabled
+	Fields       map[string]interface{} // All decoded fields, including unknown ones
+	Length       int                    // Length of the trailer in bytes, including the 2 byte length
+}
+
+// DecodeBytecodeMetadata splits the metadata trailer off creation or runtime bytecode (hex) and decodes it.
+// It returns ErrNoMetadata if the bytecode has no trailer.
+func DecodeBytecodeMetadata(bytecode string) (*BytecodeMetadata, error) {
+	code, err := decodeBytecode(bytecode)
+	if err != nil {
+		return nil, err
+	}
+
+	// creation bytecode ends with the trailer of the runtime code (unless constructor arguments were appended)
+	end := len(code)
+	if metadataStart(code) < 0 {
+		if end = metadataEnd(code); end < 0 {
+			return nil, ErrNoMetadata
+		}
+	}
+	start := metadataStart(code[:end])
+	if start < 0 {
+		return nil, ErrNoMetadata
+	}
+
+	fields, err := decodeCBORMap(code[start : end-2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid metadata trailer: %v", err)
+	}
+
+	metadata := &BytecodeMetadata{Fields: fields, Length: end - start}
+	for key, value := range fields {
+		switch key {
+		case "ipfs":
+			if b, ok := value.([]byte); ok {
+				metadata.IPFS = base58Encode(b)
+			}
+		case "bzzr0":
+			if b, ok := value.([]byte); ok {
+				metadata.Bzzr0 = hex.EncodeToString(b)
+			}
+		case "bzzr1":
+			if b, ok := value.([]byte); ok {
+				metadata.Bzzr1 = hex.EncodeToString(b)
+			}
+		case "solc":
+			switch v := value.(type) {
+			case []byte:
+				if len(v) == 3 {
+					metadata.Solc = fmt.Sprintf("%d.%d.%d", v[0], v[1], v[2])
+				}
+			case string:
+				metadata.Solc = v
+			}
+		case "experimental":
+			metadata.Experimental, _ = value.(bool)
+		}
+	}
+
+	return metadata, nil
+}
+
+// BytecodeMetadata decodes the metadata trailer of the runtime bytecode of a contract.
+// It returns ErrNoMetadata if the contract was compiled without trailer.
+func (contracts CompilerOutput) BytecodeMetadata(fqName string) (*BytecodeMetadata, error) {
+	contract, err := contracts.Contract(fqName)
+	if err != nil {
+		return nil, err
+	}
+	_, deployedBytecode, err := contractBytecodes(contract)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeBytecodeMetadata(deployedBytecode)
+}
+
+// decodeCBORMap decodes data holding exactly one CBOR map with string keys.
+func decodeCBORMap(data []byte) (map[string]interface{}, error) {
+	value, n, err := decodeCBOR(data, 0)
+	if err != nil {
+		return nil, err
+	}
+	if n != len(data) {
+		return nil, fmt.Errorf("%d trailing bytes after CBOR value", len(data)-n)
+	}
+	fields, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected a CBOR map, got %T", value)
+	}
+	return fields, nil
+}
+
+// decodeCBOR decodes the CBOR value at offset and returns it with the offset following it.
+// Unsigned and negative integers decode to uint64 or *big.Int, byte strings to []byte, text strings
+// to string, arrays to []interface{}, maps (with string keys) to map[string]interface{} and
+// simple values to bool or nil. Indefinite lengths, tags and floats are not supported.
+func decodeCBOR(data []byte, offset int) (interface{}, int, error) {
+	if offset >= len(data) {
+		return nil, 0, fmt.Errorf("unexpected end of CBOR data")
+	}
+	major := data[offset] >> 5
+	info := data[offset] & 0x1f
+	offset++
+
+	if major == 7 {
+		switch info {
+		case 20:
+			return false, offset, nil
+		case 21:
+			return true, offset, nil
+		case 22, 23:
+			return nil, offset, nil
+		}
+		return nil, 0, fmt.Errorf("unsupported CBOR simple value %d", info)
+	}
+
+	var argument uint64
+	switch {
+	case info < 24:
+		argument = uint64(info)
+	case info <= 27:
+		size := 1 << (info - 24)
+		if offset+size > len(data) {
+			return nil, 0, fmt.Errorf("unexpected end of CBOR data")
+		}
+		var buf [8]byte
+		copy(buf[8-size:], data[offset:offset+size])
+		argument = binary.BigEndian.Uint64(buf[:])
+		offset += size
+	default:
+		return nil, 0, fmt.Errorf("unsupported CBOR additional information %d", info)
+	}
+
+	switch major {
+	case 0:
+		return argument, offset, nil
+	case 1:
+		n := new(big.Int).SetUint64(argument)
+		return n.Neg(n).Sub(n, big.NewInt(1)), offset, nil
+	case 2, 3:
+		if argument > uint64(len(data)-offset) {
+			return nil, 0, fmt.Errorf("unexpected end of CBOR data")
+		}
+		end := offset + int(argument)
+		if major == 3 {
+			return string(data[offset:end]), end, nil
+		}
+		return append([]byte{}, data[offset:end]...), end, nil
+	case 4:
+		if argument > uint64(len(data)-offset) {
+			return nil, 0, fmt.Errorf("invalid CBOR array length %d", argument)
+		}
+		items := make([]interface{}, 0, argument)
+		for i := uint64(0); i < argument; i++ {
+			item, next, err := decodeCBOR(data, offset)
+			if err != nil {
+				return nil, 0, err
+			}
+			items = append(items, item)
+			offset = next
+		}
+		return items, offset, nil
+	case 5:
+		if argument > uint64(len(data)-offset) {
+			return nil, 0, fmt.Errorf("invalid CBOR map length %d", argument)
+		}
+		fields := make(map[string]interface{}, argument)
+		for i := uint64(0); i < argument; i++ {
+			key, next, err := decodeCBOR(data, offset)
+			if err != nil {
+				return nil, 0, err
+			}
+			name, ok := key.(string)
+			if !ok {
+				return nil, 0, fmt.Errorf("unsupported CBOR map key %T", key)
+			}
+			value, next, err := decodeCBOR(data, next)
+			if err != nil {
+				return nil, 0, err
+			}
+			fields[name] = value
+			offset = next
+		}
+		return fields, offset, nil
+	}
+	return nil, 0, fmt.Errorf("unsupported CBOR major type %d", major)
+}
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// base58Encode encodes data with the bitcoin base58 alphabet used by IPFS.
+func base58Encode(data []byte) string {
+	n := new(big.Int).SetBytes(data)
+	radix := big.NewInt(58)
+	mod := new(big.Int)
+
+	var encoded []byte
+	for n.Sign() > 0 {
+		n.DivMod(n, radix, mod)
+		encoded = append(encoded, base58Alphabet[mod.Int64()])
+	}
+	for _, b := range data {
+		if b != 0 {
+			break
+		}
+		encoded = append(encoded, base58Alphabet[0])
+	}
+
+	var b strings.Builder
+	for i := len(encoded) - 1; i >= 0; i-- {
+		b.WriteByte(encoded[i])
+	}
+	return b.String()
+}
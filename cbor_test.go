@@ -0,0 +1,59 @@
+package gosolc
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDecodeBytecodeMetadata(t *testing.T) {
+	contracts := loadTestOutput(t, "testdata_output.json")
+
+	metadata, err := contracts.BytecodeMetadata("dummy_ERC20.sol:ERC20")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if metadata.IPFS != "QmNt74vG3qYkseB5m2TYb3VGiXsosbGUv5jwt77cLvQQAz" || metadata.Solc != "0.8.29" || metadata.Length != 53 {
+		t.Errorf("unexpected metadata %+v", metadata)
+	}
+
+	// creation bytecode ends with the trailer of the runtime code
+	contract, _ := contracts.Contract("dummy_ERC20.sol:ERC20")
+	bytecode, _, _ := contractBytecodes(contract)
+	creationMetadata, err := DecodeBytecodeMetadata(bytecode + strings.Repeat("00", 64))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if creationMetadata.IPFS != metadata.IPFS {
+		t.Errorf("expected the runtime metadata, got %+v", creationMetadata)
+	}
+
+	// bytecodeHash "bzzr1" with experimental features
+	trailer := "a3" + "65" + hex.EncodeToString([]byte("bzzr1")) + "5820" + strings.Repeat("ab", 32) +
+		"6c" + hex.EncodeToString([]byte("experimental")) + "f5" +
+		"64" + hex.EncodeToString([]byte("solc")) + "4300081d" + "0040"
+	metadata, err = DecodeBytecodeMetadata("0x6080604052fe" + trailer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if metadata.Bzzr1 != strings.Repeat("ab", 32) || !metadata.Experimental || metadata.Solc != "0.8.29" {
+		t.Errorf("unexpected metadata %+v", metadata)
+	}
+
+	// prereleases record the full version string, constructor arguments may follow the creation trailer
+	trailer = "a2" + "64" + hex.EncodeToString([]byte("ipfs")) + "5822" + "1220" + strings.Repeat("cd", 32) +
+		"64" + hex.EncodeToString([]byte("solc")) + "78" + "1e" + hex.EncodeToString([]byte("0.8.30-develop.2025.4.1+commit")) + "004f"
+	metadata, err = DecodeBytecodeMetadata("0x6080604052fe" + trailer + strings.Repeat("00", 64))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if metadata.Solc != "0.8.30-develop.2025.4.1+commit" || metadata.Length != 81 {
+		t.Errorf("unexpected prerelease metadata %+v", metadata)
+	}
+
+	// appendCBOR disabled
+	if _, err := DecodeBytecodeMetadata("0x6080604052348015600e575f5ffd5b50"); !errors.Is(err, ErrNoMetadata) {
+		t.Errorf("expected ErrNoMetadata, got %v", err)
+	}
+}
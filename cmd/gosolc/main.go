@@ -0,0 +1,611 @@
+// Command gosolc compiles Solidity contracts with the embedded solc-js compiler.
+//
+// Usage:
+//
+//	gosolc build -contracts ./contracts [flags]
+//	gosolc bindings -contracts ./contracts -pkg contracts -out bindings.go [flags]
+//	gosolc storage -contracts ./contracts -contract Token.sol:Token [-old ./contracts-v1] [flags]
+//	gosolc export -contracts ./contracts -contract Token.sol:Token -format sourcify -out ./verify [flags]
+//	gosolc upgrades -contracts ./contracts -contract Vault.sol:Vault [flags]
+//	gosolc abidiff -contracts ./contracts -old ./contracts-v1 [-version v1.4.2] [flags]
+//	gosolc interfaces -contracts ./contracts [-contract Token.sol:Token] [-standard ERC-20] [flags]
+//	gosolc sizes -contracts ./contracts [-chain ethereum] [-margin 1024] [flags]
+//	gosolc gas -contracts ./contracts [-snapshot .gas-snapshot] [-threshold 5] [-update] [flags]
+//	gosolc runs -contracts ./contracts -contract Token.sol:Token [-budget 24576] [-out curve.json] [flags]
+//	gosolc selectors -contracts ./contracts -group proxy=Proxy.sol:Proxy,Vault.sol:Vault [-diamond A.sol:A,B.sol:B] [flags]
+//
+// The bindings command is meant to be used from go:generate:
+//
+//	//go:generate go run github.com/0xsharma/gosolc/cmd/gosolc bindings -contracts ./contracts -pkg contracts -out bindings.go
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/0xsharma/gosolc"
+)
+
+// commands maps subcommand names to their implementation.
+var commands = map[string]func(args []string) error{
+	"build":      buildCommand,
+	"bindings":   bindingsCommand,
+	"export":     exportCommand,
+	"storage":    storageCommand,
+	"upgrades":   upgradesCommand,
+	"abidiff":    abidiffCommand,
+	"selectors":  selectorsCommand,
+	"interfaces": interfacesCommand,
+	"sizes":      sizesCommand,
+	"runs":       runsCommand,
+	"gas":        gasCommand,
+}
+
+func main() {
+	if len(os.Args) < 2 || commands[os.Args[1]] == nil {
+		fmt.Fprintf(os.Stderr, "usage: gosolc <command> [flags]\n\ncommands:\n")
+		fmt.Fprintf(os.Stderr, "  build      compile contracts and write the output to ./solc-go-build\n")
+		fmt.Fprintf(os.Stderr, "  bindings   generate Go bindings for compiled contracts\n")
+		fmt.Fprintf(os.Stderr, "  storage    print the storage layout of a contract, or compare it with an older version\n")
+		fmt.Fprintf(os.Stderr, "  upgrades   check that a contract is safe to use as a proxy implementation\n")
+		fmt.Fprintf(os.Stderr, "  abidiff    compare the ABIs with an older build and recommend a version bump\n")
+		fmt.Fprintf(os.Stderr, "  selectors  check selector collisions of proxies and diamond facets, print diamond cut tables\n")
+		fmt.Fprintf(os.Stderr, "  interfaces print ERC-165 interface ids, or check a contract against standard interfaces\n")
+		fmt.Fprintf(os.Stderr, "  sizes      print the runtime and initcode sizes of contracts against the limits of a chain\n")
+		fmt.Fprintf(os.Stderr, "  gas        print the gas estimates of contracts, or compare them with a snapshot\n")
+		fmt.Fprintf(os.Stderr, "  runs       search the largest optimizer runs value keeping a contract under a size budget\n")
+		fmt.Fprintf(os.Stderr, "  export     export the verification input of a contract for Etherscan or Sourcify\n")
+		os.Exit(2)
+	}
+
+	if err := commands[os.Args[1]](os.Args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "gosolc %s: %v\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
+
+// compilerFlags registers the flags shared by the commands compiling a contracts directory.
+type compilerFlags struct {
+	contracts  *string
+	evmVersion *string
+	optimize   *bool
+	runs       *uint
+	solcJs     *string
+}
+
+func newCompilerFlags(fs *flag.FlagSet) *compilerFlags {
+	return &compilerFlags{
+		contracts:  fs.String("contracts", "contracts", "directory containing the Solidity contracts"),
+		evmVersion: fs.String("evm", "cancun", "EVM version to compile for"),
+		optimize:   fs.Bool("optimize", false, "enable the optimizer"),
+		runs:       fs.Uint("runs", 200, "optimizer runs"),
+		solcJs:     fs.String("solc", "", "path to a solc-js (soljson) file, defaults to the embedded version"),
+	}
+}
+
+func (f *compilerFlags) compiler() (*gosolc.Compiler, error) {
+	config := gosolc.NewCompilerConfig(*f.evmVersion, *f.optimize, *f.runs)
+	return gosolc.NewCompiler(*f.contracts, config, *f.solcJs)
+}
+
+func buildCommand(args []string) error {
+	fs := flag.NewFlagSet("build", flag.ExitOnError)
+	cf := newCompilerFlags(fs)
+	fs.Parse(args)
+
+	compiler, err := cf.compiler()
+	if err != nil {
+		return err
+	}
+	return compiler.CompileAndWriteOutput()
+}
+
+func bindingsCommand(args []string) error {
+	fs := flag.NewFlagSet("bindings", flag.ExitOnError)
+	cf := newCompilerFlags(fs)
+	pkg := fs.String("pkg", "", "package name of the generated file (required)")
+	out := fs.String("out", "", "output file, defaults to stdout")
+	only := fs.String("only", "", "comma separated contract names to generate bindings for, defaults to all")
+	fs.Parse(args)
+
+	if *pkg == "" {
+		return fmt.Errorf("-pkg is required")
+	}
+
+	compiler, err := cf.compiler()
+	if err != nil {
+		return err
+	}
+	output, err := compiler.Compile()
+	if err != nil {
+		return err
+	}
+
+	var contracts []string
+	if *only != "" {
+		contracts = strings.Split(*only, ",")
+	}
+	src, err := gosolc.GenerateGoBindings(output, *pkg, contracts...)
+	if err != nil {
+		return err
+	}
+
+	if *out == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+	return os.WriteFile(*out, src, 0644)
+}
+
+func exportCommand(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	cf := newCompilerFlags(fs)
+	contract := fs.String("contract", "", "fully qualified name of the contract, e.g. Token.sol:Token (required)")
+	format := fs.String("format", "etherscan", "export format: etherscan (standard JSON input) or sourcify (metadata.json and sources)")
+	out := fs.String("out", "", "output file (etherscan) or directory (sourcify), defaults to stdout for etherscan")
+	fs.Parse(args)
+
+	if *contract == "" {
+		return fmt.Errorf("-contract is required")
+	}
+
+	compiler, err := cf.compiler()
+	if err != nil {
+		return err
+	}
+
+	switch *format {
+	case "etherscan":
+		input, err := compiler.StandardJSONInput(*contract)
+		if err != nil {
+			return err
+		}
+		if *out == "" {
+			_, err = os.Stdout.Write(input)
+			return err
+		}
+		return os.WriteFile(*out, input, 0644)
+	case "sourcify":
+		if *out == "" {
+			return fmt.Errorf("-out is required for the sourcify format")
+		}
+		output, err := compiler.Compile()
+		if err != nil {
+			return err
+		}
+		bundle, err := compiler.SourcifyBundle(output, *contract)
+		if err != nil {
+			return err
+		}
+		return bundle.WriteDir(*out)
+	}
+	return fmt.Errorf("unknown format %q", *format)
+}
+
+func storageCommand(args []string) error {
+	fs := flag.NewFlagSet("storage", flag.ExitOnError)
+	cf := newCompilerFlags(fs)
+	contract := fs.String("contract", "", "fully qualified name of the contract, e.g. Token.sol:Token (required)")
+	old := fs.String("old", "", "contracts directory of the deployed version to compare the layout with")
+	fs.Parse(args)
+
+	if *contract == "" {
+		return fmt.Errorf("-contract is required")
+	}
+
+	layout, err := cf.storageLayout(*cf.contracts, *contract)
+	if err != nil {
+		return err
+	}
+	if *old == "" {
+		fmt.Print(layout.Table())
+		return nil
+	}
+
+	oldLayout, err := cf.storageLayout(*old, *contract)
+	if err != nil {
+		return err
+	}
+	breaking := 0
+	for _, change := range gosolc.CompareStorageLayouts(oldLayout, layout) {
+		fmt.Println(change)
+		if change.Breaking() {
+			breaking++
+		}
+	}
+	if breaking > 0 {
+		return fmt.Errorf("%d incompatible storage changes", breaking)
+	}
+	return nil
+}
+
+// storageLayout compiles a contracts directory with the flags and returns the storage layout of a contract,
+// with the @custom:oz-renamed-from annotations of its variables.
+func (f *compilerFlags) storageLayout(contractsDir, contract string) (*gosolc.StorageLayout, error) {
+	config := gosolc.NewCompilerConfig(*f.evmVersion, *f.optimize, *f.runs)
+	compiler, err := gosolc.NewCompiler(contractsDir, config, *f.solcJs)
+	if err != nil {
+		return nil, err
+	}
+	output, err := compiler.CompileStandard()
+	if err != nil {
+		return nil, err
+	}
+	return output.StorageLayout(contract)
+}
+
+func upgradesCommand(args []string) error {
+	fs := flag.NewFlagSet("upgrades", flag.ExitOnError)
+	cf := newCompilerFlags(fs)
+	contract := fs.String("contract", "", "fully qualified name of the implementation contract, e.g. Token.sol:Token (required)")
+	fs.Parse(args)
+
+	if *contract == "" {
+		return fmt.Errorf("-contract is required")
+	}
+
+	compiler, err := cf.compiler()
+	if err != nil {
+		return err
+	}
+	output, err := compiler.CompileStandard()
+	if err != nil {
+		return err
+	}
+	violations, err := output.ValidateUpgradeSafety(*contract)
+	if err != nil {
+		return err
+	}
+	for _, violation := range violations {
+		fmt.Println(violation)
+	}
+	if len(violations) > 0 {
+		return fmt.Errorf("%d upgrade safety violations", len(violations))
+	}
+	return nil
+}
+
+func abidiffCommand(args []string) error {
+	fs := flag.NewFlagSet("abidiff", flag.ExitOnError)
+	cf := newCompilerFlags(fs)
+	old := fs.String("old", "", "contracts directory of the previous release")
+	oldArtifacts := fs.String("old-artifacts", "", "artifacts directory of the previous release, instead of -old")
+	artifacts := fs.String("artifacts", "", "artifacts directory to compare instead of compiling -contracts")
+	version := fs.String("version", "", "version of the previous release, to print the next version")
+	allowBreaking := fs.Bool("allow-breaking", false, "don't fail on breaking changes")
+	fs.Parse(args)
+
+	if (*old == "") == (*oldArtifacts == "") {
+		return fmt.Errorf("one of -old and -old-artifacts is required")
+	}
+
+	oldOutput, err := cf.output(*old, *oldArtifacts)
+	if err != nil {
+		return err
+	}
+	newOutput, err := cf.output(*cf.contracts, *artifacts)
+	if err != nil {
+		return err
+	}
+	changes, err := gosolc.CompareOutputABIs(oldOutput, newOutput)
+	if err != nil {
+		return err
+	}
+
+	breaking := 0
+	for _, change := range changes {
+		fmt.Printf("%s: %v\n", change.Contract, change)
+		if change.Breaking() {
+			breaking++
+		}
+	}
+	bump := gosolc.RecommendSemverBump(changes)
+	if *version != "" {
+		next, err := bump.Next(*version)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("recommended version: %s (%s)\n", next, bump)
+	} else {
+		fmt.Printf("recommended bump: %s\n", bump)
+	}
+
+	if breaking > 0 && !*allowBreaking {
+		return fmt.Errorf("%d breaking ABI changes", breaking)
+	}
+	return nil
+}
+
+// output reads an artifacts directory if set, or compiles a contracts directory with the flags.
+func (f *compilerFlags) output(contractsDir, artifactsDir string) (gosolc.CompilerOutput, error) {
+	if artifactsDir != "" {
+		return gosolc.ReadArtifacts(artifactsDir)
+	}
+	config := gosolc.NewCompilerConfig(*f.evmVersion, *f.optimize, *f.runs)
+	compiler, err := gosolc.NewCompiler(contractsDir, config, *f.solcJs)
+	if err != nil {
+		return nil, err
+	}
+	return compiler.Compile()
+}
+
+// groupFlags collects the repeated -group flags of the selectors command.
+type groupFlags []gosolc.SelectorGroup
+
+func (g *groupFlags) String() string {
+	return fmt.Sprint(*g)
+}
+
+func (g *groupFlags) Set(value string) error {
+	name, contracts, ok := strings.Cut(value, "=")
+	if !ok || name == "" || contracts == "" {
+		return fmt.Errorf("expected name=Contract1,Contract2")
+	}
+	*g = append(*g, gosolc.SelectorGroup{Name: name, Contracts: strings.Split(contracts, ",")})
+	return nil
+}
+
+func selectorsCommand(args []string) error {
+	fs := flag.NewFlagSet("selectors", flag.ExitOnError)
+	cf := newCompilerFlags(fs)
+	var groups groupFlags
+	fs.Var(&groups, "group", "contracts behind a single address, as name=Contract1,Contract2 (repeatable)")
+	diamond := fs.String("diamond", "", "comma separated facets of a diamond, to print its cut table")
+	abiOut := fs.String("abi", "", "file to write the merged ABI of the diamond to")
+	fs.Parse(args)
+
+	if len(groups) == 0 && *diamond == "" {
+		return fmt.Errorf("-group or -diamond is required")
+	}
+
+	compiler, err := cf.compiler()
+	if err != nil {
+		return err
+	}
+	output, err := compiler.Compile()
+	if err != nil {
+		return err
+	}
+
+	collisions, err := output.SelectorCollisions(groups...)
+	if err != nil {
+		return err
+	}
+	if *diamond != "" {
+		d, err := output.Diamond(strings.Split(*diamond, ",")...)
+		if err != nil {
+			return err
+		}
+		fmt.Print(d.Table())
+		collisions = append(collisions, d.Collisions...)
+
+		if *abiOut != "" {
+			data, err := json.MarshalIndent(d.ABI, "", "  ")
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(*abiOut, data, 0644); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, collision := range collisions {
+		fmt.Println(collision)
+	}
+	if len(collisions) > 0 {
+		return fmt.Errorf("%d selector collisions", len(collisions))
+	}
+	return nil
+}
+
+func interfacesCommand(args []string) error {
+	fs := flag.NewFlagSet("interfaces", flag.ExitOnError)
+	cf := newCompilerFlags(fs)
+	contract := fs.String("contract", "", "contract to check against the standards, e.g. Token.sol:Token")
+	standard := fs.String("standard", "", "standard to check the contract against, e.g. ERC-20; defaults to listing the standards it conforms to")
+	fs.Parse(args)
+
+	compiler, err := cf.compiler()
+	if err != nil {
+		return err
+	}
+	output, err := compiler.Compile()
+	if err != nil {
+		return err
+	}
+
+	if *contract == "" {
+		ids, err := output.InterfaceIDs()
+		if err != nil {
+			return err
+		}
+		names := make([]string, 0, len(ids))
+		for name := range ids {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Printf("%s  %s\n", ids[name], name)
+		}
+		return nil
+	}
+
+	if *standard == "" {
+		standards, err := output.Standards(*contract)
+		if err != nil {
+			return err
+		}
+		fmt.Println(strings.Join(standards, "\n"))
+		return nil
+	}
+
+	report, err := output.CheckConformance(*contract, *standard)
+	if err != nil {
+		return err
+	}
+	for _, issue := range report.Issues {
+		fmt.Println(issue)
+	}
+	if !report.Conforms() {
+		return fmt.Errorf("%s does not conform to %s", *contract, *standard)
+	}
+	return nil
+}
+
+func sizesCommand(args []string) error {
+	fs := flag.NewFlagSet("sizes", flag.ExitOnError)
+	cf := newCompilerFlags(fs)
+	chain := fs.String("chain", "ethereum", "size profile of the chain to check the sizes against")
+	margin := fs.Int("margin", -1, "warn about sizes within this many bytes of a limit, defaults to the margin of the profile")
+	fs.Parse(args)
+
+	limits, err := gosolc.SizeProfile(*chain)
+	if err != nil {
+		return err
+	}
+	if *margin >= 0 {
+		limits.Margin = *margin
+	}
+
+	compiler, err := cf.compiler()
+	if err != nil {
+		return err
+	}
+	output, err := compiler.Compile()
+	if err != nil {
+		return err
+	}
+	sizes, err := output.ContractSizes(limits)
+	if err != nil {
+		return err
+	}
+	fmt.Print(gosolc.SizeTable(sizes))
+
+	exceeded := 0
+	for _, size := range sizes {
+		if size.Status() == gosolc.SizeExceeded {
+			exceeded++
+		}
+	}
+	if exceeded > 0 {
+		return fmt.Errorf("%d contracts exceed the %s size limits", exceeded, *chain)
+	}
+	return nil
+}
+
+func runsCommand(args []string) error {
+	fs := flag.NewFlagSet("runs", flag.ExitOnError)
+	cf := newCompilerFlags(fs)
+	contract := fs.String("contract", "", "fully qualified name of the contract, e.g. Token.sol:Token (required)")
+	runs := fs.String("values", "", "comma separated runs values to try, defaults to 1 through 1000000")
+	budget := fs.Int("budget", 0, "maximum runtime size in bytes, defaults to the EIP-170 limit")
+	parallel := fs.Int("parallel", 0, "number of concurrent compilations, defaults to the number of CPUs")
+	out := fs.String("out", "", "file to write the JSON trade-off curve to")
+	fs.Parse(args)
+
+	if *contract == "" {
+		return fmt.Errorf("-contract is required")
+	}
+	search := gosolc.RunsSearch{Contract: *contract, SizeBudget: *budget, Parallelism: *parallel}
+	if *runs != "" {
+		for _, value := range strings.Split(*runs, ",") {
+			r, err := strconv.ParseUint(strings.TrimSpace(value), 10, 32)
+			if err != nil {
+				return fmt.Errorf("invalid runs value %q", value)
+			}
+			search.Runs = append(search.Runs, uint(r))
+		}
+	}
+
+	compiler, err := cf.compiler()
+	if err != nil {
+		return err
+	}
+	curve, err := compiler.SearchOptimizerRuns(search)
+	if err != nil {
+		return err
+	}
+
+	for _, sample := range curve.Samples {
+		fmt.Printf("runs %-8d runtime %6d bytes  initcode %6d bytes  fits %v\n", sample.Runs, sample.RuntimeSize, sample.InitcodeSize, sample.FitsBudget)
+	}
+	if *out != "" {
+		data, err := curve.JSON()
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(*out, data, 0644); err != nil {
+			return err
+		}
+	}
+	if curve.Recommended == nil {
+		return fmt.Errorf("no runs value keeps %s within %d bytes of runtime and %d bytes of initcode", *contract, curve.SizeBudget, curve.InitcodeBudget)
+	}
+	fmt.Printf("recommended runs: %d\n", *curve.Recommended)
+	return nil
+}
+
+func gasCommand(args []string) error {
+	fs := flag.NewFlagSet("gas", flag.ExitOnError)
+	cf := newCompilerFlags(fs)
+	contract := fs.String("contract", "", "report only this contract, defaults to all deployable contracts")
+	snapshot := fs.String("snapshot", "", "gas snapshot file to compare the estimates with")
+	threshold := fs.Float64("threshold", 5, "percentage above which an increase of the snapshot estimates is a regression")
+	update := fs.Bool("update", false, "write the estimates to the snapshot file instead of comparing them")
+	fs.Parse(args)
+
+	compiler, err := cf.compiler()
+	if err != nil {
+		return err
+	}
+	output, err := compiler.Compile()
+	if err != nil {
+		return err
+	}
+	var contracts []string
+	if *contract != "" {
+		contracts = append(contracts, *contract)
+	}
+	report, err := output.GasReport(contracts...)
+	if err != nil {
+		return err
+	}
+	fmt.Print(report.Table())
+
+	if *snapshot == "" {
+		return nil
+	}
+	if *update {
+		data, err := json.MarshalIndent(report.Snapshot(), "", "  ")
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(*snapshot, data, 0644)
+	}
+	data, err := os.ReadFile(*snapshot)
+	if err != nil {
+		return err
+	}
+	previous, err := gosolc.ParseGasSnapshot(data)
+	if err != nil {
+		return err
+	}
+
+	regressions := 0
+	for _, change := range report.Compare(previous, *threshold) {
+		fmt.Println(change)
+		if change.Regression {
+			regressions++
+		}
+	}
+	if regressions > 0 {
+		return fmt.Errorf("%d gas regressions above %v%% compared to %s", regressions, *threshold, *snapshot)
+	}
+	return nil
+}
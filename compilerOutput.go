@@ -1,6 +1,13 @@
 package gosolc
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
 
 // CompilerOutput represents the output of the Solidity compiler.
 // It is a map where the keys are file names and the values are maps of contract names to their respective output data.
@@ -51,3 +58,95 @@ func (contracts CompilerOutput) GetDeployedContractByteCodes() (map[string]strin
 
 	return contractByteCodes, nil
 }
+
+// Contract returns the output of a single contract.
+// fqName is the fully qualified name of the contract (e.g. "dummy_token.sol:Token"), or its bare name
+// (e.g. "Token") if that name is unique in the output.
+func (contracts CompilerOutput) Contract(fqName string) (map[string]interface{}, error) {
+	_, contract, err := contracts.resolveContract(fqName)
+	return contract, err
+}
+
+// resolveContract looks up a contract by fully qualified or unique bare name and returns its fully qualified name and output.
+func (contracts CompilerOutput) resolveContract(fqName string) (string, map[string]interface{}, error) {
+	if strings.Contains(fqName, ":") {
+		file, name := splitFullyQualifiedName(fqName)
+		fileContracts, ok := contracts[file].(map[string]interface{})
+		if !ok {
+			return "", nil, fmt.Errorf("source %s not found in compiler output", file)
+		}
+		contract, ok := fileContracts[name].(map[string]interface{})
+		if !ok {
+			return "", nil, fmt.Errorf("contract %s not found in compiler output", fqName)
+		}
+		return fqName, contract, nil
+	}
+
+	var matches []string
+	var found map[string]interface{}
+	for file, fileContracts := range contracts {
+		if contract, ok := fileContracts.(map[string]interface{})[fqName].(map[string]interface{}); ok {
+			matches = append(matches, file+":"+fqName)
+			found = contract
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", nil, fmt.Errorf("contract %s not found in compiler output", fqName)
+	case 1:
+		return matches[0], found, nil
+	default:
+		sort.Strings(matches)
+		return "", nil, fmt.Errorf("contract name %s is ambiguous, use one of: %s", fqName, strings.Join(matches, ", "))
+	}
+}
+
+// FullyQualifiedNames returns the sorted fully qualified names (file.sol:Name) of all contracts in the output.
+func (contracts CompilerOutput) FullyQualifiedNames() []string {
+	var names []string
+	for file, fileContracts := range contracts {
+		for name := range fileContracts.(map[string]interface{}) {
+			names = append(names, file+":"+name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ReadArtifacts reads the JSON artifacts written by CompileAndWriteOutput (one <Contract>.json file per contract)
+// back into a CompilerOutput. Contracts are keyed by the compilation target of their metadata, or by the
+// artifact file name (e.g. "Token.json:Token") for artifacts built without metadata.
+func ReadArtifacts(dir string) (CompilerOutput, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	contracts := CompilerOutput{}
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read artifact: %w", err)
+		}
+		var contract map[string]interface{}
+		if err := json.Unmarshal(data, &contract); err != nil {
+			return nil, fmt.Errorf("invalid artifact %s: %v", path, err)
+		}
+
+		file, name := filepath.Base(path), strings.TrimSuffix(filepath.Base(path), ".json")
+		if metadataJSON, ok := contract["metadata"].(string); ok {
+			if metadata, err := ParseContractMetadata([]byte(metadataJSON)); err == nil {
+				if target, err := metadata.Target(); err == nil {
+					file, name = splitFullyQualifiedName(target)
+				}
+			}
+		}
+
+		if contracts[file] == nil {
+			contracts[file] = map[string]interface{}{}
+		}
+		contracts[file].(map[string]interface{})[name] = contract
+	}
+	return contracts, nil
+}
@@ -0,0 +1,240 @@
+package gosolc
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// ContentHash holds the content addresses solc computes for the metadata and the sources it lists.
+type ContentHash struct {
+	Keccak256 string // 0x prefixed keccak256 hash
+	IPFS      string // CIDv0 ("Qm...") of the content added to IPFS as a UnixFS file
+	Bzzr1     string // Swarm hash (hex) of the content, as in the bzzr1 metadata field
+}
+
+// HashContent computes the keccak256, IPFS and Swarm hashes of data the way solc does.
+func HashContent(data []byte) *ContentHash {
+	return &ContentHash{
+		Keccak256: "0x" + hex.EncodeToString(keccak256(data)),
+		IPFS:      IPFSHash(data),
+		Bzzr1:     hex.EncodeToString(SwarmHash(data)),
+	}
+}
+
+// URLs returns the urls listed for the content in the metadata: "bzz-raw://<hash>" and "dweb:/ipfs/<cid>".
+func (h *ContentHash) URLs() []string {
+	return []string{"bzz-raw://" + h.Bzzr1, "dweb:/ipfs/" + h.IPFS}
+}
+
+// ipfsChunkSize is the maximum size of the data of a UnixFS leaf, and ipfsMaxLinks the maximum number of links of
+// an intermediate node, the defaults of `ipfs add` used by solc.
+const (
+	ipfsChunkSize = 256 * 1024
+	ipfsMaxLinks  = 174
+)
+
+// ipfsNode is a DAG-PB node of the UnixFS file tree.
+type ipfsNode struct {
+	hash      []byte // sha256 multihash of the serialized node
+	size      int    // Size of the file data below the node
+	blockSize int    // Cumulative size of the serialized node and its children
+}
+
+// IPFSHash returns the CIDv0 of data added to IPFS as a UnixFS file: chunked into 256KiB leaves, balanced
+// DAG-PB nodes with up to 174 links and a sha256 multihash, encoded with base58.
+func IPFSHash(data []byte) string {
+	var level []ipfsNode
+	for offset := 0; offset == 0 || offset < len(data); offset += ipfsChunkSize {
+		chunk := data[offset:min(offset+ipfsChunkSize, len(data))]
+
+		unixfs := []byte{0x08, 0x02} // Type: File
+		if len(chunk) > 0 {
+			unixfs = append(unixfs, 0x12)
+			unixfs = binary.AppendUvarint(unixfs, uint64(len(chunk)))
+			unixfs = append(unixfs, chunk...)
+		}
+		unixfs = append(unixfs, 0x18)
+		unixfs = binary.AppendUvarint(unixfs, uint64(len(chunk)))
+
+		block := protobufBytes(nil, 0x0a, unixfs)
+		level = append(level, ipfsNode{hash: ipfsMultihash(block), size: len(chunk), blockSize: len(block)})
+	}
+
+	for len(level) > 1 {
+		var next []ipfsNode
+		for start := 0; start < len(level); start += ipfsMaxLinks {
+			next = append(next, ipfsCombine(level[start:min(start+ipfsMaxLinks, len(level))]))
+		}
+		level = next
+	}
+	return base58Encode(level[0].hash)
+}
+
+// ipfsCombine returns the intermediate node linking to children.
+func ipfsCombine(children []ipfsNode) ipfsNode {
+	var node ipfsNode
+	var links, blockSizes []byte
+	for _, child := range children {
+		node.size += child.size
+		node.blockSize += child.blockSize
+
+		// PBLink: Hash, empty Name and Tsize
+		link := protobufBytes(nil, 0x0a, child.hash)
+		link = append(link, 0x12, 0x00, 0x18)
+		link = binary.AppendUvarint(link, uint64(child.blockSize))
+		links = protobufBytes(links, 0x12, link)
+
+		blockSizes = append(blockSizes, 0x20)
+		blockSizes = binary.AppendUvarint(blockSizes, uint64(child.size))
+	}
+
+	unixfs := []byte{0x08, 0x02, 0x18}
+	unixfs = binary.AppendUvarint(unixfs, uint64(node.size))
+	unixfs = append(unixfs, blockSizes...)
+
+	block := protobufBytes(links, 0x0a, unixfs)
+	node.blockSize += len(block)
+	node.hash = ipfsMultihash(block)
+	return node
+}
+
+// ipfsMultihash returns the sha256 multihash of a block.
+func ipfsMultihash(block []byte) []byte {
+	hash := sha256.Sum256(block)
+	return append([]byte{0x12, 0x20}, hash[:]...)
+}
+
+// protobufBytes appends a length delimited protobuf field to b.
+func protobufBytes(b []byte, tag byte, data []byte) []byte {
+	b = append(b, tag)
+	b = binary.AppendUvarint(b, uint64(len(data)))
+	return append(b, data...)
+}
+
+// swarmChunkSize is the size of a Swarm chunk; intermediate chunks hold up to 128 references of 32 bytes.
+const swarmChunkSize = 4096
+
+// SwarmHash returns the Swarm hash of data used by the bzzr1 metadata field: the binary merkle tree (BMT)
+// hash of 4096 byte chunks, prefixed with the little-endian 8 byte span of the data they cover.
+func SwarmHash(data []byte) []byte {
+	if len(data) <= swarmChunkSize {
+		return swarmChunkHash(data, len(data))
+	}
+
+	// size of the subtrees referenced by the root chunk
+	subtreeSize := swarmChunkSize
+	for subtreeSize*(swarmChunkSize/32) < len(data) {
+		subtreeSize *= swarmChunkSize / 32
+	}
+
+	var references []byte
+	for offset := 0; offset < len(data); offset += subtreeSize {
+		references = append(references, SwarmHash(data[offset:min(offset+subtreeSize, len(data))])...)
+	}
+	return swarmChunkHash(references, len(data))
+}
+
+// swarmChunkHash returns the BMT hash of a chunk covering span bytes of data.
+func swarmChunkHash(chunk []byte, span int) []byte {
+	padded := make([]byte, swarmChunkSize)
+	copy(padded, chunk)
+
+	var spanBytes [8]byte
+	binary.LittleEndian.PutUint64(spanBytes[:], uint64(span))
+	return keccak256(spanBytes[:], swarmBMT(padded))
+}
+
+// swarmBMT returns the root of the binary merkle tree of data, whose 32 byte segments are hashed in pairs.
+func swarmBMT(data []byte) []byte {
+	if len(data) <= 64 {
+		return keccak256(data)
+	}
+	mid := len(data) / 2
+	return keccak256(swarmBMT(data[:mid]), swarmBMT(data[mid:]))
+}
+
+// MetadataHash returns the hashes of the metadata output of a contract, which the metadata trailer of its bytecode
+// references with bytecodeHash "ipfs" (the default) or "bzzr1".
+func (contracts CompilerOutput) MetadataHash(fqName string) (*ContentHash, error) {
+	contract, err := contracts.Contract(fqName)
+	if err != nil {
+		return nil, err
+	}
+	metadata, ok := contract["metadata"].(string)
+	if !ok || metadata == "" {
+		return nil, fmt.Errorf("metadata of contract %s not found in compiler output", fqName)
+	}
+	return HashContent([]byte(metadata)), nil
+}
+
+// SourceHashes returns the hashes of the literal content of every source of the compiler, keyed by source unit name,
+// as listed in the metadata of the contracts compiled from them.
+func (c Compiler) SourceHashes() (map[string]*ContentHash, error) {
+	contents, err := sourceContents(c.Sources)
+	if err != nil {
+		return nil, err
+	}
+	hashes := make(map[string]*ContentHash, len(contents))
+	for name, content := range contents {
+		hashes[name] = HashContent([]byte(content))
+	}
+	return hashes, nil
+}
+
+// VerifyMetadataHash checks that the metadata trailer of bytecode (hex, e.g. fetched from a chain) references the
+// metadata of a compiled contract, which proves the contract was compiled from the same sources and settings.
+func (contracts CompilerOutput) VerifyMetadataHash(fqName, bytecode string) error {
+	hash, err := contracts.MetadataHash(fqName)
+	if err != nil {
+		return err
+	}
+	trailer, err := DecodeBytecodeMetadata(bytecode)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case trailer.IPFS != "":
+		if trailer.IPFS != hash.IPFS {
+			return fmt.Errorf("bytecode references metadata %s, contract %s has metadata %s", trailer.IPFS, fqName, hash.IPFS)
+		}
+	case trailer.Bzzr1 != "":
+		if trailer.Bzzr1 != hash.Bzzr1 {
+			return fmt.Errorf("bytecode references metadata bzzr1 %s, contract %s has metadata %s", trailer.Bzzr1, fqName, hash.Bzzr1)
+		}
+	default:
+		return fmt.Errorf("bytecode metadata has no ipfs or bzzr1 hash")
+	}
+	return nil
+}
+
+// VerifySourceURLs checks the urls listed for every source in the metadata ("dweb:/ipfs/..." and "bzz-raw://...")
+// against the hashes of the contents resolved by ResolveSources.
+func (m *ContractMetadata) VerifySourceURLs(sources map[string]string) error {
+	contents, err := m.ResolveSources(sources)
+	if err != nil {
+		return err
+	}
+
+	for name, source := range m.Sources {
+		hash := HashContent([]byte(contents[name]))
+		for _, url := range source.URLs {
+			var expected string
+			switch {
+			case strings.HasPrefix(url, "dweb:/ipfs/"):
+				expected = "dweb:/ipfs/" + hash.IPFS
+			case strings.HasPrefix(url, "bzz-raw://"):
+				expected = "bzz-raw://" + hash.Bzzr1
+			default:
+				continue
+			}
+			if url != expected {
+				return fmt.Errorf("url %s of source %s does not match its content (%s)", url, name, expected)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package gosolc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestIPFSHash(t *testing.T) {
+	tests := map[string]string{
+		"":              "QmbFMke1KXqnYyBBWxB74N4c5SBnJMVAiMNRcGu6x1AwQH",
+		"hello world\n": "QmT78zSuBmuS4z925WZfrqQ1qHaJ56DQaTfyMUF7F8ff5o",
+	}
+	for data, expected := range tests {
+		if hash := IPFSHash([]byte(data)); hash != expected {
+			t.Errorf("IPFSHash(%q) = %s, expected %s", data, hash, expected)
+		}
+	}
+
+	// chunked files link their leaves from a single root
+	large := bytes.Repeat([]byte{'a'}, 3*ipfsChunkSize+1)
+	if hash := IPFSHash(large); !strings.HasPrefix(hash, "Qm") || len(hash) != 46 || hash == IPFSHash(large[:ipfsChunkSize]) {
+		t.Errorf("unexpected hash %s of chunked data", hash)
+	}
+}
+
+func TestSwarmHash(t *testing.T) {
+	if hash := hex.EncodeToString(SwarmHash(nil)); hash != "b34ca8c22b9e982354f9c7f50b470d66db428d880c8a904d5fe4ec9713171526" {
+		t.Errorf("unexpected hash %s of empty data", hash)
+	}
+
+	// a chunk and a tree covering the same data differ by their span
+	data := bytes.Repeat([]byte{'a'}, swarmChunkSize+1)
+	references := append(SwarmHash(data[:swarmChunkSize]), SwarmHash(data[swarmChunkSize:])...)
+	if !bytes.Equal(SwarmHash(data), swarmChunkHash(references, len(data))) {
+		t.Error("unexpected hash of two chunks")
+	}
+}
+
+func TestVerifySourceURLs(t *testing.T) {
+	content := "contract Token {}\n"
+	hash := HashContent([]byte(content))
+	metadata := &ContractMetadata{Sources: map[string]*MetadataSource{
+		"Token.sol": {Keccak256: hash.Keccak256, URLs: hash.URLs()},
+	}}
+	if err := metadata.VerifySourceURLs(map[string]string{"Token.sol": content}); err != nil {
+		t.Error(err)
+	}
+
+	metadata.Sources["Token.sol"].URLs = []string{"dweb:/ipfs/" + IPFSHash([]byte("contract Token { }"))}
+	if err := metadata.VerifySourceURLs(map[string]string{"Token.sol": content}); err == nil {
+		t.Error("expected an error for a url not matching the content")
+	}
+}
+
+func TestE2EMetadataHash(t *testing.T) {
+	if solcJS_0_8_29 == "" {
+		t.Skip("embedded soljson is not available")
+	}
+
+	c, err := NewCompiler("testdata/contracts", NewCompilerConfig("cancun", false, 0), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	compiled, err := c.Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the bytecode references the metadata, which lists the sources
+	contract, _ := compiled.Contract("dummy_token.sol:Token")
+	_, deployedBytecode, _ := contractBytecodes(contract)
+	if err := compiled.VerifyMetadataHash("dummy_token.sol:Token", deployedBytecode); err != nil {
+		t.Error(err)
+	}
+	if err := compiled.VerifyMetadataHash("dummy_ERC20.sol:ERC20", deployedBytecode); err == nil {
+		t.Error("expected an error for the metadata of another contract")
+	}
+
+	metadata, err := ParseContractMetadata([]byte(contract["metadata"].(string)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	contents, _ := sourceContents(c.Sources)
+	if err := metadata.VerifySourceURLs(contents); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,171 @@
+package gosolc
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+)
+
+// ErrSizeLimitExceeded is returned by Compile when a contract exceeds the configured size limits.
+var ErrSizeLimitExceeded = errors.New("contract size limit exceeded")
+
+// SizeLimits are the code size limits of a chain and the margin below them that produces warnings.
+type SizeLimits struct {
+	Runtime  int `json:"runtime"`  // Maximum runtime code size in bytes (EIP-170), 0 for no limit
+	Initcode int `json:"initcode"` // Maximum initcode size in bytes (EIP-3860), 0 for no limit
+	Margin   int `json:"margin"`   // Sizes within Margin bytes of a limit are reported as warnings
+}
+
+// Ethereum mainnet limits: 24KiB of runtime code (EIP-170) and twice as much initcode (EIP-3860).
+const (
+	EIP170RuntimeSizeLimit   = 24576
+	EIP3860InitcodeSizeLimit = 49152
+)
+
+var (
+	sizeProfilesMu sync.RWMutex
+	sizeProfiles   = map[string]SizeLimits{
+		"ethereum": {Runtime: EIP170RuntimeSizeLimit, Initcode: EIP3860InitcodeSizeLimit},
+		"optimism": {Runtime: EIP170RuntimeSizeLimit, Initcode: EIP3860InitcodeSizeLimit},
+		"base":     {Runtime: EIP170RuntimeSizeLimit, Initcode: EIP3860InitcodeSizeLimit},
+		"arbitrum": {Runtime: EIP170RuntimeSizeLimit, Initcode: EIP3860InitcodeSizeLimit},
+	}
+)
+
+// RegisterSizeProfile registers the size limits of a chain, e.g. an L2 or an Arbitrum Orbit chain with raised
+// limits. Registering an already known chain replaces it.
+func RegisterSizeProfile(chain string, limits SizeLimits) {
+	sizeProfilesMu.Lock()
+	defer sizeProfilesMu.Unlock()
+	sizeProfiles[chain] = limits
+}
+
+// SizeProfile returns a copy of the registered size limits of a chain, to set as CompilerConfig.SizeLimits.
+func SizeProfile(chain string) (*SizeLimits, error) {
+	sizeProfilesMu.RLock()
+	defer sizeProfilesMu.RUnlock()
+
+	limits, ok := sizeProfiles[chain]
+	if !ok {
+		chains := make([]string, 0, len(sizeProfiles))
+		for name := range sizeProfiles {
+			chains = append(chains, name)
+		}
+		sort.Strings(chains)
+		return nil, fmt.Errorf("unknown size profile %s (registered: %s)", chain, strings.Join(chains, ", "))
+	}
+	return &limits, nil
+}
+
+// SizeStatus is the result of checking a code size against its limit.
+type SizeStatus string
+
+const (
+	SizeOK       SizeStatus = "ok"
+	SizeWarning  SizeStatus = "warning"  // Within the margin of the limit
+	SizeExceeded SizeStatus = "exceeded" // Over the limit, the deployment fails
+)
+
+// ContractSize is the code size of a contract checked against size limits.
+type ContractSize struct {
+	Contract       string     // Fully qualified name of the contract
+	Runtime        int        // Size of the runtime code in bytes
+	Initcode       int        // Size of the creation code in bytes, without constructor arguments
+	RuntimeStatus  SizeStatus // Runtime size against SizeLimits.Runtime
+	InitcodeStatus SizeStatus // Initcode size against SizeLimits.Initcode
+	RuntimeMargin  int        // Bytes left below the runtime limit, negative if exceeded (0 without limit)
+	InitcodeMargin int        // Bytes left below the initcode limit, negative if exceeded (0 without limit)
+}
+
+// Status returns the worst status of the runtime and initcode sizes.
+func (s *ContractSize) Status() SizeStatus {
+	switch {
+	case s.RuntimeStatus == SizeExceeded || s.InitcodeStatus == SizeExceeded:
+		return SizeExceeded
+	case s.RuntimeStatus == SizeWarning || s.InitcodeStatus == SizeWarning:
+		return SizeWarning
+	}
+	return SizeOK
+}
+
+// String describes the size in the "contract: runtime N bytes (status), initcode N bytes (status)" format.
+func (s *ContractSize) String() string {
+	return fmt.Sprintf("%s: runtime %d bytes (%s), initcode %d bytes (%s)", s.Contract, s.Runtime, s.RuntimeStatus, s.Initcode, s.InitcodeStatus)
+}
+
+// ContractSizes returns the runtime and initcode sizes of the deployable contracts of the output (interfaces and
+// abstract contracts have no code), sorted by name and checked against limits; nil limits use the Ethereum ones.
+// Library placeholders count for the 20 bytes of the address replacing them.
+func (contracts CompilerOutput) ContractSizes(limits *SizeLimits) ([]*ContractSize, error) {
+	if limits == nil {
+		limits, _ = SizeProfile("ethereum")
+	}
+
+	var sizes []*ContractSize
+	for _, fqName := range contracts.FullyQualifiedNames() {
+		contract, _ := contracts.Contract(fqName)
+		bytecode, deployedBytecode, err := contractBytecodes(contract)
+		if err != nil {
+			return nil, fmt.Errorf("contract %s: %v", fqName, err)
+		}
+		if bytecode == "" {
+			continue
+		}
+
+		size := &ContractSize{
+			Contract: fqName,
+			Runtime:  len(strings.TrimPrefix(deployedBytecode, "0x")) / 2,
+			Initcode: len(strings.TrimPrefix(bytecode, "0x")) / 2,
+		}
+		size.RuntimeStatus, size.RuntimeMargin = checkSize(size.Runtime, limits.Runtime, limits.Margin)
+		size.InitcodeStatus, size.InitcodeMargin = checkSize(size.Initcode, limits.Initcode, limits.Margin)
+		sizes = append(sizes, size)
+	}
+	return sizes, nil
+}
+
+// checkSize returns the status of a size and the bytes left below its limit.
+func checkSize(size, limit, margin int) (SizeStatus, int) {
+	switch {
+	case limit <= 0:
+		return SizeOK, 0
+	case size > limit:
+		return SizeExceeded, limit - size
+	case size > limit-margin:
+		return SizeWarning, limit - size
+	}
+	return SizeOK, limit - size
+}
+
+// checkSizeLimits returns an error wrapping ErrSizeLimitExceeded listing the contracts over the limits.
+func (contracts CompilerOutput) checkSizeLimits(limits *SizeLimits) error {
+	sizes, err := contracts.ContractSizes(limits)
+	if err != nil {
+		return err
+	}
+	var exceeded []string
+	for _, size := range sizes {
+		if size.Status() == SizeExceeded {
+			exceeded = append(exceeded, size.String())
+		}
+	}
+	if len(exceeded) > 0 {
+		return fmt.Errorf("%w:\n%s", ErrSizeLimitExceeded, strings.Join(exceeded, "\n"))
+	}
+	return nil
+}
+
+// SizeTable formats contract sizes as a table with the bytes left below the limits.
+func SizeTable(sizes []*ContractSize) string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "Contract\tRuntime\tMargin\tInitcode\tMargin\tStatus\t")
+	for _, size := range sizes {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%s\t\n", size.Contract, size.Runtime, size.RuntimeMargin, size.Initcode, size.InitcodeMargin, size.Status())
+	}
+	w.Flush()
+	return b.String()
+}
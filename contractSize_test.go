@@ -0,0 +1,61 @@
+package gosolc
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestContractSizes(t *testing.T) {
+	output := loadTestOutput(t, "testdata_output.json")
+
+	sizes, err := output.ContractSizes(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sizes) != 2 || sizes[0].Contract != "dummy_ERC20.sol:ERC20" {
+		t.Fatalf("unexpected sizes %v", sizes)
+	}
+	_, deployedBytecode, _ := contractBytecodes(output["dummy_ERC20.sol"].(map[string]interface{})["ERC20"].(map[string]interface{}))
+	erc20 := sizes[0]
+	if erc20.Runtime != len(deployedBytecode)/2 || erc20.RuntimeMargin != EIP170RuntimeSizeLimit-erc20.Runtime || erc20.Status() != SizeOK {
+		t.Errorf("unexpected ERC20 size %+v", erc20)
+	}
+
+	// a limit between the runtime and initcode sizes, and a margin covering the runtime size
+	limits := &SizeLimits{Runtime: erc20.Runtime + 10, Initcode: erc20.Initcode - 1, Margin: 20}
+	sizes, _ = output.ContractSizes(limits)
+	if sizes[0].RuntimeStatus != SizeWarning || sizes[0].InitcodeStatus != SizeExceeded || sizes[0].InitcodeMargin != -1 || sizes[0].Status() != SizeExceeded {
+		t.Errorf("unexpected ERC20 size %+v", sizes[0])
+	}
+	if err := output.checkSizeLimits(limits); !errors.Is(err, ErrSizeLimitExceeded) || !strings.Contains(err.Error(), "dummy_ERC20.sol:ERC20") {
+		t.Errorf("expected ErrSizeLimitExceeded, got %v", err)
+	}
+
+	table := SizeTable(sizes)
+	if lines := strings.Split(strings.TrimSpace(table), "\n"); len(lines) != 3 || !strings.HasSuffix(lines[1], "exceeded") {
+		t.Errorf("unexpected table:\n%s", table)
+	}
+}
+
+func TestSizeProfile(t *testing.T) {
+	limits, err := SizeProfile("ethereum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if limits.Runtime != 24576 || limits.Initcode != 49152 {
+		t.Errorf("unexpected ethereum limits %+v", limits)
+	}
+	limits.Runtime = 0 // profiles are copied
+
+	RegisterSizeProfile("orbit", SizeLimits{Runtime: 96 * 1024, Initcode: 192 * 1024, Margin: 1024})
+	if limits, err := SizeProfile("orbit"); err != nil || limits.Runtime != 98304 {
+		t.Errorf("unexpected orbit limits %+v (%v)", limits, err)
+	}
+	if limits, _ := SizeProfile("ethereum"); limits.Runtime != 24576 {
+		t.Errorf("registered profile was modified")
+	}
+	if _, err := SizeProfile("unknown"); err == nil {
+		t.Error("expected an error for an unknown profile")
+	}
+}
@@ -0,0 +1,66 @@
+package gosolc
+
+import (
+	"encoding/hex"
+	"fmt"
+)
+
+// DeploymentCode returns the init code deploying a contract: its creation bytecode followed by the
+// ABI-encoded constructor arguments, ready to be used as the data of a deployment transaction.
+// The arguments are validated against the constructor ABI (see the ABI codec for accepted Go types).
+// fqName is the fully qualified name of the contract (e.g. "dummy_ERC20.sol:ERC20"), or its unique bare name.
+func (contracts CompilerOutput) DeploymentCode(fqName string, args ...interface{}) ([]byte, error) {
+	return contracts.DeploymentCodeWithLibraries(fqName, nil, args...)
+}
+
+// DeploymentCodeWithLibraries works like DeploymentCode for contracts using external libraries.
+// libraries maps fully qualified library names (e.g. "libs/Math.sol:Math") to their deployed addresses.
+func (contracts CompilerOutput) DeploymentCodeWithLibraries(fqName string, libraries map[string]string, args ...interface{}) ([]byte, error) {
+	abi, err := contracts.ContractABI(fqName)
+	if err != nil {
+		return nil, err
+	}
+
+	references, _, err := contracts.GetLinkReferences(fqName)
+	if err != nil {
+		return nil, err
+	}
+
+	contract, err := contracts.Contract(fqName)
+	if err != nil {
+		return nil, err
+	}
+	bytecode, _, err := contractBytecodes(contract)
+	if err != nil {
+		return nil, fmt.Errorf("%v for contract %s", err, fqName)
+	}
+	if bytecode == "" {
+		return nil, fmt.Errorf("contract %s has no bytecode (abstract contract or interface)", fqName)
+	}
+
+	linked, err := LinkBytecode(bytecode, references, libraries)
+	if err != nil {
+		return nil, fmt.Errorf("failed to link bytecode of %s: %w", fqName, err)
+	}
+
+	code, err := hex.DecodeString(linked)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bytecode for contract %s: %v", fqName, err)
+	}
+
+	encodedArgs, err := abi.EncodeConstructor(args...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid constructor arguments for %s: %w", fqName, err)
+	}
+
+	return append(code, encodedArgs...), nil
+}
+
+// DeploymentCodeHex works like DeploymentCode and returns the init code as a 0x prefixed hex string.
+func (contracts CompilerOutput) DeploymentCodeHex(fqName string, args ...interface{}) (string, error) {
+	code, err := contracts.DeploymentCode(fqName, args...)
+	if err != nil {
+		return "", err
+	}
+	return "0x" + hex.EncodeToString(code), nil
+}
@@ -0,0 +1,85 @@
+package gosolc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestDeploymentCode(t *testing.T) {
+	contracts := loadTestOutput(t, "testdata_output.json")
+	contract, _ := contracts.Contract("dummy_ERC20.sol:ERC20")
+	bytecode, _, _ := contractBytecodes(contract)
+	creation, _ := hex.DecodeString(bytecode)
+
+	code, err := contracts.DeploymentCode("dummy_ERC20.sol:ERC20", "MyToken", "MTK")
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := mustDecodeHex(t, `
+		0000000000000000000000000000000000000000000000000000000000000040
+		0000000000000000000000000000000000000000000000000000000000000080
+		0000000000000000000000000000000000000000000000000000000000000007
+		4d79546f6b656e00000000000000000000000000000000000000000000000000
+		0000000000000000000000000000000000000000000000000000000000000003
+		4d544b0000000000000000000000000000000000000000000000000000000000`)
+	if !bytes.Equal(code[:len(creation)], creation) || !bytes.Equal(code[len(creation):], args) {
+		t.Fatalf("unexpected deployment code %x", code[len(creation):])
+	}
+
+	codeHex, err := contracts.DeploymentCodeHex("ERC20", "MyToken", "MTK")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if codeHex != "0x"+hex.EncodeToString(code) {
+		t.Errorf("unexpected deployment code hex %s", codeHex)
+	}
+
+	if _, err := contracts.DeploymentCode("ERC20", "MyToken"); err == nil {
+		t.Error("expected an error for a missing constructor argument")
+	}
+	if _, err := contracts.DeploymentCode("ERC20", "MyToken", 42); err == nil || !strings.Contains(err.Error(), "invalid constructor arguments") {
+		t.Errorf("expected an error for a wrong argument type, got %v", err)
+	}
+
+	// abstract contracts and interfaces have an empty bytecode
+	contracts["dummy_ERC20.sol"].(map[string]interface{})["IERC20"] = map[string]interface{}{
+		"abi": []interface{}{},
+		"evm": map[string]interface{}{
+			"bytecode":         map[string]interface{}{"object": "", "linkReferences": map[string]interface{}{}},
+			"deployedBytecode": map[string]interface{}{"object": ""},
+		},
+	}
+	if _, err := contracts.DeploymentCode("dummy_ERC20.sol:IERC20"); err == nil || !strings.Contains(err.Error(), "has no bytecode") {
+		t.Errorf("expected an error for an abstract contract, got %v", err)
+	}
+}
+
+func TestDeploymentCodeWithLibraries(t *testing.T) {
+	contracts := linkedOutput()
+	contracts["Vault.sol"].(map[string]interface{})["Vault"].(map[string]interface{})["abi"] = []interface{}{}
+	math := "0x" + strings.Repeat("11", 20)
+	strs := "0x" + strings.Repeat("ab", 20)
+
+	code, err := contracts.DeploymentCodeWithLibraries("Vault", map[string]string{
+		"libs/Math.sol:Math":    math,
+		"libs/Math.sol:Strings": strs,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(code) != "73"+strings.Repeat("11", 20)+"73"+strings.Repeat("ab", 20) {
+		t.Fatalf("unexpected linked deployment code %x", code)
+	}
+
+	// unlinked: no library addresses at all
+	if _, err := contracts.DeploymentCode("Vault"); err == nil || !strings.Contains(err.Error(), "libs/Math.sol:Math, libs/Math.sol:Strings") {
+		t.Errorf("expected an unlinked libraries error, got %v", err)
+	}
+	// missing: one of the libraries has no address
+	_, err = contracts.DeploymentCodeWithLibraries("Vault", map[string]string{"libs/Math.sol:Math": math})
+	if err == nil || !strings.Contains(err.Error(), "libs/Math.sol:Strings") || strings.Contains(err.Error(), "libs/Math.sol:Math,") {
+		t.Errorf("expected a missing library error, got %v", err)
+	}
+}
@@ -0,0 +1,266 @@
+package gosolc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Instruction is a disassembled EVM instruction.
+type Instruction struct {
+	PC        int // Offset of the instruction in the code
+	Opcode    byte
+	Name      string // Mnemonic, "UNDEFINED(0x..)" for undefined opcodes
+	Immediate []byte // Immediate of PUSH1 to PUSH32
+	Undefined bool   // The opcode is not defined in any EVM version
+	Truncated bool   // The PUSH immediate runs past the end of the code
+}
+
+// String returns the instruction in the "0x002a PUSH1 0x80" format.
+func (i Instruction) String() string {
+	if len(i.Immediate) > 0 {
+		return fmt.Sprintf("0x%04x %s 0x%s", i.PC, i.Name, hex.EncodeToString(i.Immediate))
+	}
+	return fmt.Sprintf("0x%04x %s", i.PC, i.Name)
+}
+
+// Disassemble decodes EVM code into instructions. Data sections are decoded as instructions too,
+// so undefined opcodes after the last reachable instruction are common; use SplitBytecode to
+// remove the metadata trailer first.
+func Disassemble(code []byte) []Instruction {
+	var instructions []Instruction
+	for pc := 0; pc < len(code); {
+		op := code[pc]
+		instruction := Instruction{PC: pc, Opcode: op, Name: OpcodeName(op)}
+		_, defined := opcodes[op]
+		instruction.Undefined = !defined
+
+		size := pushSize(op)
+		if size > 0 {
+			end := pc + 1 + size
+			if end > len(code) {
+				end = len(code)
+				instruction.Truncated = true
+			}
+			instruction.Immediate = code[pc+1 : end]
+		}
+
+		instructions = append(instructions, instruction)
+		pc += 1 + size
+	}
+	return instructions
+}
+
+// FormatInstructions returns the disassembly listing of instructions, one per line.
+func FormatInstructions(instructions []Instruction) string {
+	var b strings.Builder
+	for _, instruction := range instructions {
+		b.WriteString(instruction.String())
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// OpcodeHistogram counts the instructions by mnemonic.
+func OpcodeHistogram(instructions []Instruction) map[string]int {
+	histogram := map[string]int{}
+	for _, instruction := range instructions {
+		histogram[instruction.Name]++
+	}
+	return histogram
+}
+
+// UnsupportedInstructions returns the instructions whose opcodes are not available in an EVM version,
+// e.g. PUSH0 before shanghai or MCOPY and TSTORE before cancun. Undefined opcodes are not reported.
+func UnsupportedInstructions(instructions []Instruction, evmVersion string) ([]Instruction, error) {
+	var unsupported []Instruction
+	for _, instruction := range instructions {
+		if instruction.Undefined {
+			continue
+		}
+		supported, err := OpcodeSupported(instruction.Opcode, evmVersion)
+		if err != nil {
+			return nil, err
+		}
+		if !supported {
+			unsupported = append(unsupported, instruction)
+		}
+	}
+	return unsupported, nil
+}
+
+// BytecodeSections are the parts of a contract's bytecode.
+type BytecodeSections struct {
+	InitCode        []byte // Constructor code, empty for runtime bytecode
+	RuntimeCode     []byte // Runtime code without the metadata trailer
+	Metadata        []byte // CBOR metadata trailer, including its 2 byte length, empty if not appended
+	ConstructorArgs []byte // Data appended after the runtime code: the ABI-encoded constructor arguments, or sub-assemblies
+}
+
+// SplitBytecode splits creation bytecode into init code, runtime code, metadata trailer and the
+// constructor arguments appended to it. If runtime is set (e.g. the deployed bytecode of the same
+// contract), it is located in the creation bytecode; otherwise the runtime code is assumed to start
+// after the first RETURN INVALID sequence, which ends the init code generated by solc, and to end
+// with the metadata trailer. Without trailer, constructor arguments are part of the runtime code.
+func SplitBytecode(creation []byte, runtime []byte) (*BytecodeSections, error) {
+	start := -1
+	if len(runtime) > 0 {
+		start = bytes.Index(creation, runtime)
+		if start < 0 {
+			return nil, fmt.Errorf("runtime code not found in the creation bytecode")
+		}
+	} else {
+		instructions := Disassemble(creation)
+		for i := 0; i+1 < len(instructions); i++ {
+			if instructions[i].Opcode == 0xf3 && instructions[i+1].Opcode == 0xfe {
+				start = instructions[i+1].PC + 1
+				break
+			}
+		}
+		if start < 0 {
+			return nil, fmt.Errorf("end of the init code not found")
+		}
+		runtime = creation[start:]
+		if end := metadataEnd(runtime); end > 0 {
+			runtime = runtime[:end]
+		}
+	}
+
+	sections := SplitRuntimeBytecode(runtime)
+	sections.InitCode = creation[:start]
+	if end := start + len(runtime); end < len(creation) {
+		sections.ConstructorArgs = creation[end:]
+	}
+	return sections, nil
+}
+
+// solcMetadataKey is the CBOR encoding of the "solc" key, whose version value ends the metadata appended by
+// solc since 0.5.9: a 3 byte string for releases, a text string with the full version for prereleases.
+var solcMetadataKey = []byte{0x64, 's', 'o', 'l', 'c'}
+
+// metadataEnd returns the end of the last metadata trailer in code, or -1.
+func metadataEnd(code []byte) int {
+	for i := bytes.LastIndex(code, solcMetadataKey); i >= 0; i = bytes.LastIndex(code[:i], solcMetadataKey) {
+		value, next, err := decodeCBOR(code, i+len(solcMetadataKey))
+		if err != nil {
+			continue
+		}
+		switch version := value.(type) {
+		case []byte:
+			if len(version) != 3 {
+				continue
+			}
+		case string:
+		default:
+			continue
+		}
+		if end := next + 2; end <= len(code) && metadataStart(code[:end]) >= 0 {
+			return end
+		}
+	}
+	return -1
+}
+
+// SplitRuntimeBytecode splits runtime bytecode into its code and the CBOR metadata trailer appended by solc.
+// The trailer is detected by its big-endian length in the last 2 bytes and must hold a CBOR map.
+func SplitRuntimeBytecode(code []byte) *BytecodeSections {
+	if end := metadataStart(code); end >= 0 {
+		return &BytecodeSections{RuntimeCode: code[:end], Metadata: code[end:]}
+	}
+	return &BytecodeSections{RuntimeCode: code}
+}
+
+// metadataStart returns the offset of the CBOR metadata trailer of code, or -1.
+func metadataStart(code []byte) int {
+	if len(code) < 2 {
+		return -1
+	}
+	length := int(code[len(code)-2])<<8 | int(code[len(code)-1])
+	start := len(code) - 2 - length
+	if length == 0 || start < 0 || code[start]&0xe0 != 0xa0 {
+		return -1
+	}
+	if _, err := decodeCBORMap(code[start : len(code)-2]); err != nil {
+		return -1
+	}
+	return start
+}
+
+// Disassembly is the disassembled bytecode of a contract.
+type Disassembly struct {
+	Creation *BytecodeSections
+	Init     []Instruction // Instructions of the init code
+	Runtime  []Instruction // Instructions of the runtime code, without metadata
+}
+
+// Disassemble disassembles the creation and runtime bytecode of a contract.
+// Unlinked library placeholders are disassembled as zero addresses.
+func (contracts CompilerOutput) Disassemble(fqName string) (*Disassembly, error) {
+	contract, err := contracts.Contract(fqName)
+	if err != nil {
+		return nil, err
+	}
+	bytecode, deployedBytecode, err := contractBytecodes(contract)
+	if err != nil {
+		return nil, err
+	}
+
+	creation, err := decodeBytecode(bytecode)
+	if err != nil {
+		return nil, err
+	}
+	runtime, err := decodeBytecode(deployedBytecode)
+	if err != nil {
+		return nil, err
+	}
+	if len(creation) == 0 {
+		return nil, fmt.Errorf("contract %s has no bytecode (abstract contract or interface)", fqName)
+	}
+
+	// the runtime code is followed by the creation code of the contracts deployed by the constructor, if any
+	sections, err := SplitBytecode(creation, runtime)
+	if err != nil {
+		return nil, fmt.Errorf("contract %s: %v", fqName, err)
+	}
+
+	return &Disassembly{
+		Creation: sections,
+		Init:     Disassemble(sections.InitCode),
+		Runtime:  Disassemble(sections.RuntimeCode),
+	}, nil
+}
+
+// Unsupported returns the instructions of the init and runtime code not available in an EVM version.
+func (d *Disassembly) Unsupported(evmVersion string) ([]Instruction, error) {
+	unsupported, err := UnsupportedInstructions(d.Init, evmVersion)
+	if err != nil {
+		return nil, err
+	}
+	runtime, err := UnsupportedInstructions(d.Runtime, evmVersion)
+	if err != nil {
+		return nil, err
+	}
+	return append(unsupported, runtime...), nil
+}
+
+// Histogram counts the instructions of the init and runtime code by mnemonic.
+func (d *Disassembly) Histogram() map[string]int {
+	return OpcodeHistogram(append(append([]Instruction{}, d.Init...), d.Runtime...))
+}
+
+// SortedHistogram returns the mnemonics of a histogram by decreasing count, then by name.
+func SortedHistogram(histogram map[string]int) []string {
+	names := make([]string, 0, len(histogram))
+	for name := range histogram {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if histogram[names[i]] != histogram[names[j]] {
+			return histogram[names[i]] > histogram[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
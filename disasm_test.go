@@ -0,0 +1,102 @@
+package gosolc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestDisassemble(t *testing.T) {
+	code, _ := hex.DecodeString("60015f0c61ff")
+	instructions := Disassemble(code)
+
+	expected := []string{"0x0000 PUSH1 0x01", "0x0002 PUSH0", "0x0003 UNDEFINED(0x0c)", "0x0004 PUSH2 0xff"}
+	if len(instructions) != len(expected) {
+		t.Fatalf("expected %d instructions, got %v", len(expected), instructions)
+	}
+	for i, instruction := range instructions {
+		if instruction.String() != expected[i] {
+			t.Errorf("instruction %d: expected %q, got %q", i, expected[i], instruction)
+		}
+	}
+	if !instructions[2].Undefined || !instructions[3].Truncated {
+		t.Errorf("undefined or truncated instruction not detected: %+v", instructions)
+	}
+
+	unsupported, err := UnsupportedInstructions(instructions, "paris")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(unsupported) != 1 || unsupported[0].Name != "PUSH0" {
+		t.Errorf("expected PUSH0 to be unsupported by paris, got %v", unsupported)
+	}
+	if _, err := UnsupportedInstructions(instructions, "merge"); err == nil {
+		t.Error("expected an error for an unknown EVM version")
+	}
+}
+
+func TestDisassembleContract(t *testing.T) {
+	contracts := loadTestOutput(t, "testdata_output.json")
+
+	disassembly, err := contracts.Disassemble("dummy_ERC20.sol:ERC20")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sections := disassembly.Creation
+	if len(sections.InitCode) != 1286 || len(sections.RuntimeCode)+len(sections.Metadata) != 686 || len(sections.Metadata) != 53 {
+		t.Errorf("unexpected sections: init %d, runtime %d, metadata %d bytes", len(sections.InitCode), len(sections.RuntimeCode), len(sections.Metadata))
+	}
+	if last := disassembly.Init[len(disassembly.Init)-1]; last.Name != "INVALID" {
+		t.Errorf("expected the init code to end with INVALID, got %s", last)
+	}
+
+	histogram := disassembly.Histogram()
+	if histogram["CODECOPY"] != 2 || histogram["PUSH0"] == 0 {
+		t.Errorf("unexpected histogram %v", histogram)
+	}
+
+	unsupported, err := disassembly.Unsupported("shanghai")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(unsupported) == 0 {
+		t.Fatal("expected MCOPY to be unsupported by shanghai")
+	}
+	for _, instruction := range unsupported {
+		if instruction.Name != "MCOPY" {
+			t.Errorf("unexpected unsupported instruction %s", instruction)
+		}
+	}
+
+	// a constructor deploying another contract (new Token()) has its creation code after the runtime code
+	token, _ := contracts.Contract("dummy_token.sol:Token")
+	tokenBytecode, _, _ := contractBytecodes(token)
+	erc20 := contracts["dummy_ERC20.sol"].(map[string]interface{})["ERC20"].(map[string]interface{})
+	evm := erc20["evm"].(map[string]interface{})
+	original := evm["bytecode"].(map[string]interface{})["object"].(string)
+	evm["bytecode"].(map[string]interface{})["object"] = original + tokenBytecode
+	withSubAssembly, err := contracts.Disassemble("dummy_ERC20.sol:ERC20")
+	if err != nil {
+		t.Fatal(err)
+	}
+	subAssembly, _ := decodeBytecode(tokenBytecode)
+	if sub := withSubAssembly.Creation; !bytes.Equal(sub.InitCode, sections.InitCode) || !bytes.Equal(sub.RuntimeCode, sections.RuntimeCode) ||
+		!bytes.Equal(sub.ConstructorArgs, subAssembly) {
+		t.Errorf("unexpected sections with a sub-assembly: init %d, runtime %d, trailing %d bytes", len(sub.InitCode), len(sub.RuntimeCode), len(sub.ConstructorArgs))
+	}
+	evm["bytecode"].(map[string]interface{})["object"] = original
+
+	// creation bytecode of a deployment, with constructor arguments
+	contract, _ := contracts.Contract("dummy_ERC20.sol:ERC20")
+	bytecode, _, _ := contractBytecodes(contract)
+	creation, _ := decodeBytecode(bytecode)
+	args := bytes.Repeat([]byte{0x11}, 64)
+	split, err := SplitBytecode(append(creation, args...), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(split.ConstructorArgs, args) || !bytes.Equal(split.Metadata, sections.Metadata) {
+		t.Errorf("unexpected split: args %x, metadata %x", split.ConstructorArgs, split.Metadata)
+	}
+}
@@ -0,0 +1,275 @@
+package gosolc
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// GasCost is a gas estimate of solc. Infinite estimates are those solc can't bound, e.g. functions with loops
+// or writing dynamic data.
+type GasCost struct {
+	Gas      uint64
+	Infinite bool
+}
+
+// UnmarshalJSON decodes the "infinite" or decimal string estimates of the compiler output.
+func (g *GasCost) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		s = string(data)
+	}
+	if s == "infinite" {
+		*g = GasCost{Infinite: true}
+		return nil
+	}
+	gas, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid gas estimate %q", s)
+	}
+	*g = GasCost{Gas: gas}
+	return nil
+}
+
+// MarshalJSON encodes the estimate in the format of the compiler output.
+func (g GasCost) MarshalJSON() ([]byte, error) {
+	return json.Marshal(g.String())
+}
+
+// String returns the estimate in decimal, or "infinite".
+func (g GasCost) String() string {
+	if g.Infinite {
+		return "infinite"
+	}
+	return strconv.FormatUint(g.Gas, 10)
+}
+
+// less orders estimates, infinite estimates last.
+func (g GasCost) less(other GasCost) bool {
+	if g.Infinite || other.Infinite {
+		return !g.Infinite && other.Infinite
+	}
+	return g.Gas < other.Gas
+}
+
+// GasEstimates is the evm.gasEstimates output of a contract.
+type GasEstimates struct {
+	Creation CreationGasEstimates `json:"creation"`
+	External map[string]GasCost   `json:"external"` // Keyed by function signature, "" for the fallback or receive function
+	Internal map[string]GasCost   `json:"internal"` // Keyed by internal function name and parameter types
+}
+
+// CreationGasEstimates are the gas estimates of the deployment of a contract.
+type CreationGasEstimates struct {
+	CodeDepositCost GasCost `json:"codeDepositCost"` // 200 gas per byte of runtime code
+	ExecutionCost   GasCost `json:"executionCost"`   // Constructor execution
+	TotalCost       GasCost `json:"totalCost"`
+}
+
+// GasEstimates returns the typed evm.gasEstimates output of a contract.
+func (contracts CompilerOutput) GasEstimates(fqName string) (*GasEstimates, error) {
+	contract, err := contracts.Contract(fqName)
+	if err != nil {
+		return nil, err
+	}
+	evm, _ := contract["evm"].(map[string]interface{})
+	if evm["gasEstimates"] == nil {
+		return nil, fmt.Errorf("gasEstimates of contract %s not found in compiler output", fqName)
+	}
+	var estimates GasEstimates
+	if err := decodeOutput(evm["gasEstimates"], &estimates); err != nil {
+		return nil, fmt.Errorf("invalid gasEstimates output for contract %s: %v", fqName, err)
+	}
+	return &estimates, nil
+}
+
+// GasReportEntry is the estimated cost of deploying a contract or calling one of its external functions.
+type GasReportEntry struct {
+	Contract   string  // Fully qualified name of the contract
+	Function   string  // Function signature, "constructor" for the deployment or "fallback" / "receive"
+	Selector   string  // 0x prefixed selector of functions
+	Mutability string  // State mutability from the ABI
+	Cost       GasCost // Total cost of the deployment, execution cost of functions
+}
+
+// GasReport lists the gas estimates of contracts, most expensive first.
+type GasReport struct {
+	Entries []*GasReportEntry
+}
+
+// GasReport joins the gas estimates of contracts with their ABIs, defaulting to all deployable contracts of the
+// output. Entries are sorted by decreasing cost, infinite estimates first, then by contract and function.
+func (contracts CompilerOutput) GasReport(fqNames ...string) (*GasReport, error) {
+	if len(fqNames) == 0 {
+		for _, fqName := range contracts.FullyQualifiedNames() {
+			contract, _ := contracts.Contract(fqName)
+			if bytecode, _, err := contractBytecodes(contract); err == nil && bytecode != "" {
+				fqNames = append(fqNames, fqName)
+			}
+		}
+	}
+
+	report := &GasReport{}
+	for _, name := range fqNames {
+		fqName, _, err := contracts.resolveContract(name)
+		if err != nil {
+			return nil, err
+		}
+		estimates, err := contracts.GasEstimates(fqName)
+		if err != nil {
+			return nil, err
+		}
+		abi, err := contracts.ContractABI(fqName)
+		if err != nil {
+			return nil, err
+		}
+
+		report.Entries = append(report.Entries, &GasReportEntry{Contract: fqName, Function: "constructor", Cost: estimates.Creation.TotalCost})
+		functions := map[string]*ABIEntry{}
+		for _, function := range abi.Functions {
+			functions[function.Signature()] = function
+		}
+		for signature, cost := range estimates.External {
+			entry := &GasReportEntry{Contract: fqName, Function: signature, Cost: cost}
+			switch function := functions[signature]; {
+			case function != nil:
+				entry.Selector = selectorHex(function)
+				entry.Mutability = function.StateMutability
+			case signature == "" && abi.Fallback != nil:
+				entry.Function, entry.Mutability = "fallback", abi.Fallback.StateMutability
+			case signature == "" && abi.Receive != nil:
+				entry.Function, entry.Mutability = "receive", abi.Receive.StateMutability
+			}
+			report.Entries = append(report.Entries, entry)
+		}
+	}
+
+	sort.Slice(report.Entries, func(i, j int) bool {
+		a, b := report.Entries[i], report.Entries[j]
+		if a.Cost != b.Cost {
+			return b.Cost.less(a.Cost)
+		}
+		if a.Contract != b.Contract {
+			return a.Contract < b.Contract
+		}
+		return a.Function < b.Function
+	})
+	return report, nil
+}
+
+// Table formats the report as a table.
+func (r *GasReport) Table() string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Gas\tContract\tFunction\tSelector\tMutability")
+	for _, entry := range r.Entries {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", entry.Cost, entry.Contract, entry.Function, entry.Selector, entry.Mutability)
+	}
+	w.Flush()
+	return b.String()
+}
+
+// GasSnapshot is a stored gas report: estimates keyed by contract and function, as written to snapshot files.
+type GasSnapshot map[string]map[string]GasCost
+
+// Snapshot returns the estimates of the report, to store and compare later reports with.
+func (r *GasReport) Snapshot() GasSnapshot {
+	snapshot := GasSnapshot{}
+	for _, entry := range r.Entries {
+		if snapshot[entry.Contract] == nil {
+			snapshot[entry.Contract] = map[string]GasCost{}
+		}
+		snapshot[entry.Contract][entry.Function] = entry.Cost
+	}
+	return snapshot
+}
+
+// ParseGasSnapshot parses a snapshot file written from the JSON encoding of a GasSnapshot.
+func ParseGasSnapshot(data []byte) (GasSnapshot, error) {
+	var snapshot GasSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("invalid gas snapshot: %v", err)
+	}
+	return snapshot, nil
+}
+
+// GasChange is a difference between a gas report and a snapshot.
+type GasChange struct {
+	Contract   string
+	Function   string
+	Old        *GasCost // Estimate of the snapshot, nil for new functions
+	New        *GasCost // Estimate of the report, nil for removed functions
+	Percent    float64  // Relative change of finite estimates, +Inf when an estimate became infinite
+	Regression bool     // The estimate increased by more than the threshold, or became infinite
+}
+
+// String describes the change, e.g. "Token.sol:Token transfer(address,uint256): 51234 -> 53012 (+3.47%)".
+func (c *GasChange) String() string {
+	switch {
+	case c.Old == nil:
+		return fmt.Sprintf("%s %s: added (%v)", c.Contract, c.Function, *c.New)
+	case c.New == nil:
+		return fmt.Sprintf("%s %s: removed (%v)", c.Contract, c.Function, *c.Old)
+	}
+	s := fmt.Sprintf("%s %s: %v -> %v", c.Contract, c.Function, *c.Old, *c.New)
+	if !c.Old.Infinite && !c.New.Infinite {
+		s += fmt.Sprintf(" (%+.2f%%)", c.Percent)
+	}
+	if c.Regression {
+		s += " regression"
+	}
+	return s
+}
+
+// Compare returns the estimates of the report that differ from a snapshot, sorted by contract and function. A
+// change is a regression if the estimate grew by more than thresholdPercent (e.g. 5 for 5%) or became infinite.
+func (r *GasReport) Compare(snapshot GasSnapshot, thresholdPercent float64) []*GasChange {
+	var changes []*GasChange
+	current := r.Snapshot()
+	for contract, functions := range current {
+		for function, cost := range functions {
+			cost := cost
+			old, ok := snapshot[contract][function]
+			if !ok {
+				changes = append(changes, &GasChange{Contract: contract, Function: function, New: &cost})
+				continue
+			}
+			if old == cost {
+				continue
+			}
+			change := &GasChange{Contract: contract, Function: function, Old: &old, New: &cost}
+			switch {
+			case cost.Infinite:
+				change.Percent, change.Regression = math.Inf(1), true
+			case old.Infinite:
+				change.Percent = math.Inf(-1)
+			case old.Gas > 0:
+				change.Percent = (float64(cost.Gas) - float64(old.Gas)) / float64(old.Gas) * 100
+				change.Regression = change.Percent > thresholdPercent
+			default:
+				change.Percent, change.Regression = math.Inf(1), true
+			}
+			changes = append(changes, change)
+		}
+	}
+	for contract, functions := range snapshot {
+		for function, cost := range functions {
+			cost := cost
+			if _, ok := current[contract][function]; !ok {
+				changes = append(changes, &GasChange{Contract: contract, Function: function, Old: &cost})
+			}
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Contract != changes[j].Contract {
+			return changes[i].Contract < changes[j].Contract
+		}
+		return changes[i].Function < changes[j].Function
+	})
+	return changes
+}
@@ -0,0 +1,68 @@
+package gosolc
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestGasReport(t *testing.T) {
+	output := loadTestOutput(t, "testdata_output.json")
+	if _, err := output.GasEstimates("dummy_token.sol:Token"); err == nil {
+		t.Error("expected an error for an output without gasEstimates")
+	}
+
+	var estimates map[string]interface{}
+	json.Unmarshal([]byte(`{
+		"creation": {"codeDepositCost": "137200", "executionCost": "infinite", "totalCost": "infinite"},
+		"external": {"name()": "infinite", "symbol()": "2450"},
+		"internal": {"_mint(address,uint256)": "infinite"}
+	}`), &estimates)
+	evm := output["dummy_token.sol"].(map[string]interface{})["Token"].(map[string]interface{})["evm"].(map[string]interface{})
+	evm["gasEstimates"] = estimates
+
+	typed, err := output.GasEstimates("Token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if typed.Creation.CodeDepositCost != (GasCost{Gas: 137200}) || !typed.Creation.TotalCost.Infinite || !typed.Internal["_mint(address,uint256)"].Infinite {
+		t.Errorf("unexpected estimates %+v", typed)
+	}
+
+	report, err := output.GasReport("Token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Entries) != 3 || report.Entries[0].Function != "constructor" || report.Entries[1].Function != "name()" || report.Entries[2].Selector != "0x95d89b41" {
+		t.Fatalf("unexpected report:\n%s", report.Table())
+	}
+
+	data, _ := json.Marshal(report.Snapshot())
+	snapshot, err := ParseGasSnapshot(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changes := report.Compare(snapshot, 5); len(changes) != 0 {
+		t.Errorf("unexpected changes %v", changes)
+	}
+
+	snapshot["dummy_token.sol:Token"]["symbol()"] = GasCost{Gas: 2300}
+	snapshot["dummy_token.sol:Token"]["name()"] = GasCost{Gas: 3000}
+	snapshot["dummy_token.sol:Token"]["decimals()"] = GasCost{Gas: 250}
+	changes := report.Compare(snapshot, 5)
+	if len(changes) != 3 {
+		t.Fatalf("unexpected changes %v", changes)
+	}
+	if changes[0].Function != "decimals()" || changes[0].New != nil || changes[0].Regression {
+		t.Errorf("unexpected change %v", changes[0])
+	}
+	if !changes[1].Regression || !strings.HasSuffix(changes[1].String(), "3000 -> infinite regression") {
+		t.Errorf("unexpected change %v", changes[1])
+	}
+	if !changes[2].Regression || changes[2].String() != "dummy_token.sol:Token symbol(): 2300 -> 2450 (+6.52%) regression" {
+		t.Errorf("unexpected change %v", changes[2])
+	}
+	if changes := report.Compare(snapshot, 10); changes[2].Regression {
+		t.Errorf("unexpected regression below the threshold %v", changes[2])
+	}
+}
@@ -3,3 +3,8 @@ module github.com/0xsharma/gosolc
 go 1.22.6
 
 require rogchap.com/v8go v0.8.0
+
+require (
+	golang.org/x/crypto v0.31.0
+	golang.org/x/sys v0.28.0 // indirect
+)
@@ -7,6 +7,8 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"golang.org/x/crypto/sha3"
 )
 
 // solcJSFromPath reads the solc-js file from the specified path and returns its content as a byte slice.
@@ -18,62 +20,59 @@ func solcJsFromPath(path string) ([]byte, error) {
 	return solcJS, nil
 }
 
-// contractsDirToSourcesMap reads Solidity files from the specified directory and returns a map of file names to their content.
-func contractsDirToSourcesMap(contractsDir string) (map[string]map[string]string, error) {
+// readContractsDir reads Solidity files from the specified directory and returns a map of file names to their raw content.
+func readContractsDir(contractsDir string) (map[string]string, error) {
 	files, err := filepath.Glob(fmt.Sprintf("%s/*.sol", contractsDir))
 	if err != nil {
 		return nil, fmt.Errorf("failed to read contracts directory: %v", err)
 	}
 
-	sources := make(map[string]map[string]string)
+	contents := make(map[string]string)
 	for _, file := range files {
 		content, err := os.ReadFile(file)
 		if err != nil {
 			return nil, fmt.Errorf("failed to read file %s: %v", file, err)
 		}
+		contents[filepath.Base(file)] = string(content)
+	}
+
+	return contents, nil
+}
+
+// sourcesMap converts raw source contents into the escaped sources map used in the compiler input.
+func sourcesMap(contents map[string]string) (map[string]map[string]string, error) {
+	sources := make(map[string]map[string]string)
+	for name, content := range contents {
 		// Escape the content by marshaling it to JSON and removing quotes
-		escapedContent, err := json.Marshal(string(content))
+		escapedContent, err := json.Marshal(content)
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal file content: %v", err)
 		}
 		// Remove surrounding quotes from marshaled string
 		escapedContentStr := string(escapedContent)[1 : len(escapedContent)-1]
-		sources[filepath.Base(file)] = map[string]string{"content": escapedContentStr}
+		sources[name] = map[string]string{"content": escapedContentStr}
 	}
 
 	return sources, nil
 }
 
-// getInputJSON generates the input JSON for the Solidity compiler based on the provided sources and configuration.
-func (c Compiler) getInputJSON() (string, error) {
-	compilerInput := map[string]interface{}{
-		"language": "Solidity",
-		"sources":  c.Sources,
-		"settings": map[string]interface{}{
-			"optimizer": map[string]any{
-				"enabled": c.CompilerConfig.SolcOptimizer.Enabled,
-				"runs":    c.CompilerConfig.SolcOptimizer.Runs,
-			},
-			"evmVersion": c.CompilerConfig.EVMVersion,
-			"outputSelection": map[string]map[string][]string{
-				"*": {
-					"*": []string{
-						"abi",
-						"evm.bytecode.object",
-						"evm.bytecode.sourceMap",
-						"evm.deployedBytecode.object",
-						"evm.deployedBytecode.sourceMap",
-						"evm.methodIdentifiers",
-					},
-					"": []string{
-						"ast",
-					},
-				},
-			},
-		},
+// sourceContents reverses sourcesMap, returning the raw content of every source.
+func sourceContents(sources map[string]map[string]string) (map[string]string, error) {
+	contents := make(map[string]string)
+	for name, source := range sources {
+		var content string
+		if err := json.Unmarshal([]byte(`"`+source["content"]+`"`), &content); err != nil {
+			return nil, fmt.Errorf("failed to unescape content of %s: %v", name, err)
+		}
+		contents[name] = content
 	}
 
-	inputJSON, err := json.Marshal(compilerInput)
+	return contents, nil
+}
+
+// getInputJSON generates the input JSON for the Solidity compiler based on the provided sources and configuration.
+func (c Compiler) getInputJSON() (string, error) {
+	inputJSON, err := json.Marshal(c.compilerInput(c.Sources))
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal compiler input JSON: %v", err)
 	}
@@ -83,7 +82,60 @@ func (c Compiler) getInputJSON() (string, error) {
 	return inputJSONStr, err
 }
 
-// writeOutput writes the compiler output to files in the specified directory (./solc-go-build).
+// compilerInput returns the standard JSON input for the given sources map and the compiler configuration.
+func (c Compiler) compilerInput(sources map[string]map[string]string) map[string]interface{} {
+	optimizer := c.CompilerConfig.SolcOptimizer
+	if optimizer == nil {
+		optimizer = &SolcOptimizerConfig{}
+	}
+
+	settings := map[string]interface{}{
+		"optimizer": map[string]any{
+			"enabled": optimizer.Enabled,
+			"runs":    optimizer.Runs,
+		},
+		"evmVersion": c.CompilerConfig.EVMVersion,
+		"outputSelection": map[string]map[string][]string{
+			"*": {
+				"*": []string{
+					"abi",
+					"evm.bytecode.object",
+					"evm.bytecode.sourceMap",
+					"evm.bytecode.linkReferences",
+					"evm.deployedBytecode.object",
+					"evm.deployedBytecode.sourceMap",
+					"evm.deployedBytecode.linkReferences",
+					"evm.deployedBytecode.immutableReferences",
+					"evm.methodIdentifiers",
+					"evm.gasEstimates",
+					"metadata",
+					"storageLayout",
+				},
+				"": []string{
+					"ast",
+				},
+			},
+		},
+	}
+	if c.CompilerConfig.ViaIR != nil && *c.CompilerConfig.ViaIR {
+		settings["viaIR"] = true
+	}
+	if c.CompilerConfig.Metadata != nil {
+		settings["metadata"] = c.CompilerConfig.Metadata
+	}
+	if len(c.CompilerConfig.Libraries) > 0 {
+		settings["libraries"] = c.CompilerConfig.Libraries
+	}
+
+	return map[string]interface{}{
+		"language": "Solidity",
+		"sources":  sources,
+		"settings": settings,
+	}
+}
+
+// writeOutput writes the compiler output to files in the specified directory (./solc-go-build):
+// a JSON artifact and a TypeScript ABI module (see GenerateTypeScript) per contract.
 func (c Compiler) writeOutput(contracts CompilerOutput) error {
 	outputDir := "solc-go-build"
 	err := os.MkdirAll(outputDir, 0755)
@@ -104,8 +156,36 @@ func (c Compiler) writeOutput(contracts CompilerOutput) error {
 			if err != nil {
 				return fmt.Errorf("failed to write contract data to file: %w", err)
 			}
+
+			// Write the TypeScript ABI module next to the JSON artifact
+			typeScript, err := contractTypeScript(name, contract)
+			if err != nil {
+				return fmt.Errorf("failed to generate TypeScript for %s: %w", name, err)
+			}
+			err = os.WriteFile(filepath.Join(outputDir, name+".ts"), typeScript, 0644)
+			if err != nil {
+				return fmt.Errorf("failed to write TypeScript module to file: %w", err)
+			}
 		}
 	}
 
 	return nil
 }
+
+// decodeOutput converts a value of the untyped compiler output into a typed value by round-tripping it through JSON.
+func decodeOutput(value interface{}, target interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, target)
+}
+
+// keccak256 returns the Keccak-256 hash of the concatenated data, as used by the EVM.
+func keccak256(data ...[]byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	for _, d := range data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
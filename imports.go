@@ -0,0 +1,132 @@
+package gosolc
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	// importRegexp matches the path of every import form:
+	// import "a.sol"; import "a.sol" as A; import * as A from "a.sol"; import {A, B as C} from "a.sol";
+	importRegexp = regexp.MustCompile(`\bimport\s+(?:[^;'"]*?\bfrom\s*)?["']([^"']+)["']`)
+
+	// pragmaRegexp matches the version expression of a `pragma solidity` directive.
+	pragmaRegexp = regexp.MustCompile(`\bpragma\s+solidity\s+([^;]+);`)
+)
+
+// sourceInfo holds the parts of a Solidity source needed to plan compilation units.
+type sourceInfo struct {
+	pragma  versionRange // intersection of all `pragma solidity` directives in the file
+	imports []string     // resolved source unit names of the imported files
+}
+
+// analyzeSource extracts the version pragma and resolved imports of the source unit name.
+func analyzeSource(name string, content string) (*sourceInfo, error) {
+	code := stripComments(content)
+	info := &sourceInfo{}
+
+	for _, m := range pragmaRegexp.FindAllStringSubmatch(code, -1) {
+		r, err := parseVersionRange(m[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid pragma in %s: %v", name, err)
+		}
+		info.pragma = info.pragma.intersect(r)
+	}
+
+	for _, m := range importRegexp.FindAllStringSubmatch(code, -1) {
+		info.imports = append(info.imports, resolveImportPath(name, m[1]))
+	}
+
+	return info, nil
+}
+
+// analyzeSources analyzes every source of a raw contents map, keyed by source unit name.
+func analyzeSources(contents map[string]string) (map[string]*sourceInfo, error) {
+	infos := make(map[string]*sourceInfo, len(contents))
+	for name, content := range contents {
+		info, err := analyzeSource(name, content)
+		if err != nil {
+			return nil, err
+		}
+		infos[name] = info
+	}
+	return infos, nil
+}
+
+// resolveImportPath resolves an import path relative to the importing source unit name.
+// Paths that don't start with "./" or "../" are already source unit names.
+func resolveImportPath(importer string, importPath string) string {
+	if strings.HasPrefix(importPath, "./") || strings.HasPrefix(importPath, "../") {
+		return path.Clean(path.Join(path.Dir(importer), importPath))
+	}
+	return importPath
+}
+
+// stripComments removes // and /* */ comments from Solidity code, leaving string literals intact.
+func stripComments(code string) string {
+	var b strings.Builder
+	b.Grow(len(code))
+
+	for i := 0; i < len(code); i++ {
+		switch {
+		case code[i] == '"' || code[i] == '\'':
+			quote := code[i]
+			start := i
+			for i++; i < len(code) && code[i] != quote && code[i] != '\n'; i++ {
+				if code[i] == '\\' {
+					i++
+				}
+			}
+			end := i + 1
+			if end > len(code) {
+				end = len(code)
+			}
+			b.WriteString(code[start:end])
+		case strings.HasPrefix(code[i:], "//"):
+			for i < len(code) && code[i] != '\n' {
+				i++
+			}
+			b.WriteByte('\n')
+		case strings.HasPrefix(code[i:], "/*"):
+			end := strings.Index(code[i+2:], "*/")
+			if end < 0 {
+				return b.String()
+			}
+			i += end + 3
+			b.WriteByte(' ')
+		default:
+			b.WriteByte(code[i])
+		}
+	}
+
+	return b.String()
+}
+
+// importClosure returns the sorted source unit names reachable from root through imports, including root.
+func importClosure(root string, infos map[string]*sourceInfo) ([]string, error) {
+	seen := map[string]bool{}
+	stack := []string{root}
+	for len(stack) > 0 {
+		name := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if seen[name] {
+			continue
+		}
+		info, ok := infos[name]
+		if !ok {
+			return nil, fmt.Errorf("source %s not found (imported from the closure of %s)", name, root)
+		}
+		seen[name] = true
+		stack = append(stack, info.imports...)
+	}
+
+	closure := make([]string, 0, len(seen))
+	for name := range seen {
+		closure = append(closure, name)
+	}
+	sort.Strings(closure)
+	return closure, nil
+}
@@ -0,0 +1,484 @@
+package gosolc
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// InterfaceID returns the ERC-165 interface id of the functions of the ABI: the XOR of their selectors, 0x prefixed.
+func (abi *ABI) InterfaceID() string {
+	return interfaceID(abi.Functions)
+}
+
+func interfaceID(functions []*ABIEntry) string {
+	var id [4]byte
+	for _, function := range functions {
+		selector := function.Selector()
+		for i := range id {
+			id[i] ^= selector[i]
+		}
+	}
+	return "0x" + hex.EncodeToString(id[:])
+}
+
+// InterfaceIDs returns the ERC-165 interface ids of the contracts without bytecode (interfaces and abstract
+// contracts, which the ABI can't tell apart), keyed by fully qualified name. The id is the XOR of all the functions
+// of the ABI, inherited ones included, so it differs from type(I).interfaceId for interfaces extending others such
+// as IERC165: StandardOutput.InterfaceIDs uses the AST to cover the declared functions of interfaces only.
+func (contracts CompilerOutput) InterfaceIDs() (map[string]string, error) {
+	ids := map[string]string{}
+	for _, fqName := range contracts.FullyQualifiedNames() {
+		contract, _ := contracts.Contract(fqName)
+		if bytecode, _, err := contractBytecodes(contract); err != nil || bytecode != "" {
+			continue
+		}
+		abi, err := contracts.ContractABI(fqName)
+		if err != nil {
+			return nil, err
+		}
+		ids[fqName] = abi.InterfaceID()
+	}
+	return ids, nil
+}
+
+// InterfaceIDs returns the ERC-165 interface ids of the interfaces of the output, keyed by fully qualified name.
+// The ids are computed from the functions declared by each interface, as type(I).interfaceId.
+func (out *StandardOutput) InterfaceIDs() (map[string]string, error) {
+	ids := map[string]string{}
+	for _, source := range out.Sources {
+		if source.AST == nil {
+			continue
+		}
+		for _, def := range FindNodes[*ContractDefinition](source.AST) {
+			if def.ContractKind != "interface" {
+				continue
+			}
+			fqName, err := out.contractName(def)
+			if err != nil {
+				return nil, err
+			}
+
+			var id [4]byte
+			for _, node := range def.Nodes {
+				function, ok := node.(*FunctionDefinition)
+				if !ok || function.FunctionSelector == "" {
+					continue
+				}
+				selector, err := hex.DecodeString(function.FunctionSelector)
+				if err != nil || len(selector) != 4 {
+					return nil, fmt.Errorf("invalid selector %q of %s.%s", function.FunctionSelector, fqName, function.Name)
+				}
+				for i := range id {
+					id[i] ^= selector[i]
+				}
+			}
+			ids[fqName] = "0x" + hex.EncodeToString(id[:])
+		}
+	}
+	return ids, nil
+}
+
+// InterfaceStandard is a standard interface that contracts can be checked against.
+type InterfaceStandard struct {
+	Name      string
+	Requires  []string    // Standards the standard extends, e.g. ERC-165 for ERC-721
+	Functions []*ABIEntry // Required functions
+	Events    []*ABIEntry // Required events
+}
+
+// NewInterfaceStandard defines a standard from Solidity-like declarations of its functions and events, e.g.
+// "function balanceOf(address owner) view returns (uint256)" or "event Transfer(address indexed, address indexed, uint256)".
+func NewInterfaceStandard(name string, requires []string, declarations ...string) (*InterfaceStandard, error) {
+	standard := &InterfaceStandard{Name: name, Requires: requires}
+	for _, declaration := range declarations {
+		entry, err := parseABIDeclaration(declaration)
+		if err != nil {
+			return nil, fmt.Errorf("standard %s: %v", name, err)
+		}
+		switch entry.Type {
+		case "function":
+			standard.Functions = append(standard.Functions, entry)
+		case "event":
+			standard.Events = append(standard.Events, entry)
+		}
+	}
+	return standard, nil
+}
+
+// InterfaceID returns the ERC-165 interface id of the functions of the standard.
+func (s *InterfaceStandard) InterfaceID() string {
+	return interfaceID(s.Functions)
+}
+
+// parseABIDeclaration parses the declaration of a function or event with elementary and array parameter types:
+// "function <name>(<params>) [view|pure|payable] [returns (<params>)]" or "event <name>(<params>) [anonymous]".
+func parseABIDeclaration(declaration string) (*ABIEntry, error) {
+	kind, rest, _ := strings.Cut(strings.TrimSpace(declaration), " ")
+	open, closing := strings.Index(rest, "("), strings.Index(rest, ")")
+	if (kind != "function" && kind != "event") || open <= 0 || closing < open {
+		return nil, fmt.Errorf("invalid declaration %q", declaration)
+	}
+
+	entry := &ABIEntry{Type: kind, Name: strings.TrimSpace(rest[:open])}
+	var err error
+	if entry.Inputs, err = parseDeclarationParameters(rest[open+1:closing], kind == "event"); err != nil {
+		return nil, fmt.Errorf("invalid declaration %q: %v", declaration, err)
+	}
+
+	modifiers := strings.TrimSpace(rest[closing+1:])
+	if returns, outputs, ok := strings.Cut(modifiers, "returns"); ok {
+		modifiers = returns
+		outputs = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(outputs), "("), ")")
+		if entry.Outputs, err = parseDeclarationParameters(outputs, false); err != nil {
+			return nil, fmt.Errorf("invalid declaration %q: %v", declaration, err)
+		}
+	}
+	if kind == "function" {
+		entry.StateMutability = "nonpayable"
+		if entry.Outputs == nil {
+			entry.Outputs = []ABIParameter{}
+		}
+	}
+	for _, modifier := range strings.Fields(modifiers) {
+		switch {
+		case kind == "event" && modifier == "anonymous":
+			entry.Anonymous = true
+		case kind == "function" && (modifier == "view" || modifier == "pure" || modifier == "payable"):
+			entry.StateMutability = modifier
+		case kind == "function" && modifier == "external":
+		default:
+			return nil, fmt.Errorf("invalid declaration %q: unexpected %q", declaration, modifier)
+		}
+	}
+	return entry, nil
+}
+
+// parseDeclarationParameters parses a comma separated list of "<type> [indexed] [name]" parameters.
+func parseDeclarationParameters(list string, event bool) ([]ABIParameter, error) {
+	params := []ABIParameter{}
+	if strings.TrimSpace(list) == "" {
+		return params, nil
+	}
+	for _, declaration := range strings.Split(list, ",") {
+		fields := strings.Fields(declaration)
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("empty parameter")
+		}
+		param := ABIParameter{Type: fields[0]}
+		for _, field := range fields[1:] {
+			switch field {
+			case "indexed":
+				if !event {
+					return nil, fmt.Errorf("only event parameters can be indexed")
+				}
+				param.Indexed = true
+			case "memory", "calldata":
+			default:
+				param.Name = field
+			}
+		}
+		if _, err := param.ABIType(); err != nil {
+			return nil, err
+		}
+		params = append(params, param)
+	}
+	return params, nil
+}
+
+// abiDeclaration formats an entry as a declaration accepted by NewInterfaceStandard.
+func abiDeclaration(entry *ABIEntry) string {
+	if entry.Type == "event" {
+		return "event " + eventSignature(entry)
+	}
+	s := "function " + entry.Signature()
+	if entry.StateMutability != "nonpayable" && entry.StateMutability != "" {
+		s += " " + entry.StateMutability
+	}
+	if len(entry.Outputs) > 0 {
+		s += " returns (" + canonicalTypes(entry.Outputs) + ")"
+	}
+	return s
+}
+
+var (
+	interfaceStandardsMu sync.RWMutex
+	interfaceStandards   = map[string]*InterfaceStandard{}
+)
+
+// RegisterInterfaceStandard registers a standard for CheckConformance and Standards. Registering an already
+// known name replaces it.
+func RegisterInterfaceStandard(standard *InterfaceStandard) {
+	interfaceStandardsMu.Lock()
+	defer interfaceStandardsMu.Unlock()
+	interfaceStandards[standard.Name] = standard
+}
+
+// InterfaceStandards returns the names of the registered standards, sorted.
+func InterfaceStandards() []string {
+	interfaceStandardsMu.RLock()
+	defer interfaceStandardsMu.RUnlock()
+
+	names := make([]string, 0, len(interfaceStandards))
+	for name := range interfaceStandards {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LookupInterfaceStandard returns the registered standard with the given name.
+func LookupInterfaceStandard(name string) (*InterfaceStandard, bool) {
+	interfaceStandardsMu.RLock()
+	defer interfaceStandardsMu.RUnlock()
+	standard, ok := interfaceStandards[name]
+	return standard, ok
+}
+
+// ConformanceIssueKind classifies a difference between a contract and a standard.
+type ConformanceIssueKind string
+
+const (
+	ConformanceMissingFunction    ConformanceIssueKind = "missing-function"    // No function with the name of a required function
+	ConformanceMismatchedFunction ConformanceIssueKind = "mismatched-function" // A function has the name of a required one but other parameter or return types, or changes state instead of being view
+	ConformanceMissingEvent       ConformanceIssueKind = "missing-event"       // No event with the name of a required event
+	ConformanceMismatchedEvent    ConformanceIssueKind = "mismatched-event"    // An event has the name of a required one but other parameter types or indexed flags
+)
+
+// ConformanceIssue is a required function or event a contract lacks or declares differently.
+type ConformanceIssue struct {
+	Kind     ConformanceIssueKind
+	Standard string // Name of the standard requiring the entry
+	Expected string // Declaration required by the standard
+	Found    string // Declaration of the contract, empty if missing
+}
+
+// String returns the issue in the "standard: kind: expected (found ...)" format.
+func (i *ConformanceIssue) String() string {
+	if i.Found == "" {
+		return fmt.Sprintf("%s: %s: %s", i.Standard, i.Kind, i.Expected)
+	}
+	return fmt.Sprintf("%s: %s: %s (found %s)", i.Standard, i.Kind, i.Expected, i.Found)
+}
+
+// ConformanceReport is the result of checking a contract against a standard and the standards it requires.
+type ConformanceReport struct {
+	Contract string
+	Standard string
+	Issues   []*ConformanceIssue
+}
+
+// Conforms reports whether the contract has all functions and events of the standard.
+func (r *ConformanceReport) Conforms() bool {
+	return len(r.Issues) == 0
+}
+
+// CheckConformance checks the ABI of a contract against a registered standard and the standards it requires.
+// Functions must have the signature and return types of the standard; view and pure functions must not change
+// state, while functions of the standard that change state may be implemented as view or pure, and payable is
+// not required (ERC-721 declares payable transfers that most implementations reject).
+func (contracts CompilerOutput) CheckConformance(fqName, standard string) (*ConformanceReport, error) {
+	abi, err := contracts.ContractABI(fqName)
+	if err != nil {
+		return nil, err
+	}
+	issues, err := abi.CheckConformance(standard)
+	if err != nil {
+		return nil, err
+	}
+	return &ConformanceReport{Contract: fqName, Standard: standard, Issues: issues}, nil
+}
+
+// CheckConformance checks the ABI against a registered standard and the standards it requires.
+func (abi *ABI) CheckConformance(standard string) ([]*ConformanceIssue, error) {
+	var issues []*ConformanceIssue
+	checked := map[string]bool{}
+	var check func(name string) error
+	check = func(name string) error {
+		if checked[name] {
+			return nil
+		}
+		checked[name] = true
+		s, ok := LookupInterfaceStandard(name)
+		if !ok {
+			return fmt.Errorf("interface standard %s is not registered (registered: %s)", name, strings.Join(InterfaceStandards(), ", "))
+		}
+		for _, required := range s.Requires {
+			if err := check(required); err != nil {
+				return err
+			}
+		}
+
+		for _, expected := range s.Functions {
+			if issue := checkFunctionConformance(abi, expected); issue != nil {
+				issue.Standard = s.Name
+				issues = append(issues, issue)
+			}
+		}
+		for _, expected := range s.Events {
+			if issue := checkEventConformance(abi, expected); issue != nil {
+				issue.Standard = s.Name
+				issues = append(issues, issue)
+			}
+		}
+		return nil
+	}
+	if err := check(standard); err != nil {
+		return nil, err
+	}
+	return issues, nil
+}
+
+func checkFunctionConformance(abi *ABI, expected *ABIEntry) *ConformanceIssue {
+	var found []string
+	for _, function := range abi.Functions {
+		if function.Name != expected.Name {
+			continue
+		}
+		if function.Signature() == expected.Signature() {
+			readOnly := expected.StateMutability == "view" || expected.StateMutability == "pure"
+			if canonicalTypes(function.Outputs) == canonicalTypes(expected.Outputs) &&
+				(!readOnly || stateMutabilityRank[function.StateMutability] <= stateMutabilityRank[expected.StateMutability]) {
+				return nil
+			}
+			found = []string{abiDeclaration(function)}
+			break
+		}
+		found = append(found, abiDeclaration(function))
+	}
+	if len(found) == 0 {
+		return &ConformanceIssue{Kind: ConformanceMissingFunction, Expected: abiDeclaration(expected)}
+	}
+	return &ConformanceIssue{Kind: ConformanceMismatchedFunction, Expected: abiDeclaration(expected), Found: strings.Join(found, ", ")}
+}
+
+func checkEventConformance(abi *ABI, expected *ABIEntry) *ConformanceIssue {
+	var found []string
+	for _, event := range abi.Events {
+		if event.Name != expected.Name {
+			continue
+		}
+		if eventSignature(event) == eventSignature(expected) {
+			return nil
+		}
+		found = append(found, abiDeclaration(event))
+	}
+	if len(found) == 0 {
+		return &ConformanceIssue{Kind: ConformanceMissingEvent, Expected: abiDeclaration(expected)}
+	}
+	return &ConformanceIssue{Kind: ConformanceMismatchedEvent, Expected: abiDeclaration(expected), Found: strings.Join(found, ", ")}
+}
+
+// Standards returns the registered standards the contract conforms to, sorted.
+func (contracts CompilerOutput) Standards(fqName string) ([]string, error) {
+	abi, err := contracts.ContractABI(fqName)
+	if err != nil {
+		return nil, err
+	}
+	var standards []string
+	for _, name := range InterfaceStandards() {
+		issues, err := abi.CheckConformance(name)
+		if err != nil {
+			return nil, err
+		}
+		if len(issues) == 0 {
+			standards = append(standards, name)
+		}
+	}
+	return standards, nil
+}
+
+// builtinInterfaceStandards are the standards registered by default.
+var builtinInterfaceStandards = []struct {
+	name         string
+	requires     []string
+	declarations []string
+}{
+	{"ERC-165", nil, []string{
+		"function supportsInterface(bytes4 interfaceId) view returns (bool)",
+	}},
+	{"ERC-20", nil, []string{
+		"function totalSupply() view returns (uint256)",
+		"function balanceOf(address account) view returns (uint256)",
+		"function transfer(address to, uint256 value) returns (bool)",
+		"function allowance(address owner, address spender) view returns (uint256)",
+		"function approve(address spender, uint256 value) returns (bool)",
+		"function transferFrom(address from, address to, uint256 value) returns (bool)",
+		"event Transfer(address indexed from, address indexed to, uint256 value)",
+		"event Approval(address indexed owner, address indexed spender, uint256 value)",
+	}},
+	{"ERC-2612", []string{"ERC-20"}, []string{
+		"function permit(address owner, address spender, uint256 value, uint256 deadline, uint8 v, bytes32 r, bytes32 s)",
+		"function nonces(address owner) view returns (uint256)",
+		"function DOMAIN_SEPARATOR() view returns (bytes32)",
+	}},
+	{"ERC-721", []string{"ERC-165"}, []string{
+		"function balanceOf(address owner) view returns (uint256)",
+		"function ownerOf(uint256 tokenId) view returns (address)",
+		"function safeTransferFrom(address from, address to, uint256 tokenId, bytes data) payable",
+		"function safeTransferFrom(address from, address to, uint256 tokenId) payable",
+		"function transferFrom(address from, address to, uint256 tokenId) payable",
+		"function approve(address approved, uint256 tokenId) payable",
+		"function setApprovalForAll(address operator, bool approved)",
+		"function getApproved(uint256 tokenId) view returns (address)",
+		"function isApprovedForAll(address owner, address operator) view returns (bool)",
+		"event Transfer(address indexed from, address indexed to, uint256 indexed tokenId)",
+		"event Approval(address indexed owner, address indexed approved, uint256 indexed tokenId)",
+		"event ApprovalForAll(address indexed owner, address indexed operator, bool approved)",
+	}},
+	{"ERC-721Metadata", []string{"ERC-721"}, []string{
+		"function name() view returns (string)",
+		"function symbol() view returns (string)",
+		"function tokenURI(uint256 tokenId) view returns (string)",
+	}},
+	{"ERC-1155", []string{"ERC-165"}, []string{
+		"function safeTransferFrom(address from, address to, uint256 id, uint256 value, bytes data)",
+		"function safeBatchTransferFrom(address from, address to, uint256[] ids, uint256[] values, bytes data)",
+		"function balanceOf(address account, uint256 id) view returns (uint256)",
+		"function balanceOfBatch(address[] accounts, uint256[] ids) view returns (uint256[])",
+		"function setApprovalForAll(address operator, bool approved)",
+		"function isApprovedForAll(address account, address operator) view returns (bool)",
+		"event TransferSingle(address indexed operator, address indexed from, address indexed to, uint256 id, uint256 value)",
+		"event TransferBatch(address indexed operator, address indexed from, address indexed to, uint256[] ids, uint256[] values)",
+		"event ApprovalForAll(address indexed account, address indexed operator, bool approved)",
+		"event URI(string value, uint256 indexed id)",
+	}},
+	{"ERC-1271", nil, []string{
+		"function isValidSignature(bytes32 hash, bytes signature) view returns (bytes4)",
+	}},
+	{"ERC-2981", []string{"ERC-165"}, []string{
+		"function royaltyInfo(uint256 tokenId, uint256 salePrice) view returns (address, uint256)",
+	}},
+	{"ERC-4626", []string{"ERC-20"}, []string{
+		"function asset() view returns (address)",
+		"function totalAssets() view returns (uint256)",
+		"function convertToShares(uint256 assets) view returns (uint256)",
+		"function convertToAssets(uint256 shares) view returns (uint256)",
+		"function maxDeposit(address receiver) view returns (uint256)",
+		"function previewDeposit(uint256 assets) view returns (uint256)",
+		"function deposit(uint256 assets, address receiver) returns (uint256)",
+		"function maxMint(address receiver) view returns (uint256)",
+		"function previewMint(uint256 shares) view returns (uint256)",
+		"function mint(uint256 shares, address receiver) returns (uint256)",
+		"function maxWithdraw(address owner) view returns (uint256)",
+		"function previewWithdraw(uint256 assets) view returns (uint256)",
+		"function withdraw(uint256 assets, address receiver, address owner) returns (uint256)",
+		"function maxRedeem(address owner) view returns (uint256)",
+		"function previewRedeem(uint256 shares) view returns (uint256)",
+		"function redeem(uint256 shares, address receiver, address owner) returns (uint256)",
+		"event Deposit(address indexed sender, address indexed owner, uint256 assets, uint256 shares)",
+		"event Withdraw(address indexed sender, address indexed receiver, address indexed owner, uint256 assets, uint256 shares)",
+	}},
+}
+
+func init() {
+	for _, builtin := range builtinInterfaceStandards {
+		standard, err := NewInterfaceStandard(builtin.name, builtin.requires, builtin.declarations...)
+		if err != nil {
+			panic(err)
+		}
+		RegisterInterfaceStandard(standard)
+	}
+}
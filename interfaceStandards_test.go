@@ -0,0 +1,146 @@
+package gosolc
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestInterfaceStandardIDs(t *testing.T) {
+	for name, expected := range map[string]string{
+		"ERC-165":         "0x01ffc9a7",
+		"ERC-721":         "0x80ac58cd",
+		"ERC-721Metadata": "0x5b5e139f",
+		"ERC-1155":        "0xd9b67a26",
+		"ERC-2981":        "0x2a55205a",
+	} {
+		standard, ok := LookupInterfaceStandard(name)
+		if !ok {
+			t.Fatalf("standard %s is not registered", name)
+		}
+		if id := standard.InterfaceID(); id != expected {
+			t.Errorf("%s: expected interface id %s, got %s", name, expected, id)
+		}
+	}
+}
+
+func TestInterfaceIDs(t *testing.T) {
+	standard, _ := LookupInterfaceStandard("ERC-721")
+	erc165, _ := LookupInterfaceStandard("ERC-165")
+	abi721, _ := json.Marshal(append(append([]*ABIEntry{}, standard.Functions...), erc165.Functions...))
+	abi165, _ := json.Marshal(erc165.Functions)
+
+	var output CompilerOutput
+	err := json.Unmarshal([]byte(`{
+		"IERC721.sol": {"IERC721": {"abi": `+string(abi721)+`, "evm": {"bytecode": {"object": ""}, "deployedBytecode": {"object": ""}}}},
+		"IERC165.sol": {"IERC165": {"abi": `+string(abi165)+`, "evm": {"bytecode": {"object": ""}, "deployedBytecode": {"object": ""}}}},
+		"NFT.sol": {"NFT": {"abi": `+string(abi721)+`, "evm": {"bytecode": {"object": "6080"}, "deployedBytecode": {"object": "6080"}}}}
+	}`), &output)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids, err := output.InterfaceIDs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// supportsInterface is part of the IERC721 ABI, whether it is declared or inherited
+	if fmt.Sprint(ids) != "map[IERC165.sol:IERC165:0x01ffc9a7 IERC721.sol:IERC721:0x8153916a]" {
+		t.Errorf("unexpected interface ids %v", ids)
+	}
+}
+
+func TestInterfaceIDsExtendedInterfaces(t *testing.T) {
+	erc165, _ := LookupInterfaceStandard("ERC-165")
+	erc721, _ := LookupInterfaceStandard("ERC-721")
+	metadata, _ := LookupInterfaceStandard("ERC-721Metadata")
+	functions := append([]*ABIEntry{}, erc165.Functions...)
+	abi165, _ := json.Marshal(functions)
+	functions = append(functions, erc721.Functions...)
+	abi721, _ := json.Marshal(functions)
+	functions = append(functions, metadata.Functions...)
+	abiMetadata, _ := json.Marshal(functions)
+
+	var output CompilerOutput
+	err := json.Unmarshal([]byte(`{
+		"IERC165.sol": {"IERC165": {"abi": `+string(abi165)+`, "evm": {"bytecode": {"object": ""}, "deployedBytecode": {"object": ""}}}},
+		"IERC721.sol": {"IERC721": {"abi": `+string(abi721)+`, "evm": {"bytecode": {"object": ""}, "deployedBytecode": {"object": ""}}}},
+		"IERC721Metadata.sol": {"IERC721Metadata": {"abi": `+string(abiMetadata)+`, "evm": {"bytecode": {"object": ""}, "deployedBytecode": {"object": ""}}}}
+	}`), &output)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the ids cover all the functions of each ABI, whatever other interfaces the output holds
+	ids, err := output.InterfaceIDs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(ids) != "map[IERC165.sol:IERC165:0x01ffc9a7 IERC721.sol:IERC721:0x8153916a IERC721Metadata.sol:IERC721Metadata:0xda0d82f5]" {
+		t.Fatalf("unexpected interface ids %v", ids)
+	}
+}
+
+func TestCheckConformance(t *testing.T) {
+	abi, err := ParseABI([]byte(`[
+		{"type": "function", "name": "totalSupply", "inputs": [], "outputs": [{"name": "", "type": "uint256"}], "stateMutability": "view"},
+		{"type": "function", "name": "balanceOf", "inputs": [{"name": "account", "type": "address"}], "outputs": [{"name": "", "type": "uint256"}], "stateMutability": "nonpayable"},
+		{"type": "function", "name": "transfer", "inputs": [{"name": "to", "type": "address"}, {"name": "amount", "type": "uint256"}], "outputs": [{"name": "", "type": "bool"}], "stateMutability": "nonpayable"},
+		{"type": "function", "name": "allowance", "inputs": [{"name": "owner", "type": "address"}, {"name": "spender", "type": "address"}], "outputs": [{"name": "", "type": "uint256"}], "stateMutability": "view"},
+		{"type": "function", "name": "approve", "inputs": [{"name": "spender", "type": "address"}, {"name": "amount", "type": "uint256"}], "outputs": [], "stateMutability": "nonpayable"},
+		{"type": "event", "name": "Transfer", "inputs": [{"name": "from", "type": "address", "indexed": true}, {"name": "to", "type": "address", "indexed": true}, {"name": "value", "type": "uint256", "indexed": false}], "anonymous": false},
+		{"type": "event", "name": "Approval", "inputs": [{"name": "owner", "type": "address", "indexed": true}, {"name": "spender", "type": "address", "indexed": false}, {"name": "value", "type": "uint256", "indexed": false}], "anonymous": false}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issues, err := abi.CheckConformance("ERC-2612")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, issue := range issues {
+		got = append(got, issue.String())
+	}
+	expected := []string{
+		"ERC-20: mismatched-function: function balanceOf(address) view returns (uint256) (found function balanceOf(address) returns (uint256))",
+		"ERC-20: mismatched-function: function approve(address,uint256) returns (bool) (found function approve(address,uint256))",
+		"ERC-20: missing-function: function transferFrom(address,address,uint256) returns (bool)",
+		"ERC-20: mismatched-event: event Approval(address indexed,address indexed,uint256) (found event Approval(address indexed,address,uint256))",
+		"ERC-2612: missing-function: function permit(address,address,uint256,uint256,uint8,bytes32,bytes32)",
+		"ERC-2612: missing-function: function nonces(address) view returns (uint256)",
+		"ERC-2612: missing-function: function DOMAIN_SEPARATOR() view returns (bytes32)",
+	}
+	if fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+
+	if _, err := abi.CheckConformance("ERC-0"); err == nil {
+		t.Error("expected an error for an unknown standard")
+	}
+
+	// user defined standards
+	standard, err := NewInterfaceStandard("Ownable", nil, "function owner() view returns (address)", "event OwnershipTransferred(address indexed previousOwner, address indexed newOwner)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	RegisterInterfaceStandard(standard)
+	if issues, _ := abi.CheckConformance("Ownable"); len(issues) != 2 {
+		t.Errorf("expected 2 issues, got %v", issues)
+	}
+	if _, err := NewInterfaceStandard("Invalid", nil, "function f(uint7)"); err == nil {
+		t.Error("expected an error for an invalid declaration")
+	}
+}
+
+func TestStandards(t *testing.T) {
+	output := loadTestOutput(t, "testdata_output.json")
+	standards, err := output.Standards("dummy_token.sol:Token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(standards) != 0 {
+		t.Errorf("expected no standards, got %v", standards)
+	}
+}
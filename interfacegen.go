@@ -0,0 +1,309 @@
+package gosolc
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// interfacePragma is the version pragma of generated interfaces; custom errors require solc 0.8.4.
+const interfacePragma = "^0.8.4"
+
+// SolidityInterface generates a Solidity interface (named I<Contract>) for a compiled contract.
+func (contracts CompilerOutput) SolidityInterface(fqName string) (string, error) {
+	abi, err := contracts.ContractABI(fqName)
+	if err != nil {
+		return "", err
+	}
+	_, name := splitFullyQualifiedName(fqName)
+	return GenerateSolidityInterface("I"+name, abi)
+}
+
+// GenerateSolidityInterface generates the source of a compilable Solidity interface for an ABI, with
+// the struct definitions, events, custom errors, functions, receive and fallback functions of the ABI.
+// Structs are named after their definition in the original contract, enums and contract types are
+// declared with their ABI types (uint8 and address), which keeps selectors unchanged.
+func GenerateSolidityInterface(name string, abi *ABI) (string, error) {
+	g := &interfaceGenerator{structs: map[string]*interfaceStruct{}}
+	if err := g.collectStructs(abi); err != nil {
+		return "", err
+	}
+
+	var members []string
+
+	structNames := make([]string, 0, len(g.structs))
+	for qualified := range g.structs {
+		structNames = append(structNames, qualified)
+	}
+	sort.Slice(structNames, func(i, j int) bool {
+		return g.structs[structNames[i]].name < g.structs[structNames[j]].name
+	})
+	for _, qualified := range structNames {
+		s := g.structs[qualified]
+		var fields []string
+		for i, component := range s.components {
+			t, err := g.solidityType(component)
+			if err != nil {
+				return "", err
+			}
+			fieldName := component.Name
+			if fieldName == "" {
+				fieldName = fmt.Sprintf("field%d", i)
+			}
+			fields = append(fields, fmt.Sprintf("        %s %s;\n", t, fieldName))
+		}
+		members = append(members, fmt.Sprintf("    struct %s {\n%s    }\n", s.name, strings.Join(fields, "")))
+	}
+
+	for _, event := range abi.Events {
+		params, err := g.parameters(event.Inputs, "")
+		if err != nil {
+			return "", fmt.Errorf("event %s: %v", event.Name, err)
+		}
+		anonymous := ""
+		if event.Anonymous {
+			anonymous = " anonymous"
+		}
+		members = append(members, fmt.Sprintf("    event %s(%s)%s;\n", event.Name, params, anonymous))
+	}
+
+	for _, abiError := range abi.Errors {
+		params, err := g.parameters(abiError.Inputs, "")
+		if err != nil {
+			return "", fmt.Errorf("error %s: %v", abiError.Name, err)
+		}
+		members = append(members, fmt.Sprintf("    error %s(%s);\n", abiError.Name, params))
+	}
+
+	if abi.Receive != nil {
+		members = append(members, "    receive() external payable;\n")
+	}
+	if abi.Fallback != nil {
+		members = append(members, fmt.Sprintf("    fallback() external%s;\n", solidityMutability(abi.Fallback.StateMutability)))
+	}
+
+	for _, function := range abi.Functions {
+		params, err := g.parameters(function.Inputs, "calldata")
+		if err != nil {
+			return "", fmt.Errorf("function %s: %v", function.Name, err)
+		}
+		declaration := fmt.Sprintf("    function %s(%s) external%s", function.Name, params, solidityMutability(function.StateMutability))
+		if len(function.Outputs) > 0 {
+			returns, err := g.parameters(function.Outputs, "memory")
+			if err != nil {
+				return "", fmt.Errorf("function %s: %v", function.Name, err)
+			}
+			declaration += fmt.Sprintf(" returns (%s)", returns)
+		}
+		members = append(members, declaration+";\n")
+	}
+
+	var src strings.Builder
+	src.WriteString("// SPDX-License-Identifier: UNLICENSED\n")
+	fmt.Fprintf(&src, "pragma solidity %s;\n\n", interfacePragma)
+	fmt.Fprintf(&src, "interface %s {\n%s}\n", name, strings.Join(members, "\n"))
+	return src.String(), nil
+}
+
+// VerifySolidityInterface compiles an interface generated for abi with the settings and solc-js of the
+// compiler, and checks that its function selectors, event topics and error selectors match the ABI.
+func (c Compiler) VerifySolidityInterface(name, source string, abi *ABI) error {
+	compiler := &Compiler{
+		CompilerConfig: c.CompilerConfig.sourceConfig(-1),
+		SolcJs:         c.SolcJs,
+	}
+
+	var err error
+	compiler.Sources, err = sourcesMap(map[string]string{name + ".sol": source})
+	if err != nil {
+		return err
+	}
+	compiler.CompilerInput, err = compiler.getInputJSON()
+	if err != nil {
+		return fmt.Errorf("failed to get input JSON: %v", err)
+	}
+
+	output, err := compiler.Compile()
+	if err != nil {
+		return fmt.Errorf("failed to compile interface %s: %w", name, err)
+	}
+	compiled, err := output.ContractABI(name + ".sol:" + name)
+	if err != nil {
+		return err
+	}
+
+	var mismatches []string
+	mismatches = append(mismatches, compareIdentifiers("function", abiIdentifiers(abi.Functions, true), abiIdentifiers(compiled.Functions, true))...)
+	mismatches = append(mismatches, compareIdentifiers("event", abiIdentifiers(abi.Events, false), abiIdentifiers(compiled.Events, false))...)
+	mismatches = append(mismatches, compareIdentifiers("error", abiIdentifiers(abi.Errors, true), abiIdentifiers(compiled.Errors, true))...)
+	if len(mismatches) > 0 {
+		return fmt.Errorf("interface %s doesn't match the ABI:\n%s", name, strings.Join(mismatches, "\n"))
+	}
+	return nil
+}
+
+// abiIdentifiers maps the signatures of entries to their selectors (4 bytes) or topics (32 bytes), in hex.
+func abiIdentifiers(entries []*ABIEntry, selectors bool) map[string]string {
+	identifiers := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		if selectors {
+			selector := entry.Selector()
+			identifiers[entry.Signature()] = fmt.Sprintf("%x", selector)
+		} else {
+			topic := entry.Topic()
+			identifiers[entry.Signature()] = fmt.Sprintf("%x", topic)
+		}
+	}
+	return identifiers
+}
+
+// compareIdentifiers lists the entries missing from or added to the expected identifiers.
+func compareIdentifiers(kind string, expected, actual map[string]string) []string {
+	var mismatches []string
+	for signature, id := range expected {
+		if actual[signature] != id {
+			mismatches = append(mismatches, fmt.Sprintf("  missing %s %s (%s)", kind, signature, id))
+		}
+	}
+	for signature, id := range actual {
+		if expected[signature] != id {
+			mismatches = append(mismatches, fmt.Sprintf("  unexpected %s %s (%s)", kind, signature, id))
+		}
+	}
+	sort.Strings(mismatches)
+	return mismatches
+}
+
+// interfaceStruct is a struct definition referenced by the ABI.
+type interfaceStruct struct {
+	name       string // Name in the generated interface
+	components []ABIParameter
+}
+
+// interfaceGenerator holds the struct definitions of the generated interface, keyed by qualified name.
+type interfaceGenerator struct {
+	structs map[string]*interfaceStruct
+}
+
+// collectStructs registers the structs of all entries. Structs are named after their definition
+// (e.g. "struct Multicall3.Call" becomes Call) unless two definitions share a name, in which case
+// they are qualified by their scope (Multicall3_Call).
+func (g *interfaceGenerator) collectStructs(abi *ABI) error {
+	var collect func(params []ABIParameter) error
+	collect = func(params []ABIParameter) error {
+		for _, param := range params {
+			if !strings.HasPrefix(param.Type, "tuple") {
+				continue
+			}
+			qualified := structName(param.InternalType)
+			if qualified == "" {
+				return fmt.Errorf("tuple parameter %q has no struct internal type", param.Name)
+			}
+			if _, ok := g.structs[qualified]; !ok {
+				g.structs[qualified] = &interfaceStruct{components: param.Components}
+			}
+			if err := collect(param.Components); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, entry := range abi.Entries() {
+		if err := collect(entry.Inputs); err != nil {
+			return err
+		}
+		if err := collect(entry.Outputs); err != nil {
+			return err
+		}
+	}
+
+	counts := map[string]int{}
+	for qualified := range g.structs {
+		counts[unqualifiedName(qualified)]++
+	}
+	for qualified, s := range g.structs {
+		s.name = unqualifiedName(qualified)
+		if counts[s.name] > 1 {
+			s.name = strings.ReplaceAll(qualified, ".", "_")
+		}
+	}
+	return nil
+}
+
+// parameters returns a Solidity parameter list. location is the data location of reference types
+// ("calldata", "memory" or "" for events and errors).
+func (g *interfaceGenerator) parameters(params []ABIParameter, location string) (string, error) {
+	var list []string
+	for _, param := range params {
+		t, err := g.solidityType(param)
+		if err != nil {
+			return "", err
+		}
+		declaration := t
+
+		abiType, err := param.ABIType()
+		if err != nil {
+			return "", err
+		}
+		if location != "" {
+			switch abiType.Kind {
+			case BytesKind, StringKind, SliceKind, ArrayKind, TupleKind:
+				declaration += " " + location
+			}
+		}
+		if param.Indexed {
+			declaration += " indexed"
+		}
+		if param.Name != "" {
+			declaration += " " + param.Name
+		}
+		list = append(list, declaration)
+	}
+	return strings.Join(list, ", "), nil
+}
+
+// solidityType returns the Solidity type of a parameter, using the generated struct names for tuples.
+func (g *interfaceGenerator) solidityType(param ABIParameter) (string, error) {
+	if !strings.HasPrefix(param.Type, "tuple") {
+		if param.Type == "function" {
+			if !strings.HasPrefix(param.InternalType, "function ") {
+				return "", fmt.Errorf("function type parameter %q has no internal type", param.Name)
+			}
+			return param.InternalType, nil
+		}
+		return param.Type, nil
+	}
+
+	s, ok := g.structs[structName(param.InternalType)]
+	if !ok {
+		return "", fmt.Errorf("unknown struct %s", param.InternalType)
+	}
+	return s.name + strings.TrimPrefix(param.Type, "tuple"), nil
+}
+
+// structName returns the qualified struct name of a tuple internal type, e.g. "Multicall3.Call"
+// for "struct Multicall3.Call[]".
+func structName(internalType string) string {
+	if !strings.HasPrefix(internalType, "struct ") {
+		return ""
+	}
+	name := strings.TrimPrefix(internalType, "struct ")
+	if i := strings.Index(name, "["); i >= 0 {
+		name = name[:i]
+	}
+	return name
+}
+
+// unqualifiedName strips the scope of a qualified name.
+func unqualifiedName(name string) string {
+	return name[strings.LastIndex(name, ".")+1:]
+}
+
+// solidityMutability returns the state mutability keyword of a function declaration.
+func solidityMutability(stateMutability string) string {
+	if stateMutability == "" || stateMutability == "nonpayable" {
+		return ""
+	}
+	return " " + stateMutability
+}
@@ -0,0 +1,53 @@
+package gosolc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateSolidityInterface(t *testing.T) {
+	contracts := loadTestOutput(t, "abi_output.json")
+	src, err := contracts.SolidityInterface("Token.sol:Token")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		"interface IToken {",
+		"    struct Call3 {\n        address target;\n        bool allowFailure;\n        bytes callData;\n    }\n",
+		"    event TransferBatch(address indexed operator, address indexed from, address indexed to, uint256[] ids, uint256[] values);\n",
+		"    error ERC20InsufficientBalance(address sender, uint256 balance, uint256 needed);\n",
+		"    fallback() external payable;\n",
+		"    function aggregate3(Call3[] calldata calls) external payable returns (Result[] memory returnData);\n",
+		"    function safeTransferFrom(address from, address to, uint256 tokenId, bytes calldata data) external;\n",
+	} {
+		if !strings.Contains(src, expected) {
+			t.Errorf("generated interface is missing %q", expected)
+		}
+	}
+}
+
+func TestE2EVerifySolidityInterface(t *testing.T) {
+	if solcJS_0_8_29 == "" {
+		t.Skip("embedded soljson is not available")
+	}
+
+	c, err := NewDefaultCompiler("testdata/contracts")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	contracts := loadTestOutput(t, "abi_output.json")
+	abi, err := contracts.ContractABI("Token.sol:Token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := GenerateSolidityInterface("IToken", abi)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.VerifySolidityInterface("IToken", src, abi); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,215 @@
+package gosolc
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// LinkReference is the position of a library address placeholder in a bytecode, in bytes.
+type LinkReference struct {
+	Start  int `json:"start"`
+	Length int `json:"length"`
+}
+
+// LinkReferences maps source unit names to library names to the placeholders of that library,
+// as reported by solc in evm.bytecode.linkReferences and evm.deployedBytecode.linkReferences.
+type LinkReferences map[string]map[string][]LinkReference
+
+// LinkedContract holds the creation and runtime bytecode of a contract with all libraries linked.
+type LinkedContract struct {
+	Bytecode         string `json:"bytecode"`         // Linked creation bytecode (hex, without 0x prefix)
+	DeployedBytecode string `json:"deployedBytecode"` // Linked runtime bytecode (hex, without 0x prefix)
+}
+
+// LinkBytecode replaces the library placeholders (__$<hash>$__) of a hex bytecode with library addresses.
+// libraries maps fully qualified library names (e.g. "libs/Math.sol:Math") to their hex addresses.
+// An error is returned if a referenced library has no address.
+func LinkBytecode(bytecode string, references LinkReferences, libraries map[string]string) (string, error) {
+	code := []byte(strings.TrimPrefix(bytecode, "0x"))
+
+	var unlinked []string
+	for file, fileReferences := range references {
+		for name, positions := range fileReferences {
+			fqName := file + ":" + name
+			address, ok := libraries[fqName]
+			if !ok {
+				unlinked = append(unlinked, fqName)
+				continue
+			}
+
+			addressHex, err := normalizeAddress(address)
+			if err != nil {
+				return "", fmt.Errorf("invalid address for library %s: %v", fqName, err)
+			}
+
+			for _, ref := range positions {
+				start, end := ref.Start*2, (ref.Start+ref.Length)*2
+				if ref.Length != 20 || end > len(code) {
+					return "", fmt.Errorf("invalid link reference for library %s at offset %d", fqName, ref.Start)
+				}
+				copy(code[start:end], addressHex)
+			}
+		}
+	}
+
+	if len(unlinked) > 0 {
+		sort.Strings(unlinked)
+		return "", fmt.Errorf("bytecode has unlinked references to libraries: %s", strings.Join(unlinked, ", "))
+	}
+	if strings.Contains(string(code), "__$") {
+		return "", fmt.Errorf("bytecode has unlinked library placeholders without link references")
+	}
+
+	return string(code), nil
+}
+
+// normalizeAddress validates a hex address and returns its 40 lowercase hex digits.
+func normalizeAddress(address string) (string, error) {
+	address = strings.ToLower(strings.TrimPrefix(strings.TrimPrefix(address, "0x"), "0X"))
+	if len(address) != 40 {
+		return "", fmt.Errorf("expected 20 bytes, got %q", address)
+	}
+	if _, err := hex.DecodeString(address); err != nil {
+		return "", fmt.Errorf("invalid hex %q", address)
+	}
+	return address, nil
+}
+
+// GetLinkReferences returns the link references of the creation and runtime bytecode of a contract.
+func (contracts CompilerOutput) GetLinkReferences(fqName string) (LinkReferences, LinkReferences, error) {
+	contract, err := contracts.Contract(fqName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	evm, ok := contract["evm"].(map[string]interface{})
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid evm output for contract %s", fqName)
+	}
+
+	var references, deployedReferences LinkReferences
+	if bytecode, ok := evm["bytecode"].(map[string]interface{}); ok {
+		if err := decodeOutput(bytecode["linkReferences"], &references); err != nil {
+			return nil, nil, fmt.Errorf("invalid link references for contract %s: %v", fqName, err)
+		}
+	}
+	if deployedBytecode, ok := evm["deployedBytecode"].(map[string]interface{}); ok {
+		if err := decodeOutput(deployedBytecode["linkReferences"], &deployedReferences); err != nil {
+			return nil, nil, fmt.Errorf("invalid deployed link references for contract %s: %v", fqName, err)
+		}
+	}
+
+	return references, deployedReferences, nil
+}
+
+// LinkContract links the creation and runtime bytecode of a contract.
+// libraries maps fully qualified library names (e.g. "libs/Math.sol:Math") to their addresses.
+func (contracts CompilerOutput) LinkContract(fqName string, libraries map[string]string) (*LinkedContract, error) {
+	contract, err := contracts.Contract(fqName)
+	if err != nil {
+		return nil, err
+	}
+
+	bytecode, deployedBytecode, err := contractBytecodes(contract)
+	if err != nil {
+		return nil, fmt.Errorf("%v for contract %s", err, fqName)
+	}
+
+	references, deployedReferences, err := contracts.GetLinkReferences(fqName)
+	if err != nil {
+		return nil, err
+	}
+
+	linked := &LinkedContract{}
+	linked.Bytecode, err = LinkBytecode(bytecode, references, libraries)
+	if err != nil {
+		return nil, fmt.Errorf("failed to link bytecode of %s: %w", fqName, err)
+	}
+	linked.DeployedBytecode, err = LinkBytecode(deployedBytecode, deployedReferences, libraries)
+	if err != nil {
+		return nil, fmt.Errorf("failed to link deployed bytecode of %s: %w", fqName, err)
+	}
+
+	return linked, nil
+}
+
+// LibraryDeploymentOrder returns the fully qualified names of all libraries a contract depends on,
+// directly or through other libraries, ordered so that every library comes after the libraries it links.
+func (contracts CompilerOutput) LibraryDeploymentOrder(fqName string) ([]string, error) {
+	root, _, err := contracts.resolveContract(fqName)
+	if err != nil {
+		return nil, err
+	}
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := map[string]int{}
+	var order []string
+
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		switch state[name] {
+		case visiting:
+			return fmt.Errorf("circular library dependency: %s", strings.Join(append(path, name), " -> "))
+		case visited:
+			return nil
+		}
+		state[name] = visiting
+
+		references, deployedReferences, err := contracts.GetLinkReferences(name)
+		if err != nil {
+			return err
+		}
+
+		var deps []string
+		for _, refs := range []LinkReferences{references, deployedReferences} {
+			for file, fileReferences := range refs {
+				for library := range fileReferences {
+					deps = append(deps, file+":"+library)
+				}
+			}
+		}
+		sort.Strings(deps)
+
+		for _, dep := range deps {
+			if err := visit(dep, append(path, name)); err != nil {
+				return err
+			}
+		}
+
+		state[name] = visited
+		if name != root {
+			order = append(order, name)
+		}
+		return nil
+	}
+
+	if err := visit(root, nil); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// contractBytecodes returns the creation and runtime bytecode objects of a contract output.
+func contractBytecodes(contract map[string]interface{}) (string, string, error) {
+	evm, ok := contract["evm"].(map[string]interface{})
+	if !ok {
+		return "", "", fmt.Errorf("invalid evm output")
+	}
+	creation, _ := evm["bytecode"].(map[string]interface{})
+	bytecode, ok := creation["object"].(string)
+	if !ok {
+		return "", "", fmt.Errorf("invalid bytecode output")
+	}
+	runtime, _ := evm["deployedBytecode"].(map[string]interface{})
+	deployedBytecode, ok := runtime["object"].(string)
+	if !ok {
+		return "", "", fmt.Errorf("invalid deployed bytecode output")
+	}
+	return bytecode, deployedBytecode, nil
+}
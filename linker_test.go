@@ -0,0 +1,71 @@
+package gosolc
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// linkedOutput builds a compiler output where Vault links Math and Strings, and Strings links Math.
+func linkedOutput() CompilerOutput {
+	placeholder := "__$" + strings.Repeat("a", 34) + "$__"
+	contract := func(code string, refs map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"evm": map[string]interface{}{
+				"bytecode":         map[string]interface{}{"object": code, "linkReferences": refs},
+				"deployedBytecode": map[string]interface{}{"object": code, "linkReferences": refs},
+			},
+		}
+	}
+	ref := func(start int) []interface{} {
+		return []interface{}{map[string]interface{}{"start": float64(start), "length": float64(20)}}
+	}
+
+	return CompilerOutput{
+		"libs/Math.sol": map[string]interface{}{
+			"Math":    contract("6000", map[string]interface{}{}),
+			"Strings": contract("73"+placeholder+"00", map[string]interface{}{"libs/Math.sol": map[string]interface{}{"Math": ref(1)}}),
+		},
+		"Vault.sol": map[string]interface{}{
+			"Vault": contract("73"+placeholder+"73"+placeholder, map[string]interface{}{
+				"libs/Math.sol": map[string]interface{}{"Math": ref(1), "Strings": ref(22)},
+			}),
+		},
+	}
+}
+
+func TestLinkContract(t *testing.T) {
+	contracts := linkedOutput()
+	math := "0x" + strings.Repeat("11", 20)
+	strs := "0x" + strings.Repeat("AB", 20)
+
+	linked, err := contracts.LinkContract("Vault", map[string]string{
+		"libs/Math.sol:Math":    math,
+		"libs/Math.sol:Strings": strs,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "73" + strings.Repeat("11", 20) + "73" + strings.Repeat("ab", 20)
+	if linked.Bytecode != expected || linked.DeployedBytecode != expected {
+		t.Fatalf("unexpected linked bytecode %s", linked.Bytecode)
+	}
+
+	if _, err := contracts.LinkContract("Vault.sol:Vault", map[string]string{"libs/Math.sol:Math": math}); err == nil ||
+		!strings.Contains(err.Error(), "libs/Math.sol:Strings") {
+		t.Fatalf("expected unlinked reference error, got %v", err)
+	}
+}
+
+func TestLibraryDeploymentOrder(t *testing.T) {
+	order, err := linkedOutput().LibraryDeploymentOrder("Vault")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"libs/Math.sol:Math", "libs/Math.sol:Strings"}
+	if !reflect.DeepEqual(order, expected) {
+		t.Fatalf("expected %v, got %v", expected, order)
+	}
+}
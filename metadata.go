@@ -0,0 +1,250 @@
+package gosolc
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrBuildMismatch is returned by RecompileMetadata when the rebuilt bytecode differs from the target.
+var ErrBuildMismatch = errors.New("rebuilt bytecode does not match the target")
+
+// ContractMetadata is the metadata.json solc emits for a contract (the "metadata" output), whose hash is
+// stored in the bytecode metadata trailer.
+type ContractMetadata struct {
+	Compiler struct {
+		Version string `json:"version"` // e.g. "0.8.29+commit.ab55807c"
+	} `json:"compiler"`
+	Language string                     `json:"language"`
+	Settings map[string]interface{}     `json:"settings"` // Compiler settings plus compilationTarget
+	Sources  map[string]*MetadataSource `json:"sources"`
+	Output   json.RawMessage            `json:"output,omitempty"` // ABI, devdoc and userdoc
+	Version  int                        `json:"version"`
+}
+
+// MetadataSource is a source listed in the metadata.
+type MetadataSource struct {
+	Keccak256 string   `json:"keccak256"`
+	License   string   `json:"license,omitempty"`
+	URLs      []string `json:"urls,omitempty"`
+	Content   *string  `json:"content,omitempty"` // Set with useLiteralContent
+}
+
+// ParseContractMetadata parses a metadata.json.
+func ParseContractMetadata(data []byte) (*ContractMetadata, error) {
+	var metadata ContractMetadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil, fmt.Errorf("invalid metadata: %v", err)
+	}
+	if metadata.Language != "Solidity" {
+		return nil, fmt.Errorf("unsupported metadata language %q", metadata.Language)
+	}
+	return &metadata, nil
+}
+
+// Target returns the fully qualified name of the contract the metadata was emitted for (compilationTarget).
+func (m *ContractMetadata) Target() (string, error) {
+	target, _ := m.Settings["compilationTarget"].(map[string]interface{})
+	if len(target) != 1 {
+		return "", fmt.Errorf("metadata has no single compilation target")
+	}
+	for file, name := range target {
+		if name, ok := name.(string); ok {
+			return file + ":" + name, nil
+		}
+	}
+	return "", fmt.Errorf("invalid compilation target")
+}
+
+// ResolveSources returns the contents of every source listed in the metadata, checked against their keccak256 hashes.
+// Contents embedded in the metadata are used first, then sources are looked up by source unit name and, failing
+// that, by hash, so that files stored under different paths (e.g. by Sourcify) are found too.
+func (m *ContractMetadata) ResolveSources(sources map[string]string) (map[string]string, error) {
+	byHash := make(map[string]string, len(sources))
+	for _, content := range sources {
+		byHash["0x"+hex.EncodeToString(keccak256([]byte(content)))] = content
+	}
+
+	resolved := make(map[string]string, len(m.Sources))
+	for name, source := range m.Sources {
+		content, ok := sources[name]
+		if source.Content != nil {
+			content, ok = *source.Content, true
+		}
+		if hash := "0x" + hex.EncodeToString(keccak256([]byte(content))); ok && hash != source.Keccak256 {
+			return nil, fmt.Errorf("source %s does not match the metadata (keccak256 %s, expected %s)", name, hash, source.Keccak256)
+		}
+		if !ok {
+			if content, ok = byHash[source.Keccak256]; !ok {
+				return nil, fmt.Errorf("source %s (keccak256 %s) not found", name, source.Keccak256)
+			}
+		}
+		resolved[name] = content
+	}
+	return resolved, nil
+}
+
+// StandardJSONInput rebuilds the standard JSON input the contract was compiled with: language, sources and the
+// settings recorded in the metadata (optimizer, evmVersion, viaIR, remappings, libraries, metadata settings).
+func (m *ContractMetadata) StandardJSONInput(sources map[string]string) ([]byte, error) {
+	contents, err := m.ResolveSources(sources)
+	if err != nil {
+		return nil, err
+	}
+
+	inputSources := make(map[string]map[string]string, len(contents))
+	for name, content := range contents {
+		inputSources[name] = map[string]string{"content": content}
+	}
+
+	settings := make(map[string]interface{}, len(m.Settings)+1)
+	for key, value := range m.Settings {
+		settings[key] = value
+	}
+	delete(settings, "compilationTarget")
+
+	// the metadata stores libraries as "file.sol:Lib" => address, the input keys them by file and name
+	if libraries, ok := settings["libraries"].(map[string]interface{}); ok {
+		inputLibraries := map[string]map[string]interface{}{}
+		for fqName, address := range libraries {
+			file, name := splitFullyQualifiedName(fqName)
+			if inputLibraries[file] == nil {
+				inputLibraries[file] = map[string]interface{}{}
+			}
+			inputLibraries[file][name] = address
+		}
+		settings["libraries"] = inputLibraries
+	}
+
+	settings["outputSelection"] = map[string]map[string][]string{
+		"*": {
+			"*": []string{
+				"abi",
+				"evm.bytecode.object",
+				"evm.bytecode.linkReferences",
+				"evm.deployedBytecode.object",
+				"evm.deployedBytecode.linkReferences",
+				"evm.deployedBytecode.immutableReferences",
+				"metadata",
+			},
+		},
+	}
+
+	return json.MarshalIndent(map[string]interface{}{
+		"language": m.Language,
+		"sources":  inputSources,
+		"settings": settings,
+	}, "", "  ")
+}
+
+// MetadataBuild is the result of recompiling a contract from its metadata.
+type MetadataBuild struct {
+	Contract        string              // Fully qualified name of the compilation target
+	CompilerVersion string              // Long version of the solc-js used
+	Input           []byte              // Standard JSON input rebuilt from the metadata
+	Output          CompilerOutput      // Contracts compiled from Input
+	Creation        bool                // The target is creation bytecode
+	ConstructorArgs []byte              // Data appended to the creation bytecode of the target
+	Verification    *VerificationResult // Comparison with the target
+}
+
+// RecompileMetadata rebuilds the standard JSON input from a metadata.json and the sources it lists, compiles it
+// with the registered solc-js of the version recorded in the metadata (see RegisterSolcJs) and compares the
+// result with the target bytecode (hex), either the creation bytecode of the deployment transaction, constructor
+// arguments included, or the runtime code of the deployed contract. It returns the build with an error wrapping
+// ErrBuildMismatch if the bytecode differs; a partial runtime match (different metadata) is a mismatch too.
+func RecompileMetadata(metadataJSON []byte, sources map[string]string, targetBytecode string) (*MetadataBuild, error) {
+	metadata, err := ParseContractMetadata(metadataJSON)
+	if err != nil {
+		return nil, err
+	}
+	fqName, err := metadata.Target()
+	if err != nil {
+		return nil, err
+	}
+	target, err := decodeHex(targetBytecode)
+	if err != nil {
+		return nil, fmt.Errorf("invalid target bytecode: %v", err)
+	}
+
+	version, err := parseSolcVersion(metadata.Compiler.Version)
+	if err != nil {
+		return nil, fmt.Errorf("invalid compiler version %q in metadata: %v", metadata.Compiler.Version, err)
+	}
+	solcJs, ok := solcJsForVersion(version.String())
+	if !ok {
+		return nil, fmt.Errorf("solc %s is not registered (registered: %s)", version, strings.Join(RegisteredSolcVersions(), ", "))
+	}
+	longVersion, err := solcLongVersion(solcJs)
+	if err != nil {
+		return nil, err
+	}
+	if etherscanCompilerVersion(longVersion) != metadata.Compiler.Version {
+		return nil, fmt.Errorf("registered solc %s is %s, the metadata requires %s", version, longVersion, metadata.Compiler.Version)
+	}
+
+	input, err := metadata.StandardJSONInput(sources)
+	if err != nil {
+		return nil, err
+	}
+	output, err := runSolc(solcJs, escapeCompilerInput(input))
+	if err != nil {
+		return nil, err
+	}
+	contracts, ok := output["contracts"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid contracts output")
+	}
+
+	build := &MetadataBuild{
+		Contract:        fqName,
+		CompilerVersion: longVersion,
+		Input:           input,
+		Output:          contracts,
+	}
+	contract, err := build.Output.Contract(fqName)
+	if err != nil {
+		return nil, err
+	}
+	bytecode, _, err := contractBytecodes(contract)
+	if err != nil {
+		return nil, err
+	}
+	creation, err := decodeBytecode(bytecode)
+	if err != nil {
+		return nil, err
+	}
+
+	// creation bytecode is at least as long as the compiled one, runtime code is always shorter
+	if len(creation) > 0 && len(target) >= len(creation) {
+		build.Creation = true
+		build.Verification = &VerificationResult{Status: FullMatch, FirstDifference: -1}
+		for i := range creation {
+			if creation[i] != target[i] {
+				build.Verification.Status = Mismatch
+				build.Verification.FirstDifference = i
+				return build, fmt.Errorf("%w: %s %v", ErrBuildMismatch, fqName, build.Verification)
+			}
+		}
+		build.ConstructorArgs = target[len(creation):]
+		return build, nil
+	}
+
+	build.Verification, err = build.Output.verifyDeployed(fqName, target)
+	if err != nil {
+		return nil, err
+	}
+	if build.Verification.Status != FullMatch {
+		return build, fmt.Errorf("%w: %s %v", ErrBuildMismatch, fqName, build.Verification)
+	}
+	return build, nil
+}
+
+// escapeCompilerInput escapes a standard JSON input for the single quoted string literal passed to solc.compile
+// by runSolc.
+func escapeCompilerInput(input []byte) string {
+	quoted, _ := json.Marshal(string(input))
+	return strings.ReplaceAll(string(quoted[1:len(quoted)-1]), `'`, `\'`)
+}
@@ -0,0 +1,142 @@
+package gosolc
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"rogchap.com/v8go"
+)
+
+func TestMetadataStandardJSONInput(t *testing.T) {
+	sources := map[string]string{
+		"contracts/Token.sol": "import \"./Lib.sol\";\ncontract Token {}\n",
+		"contracts/Lib.sol":   "library Lib {}\n",
+	}
+	hash := func(content string) string { return "0x" + hex.EncodeToString(keccak256([]byte(content))) }
+	metadataJSON := `{
+		"compiler": {"version": "0.8.29+commit.ab55807c"},
+		"language": "Solidity",
+		"settings": {
+			"compilationTarget": {"contracts/Token.sol": "Token"},
+			"evmVersion": "paris",
+			"libraries": {"contracts/Lib.sol:Lib": "0x0000000000000000000000000000000000000001"},
+			"metadata": {"bytecodeHash": "ipfs"},
+			"optimizer": {"enabled": true, "runs": 10000},
+			"remappings": ["@oz/=lib/oz/"],
+			"viaIR": true
+		},
+		"sources": {
+			"contracts/Token.sol": {"keccak256": "` + hash(sources["contracts/Token.sol"]) + `", "urls": []},
+			"contracts/Lib.sol": {"keccak256": "` + hash(sources["contracts/Lib.sol"]) + `", "urls": []}
+		},
+		"version": 1
+	}`
+
+	metadata, err := ParseContractMetadata([]byte(metadataJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if target, err := metadata.Target(); err != nil || target != "contracts/Token.sol:Token" {
+		t.Errorf("unexpected target %s (%v)", target, err)
+	}
+
+	// sources stored under other paths are found by hash
+	data, err := metadata.StandardJSONInput(map[string]string{
+		"Token.sol": sources["contracts/Token.sol"],
+		"Lib.sol":   sources["contracts/Lib.sol"],
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var input struct {
+		Sources  map[string]map[string]string `json:"sources"`
+		Settings map[string]interface{}       `json:"settings"`
+	}
+	if err := json.Unmarshal(data, &input); err != nil {
+		t.Fatal(err)
+	}
+	if input.Sources["contracts/Lib.sol"]["content"] != sources["contracts/Lib.sol"] || len(input.Sources) != 2 {
+		t.Errorf("unexpected sources %v", input.Sources)
+	}
+	if _, ok := input.Settings["compilationTarget"]; ok {
+		t.Error("compilationTarget is not a compiler setting")
+	}
+	libraries, _ := input.Settings["libraries"].(map[string]interface{})
+	if lib, _ := libraries["contracts/Lib.sol"].(map[string]interface{}); lib["Lib"] != "0x0000000000000000000000000000000000000001" {
+		t.Errorf("unexpected libraries %v", input.Settings["libraries"])
+	}
+	if input.Settings["viaIR"] != true || input.Settings["evmVersion"] != "paris" {
+		t.Errorf("unexpected settings %v", input.Settings)
+	}
+
+	if _, err := metadata.StandardJSONInput(map[string]string{"contracts/Token.sol": "contract Token {}"}); err == nil {
+		t.Error("expected an error for a modified source")
+	}
+}
+
+func TestEscapeCompilerInput(t *testing.T) {
+	input := `{"sources":{"a.sol":{"content":"string s = 'it\'s';\n// \"quoted\" \\  "}}}`
+
+	iso := v8go.NewIsolate()
+	defer iso.Dispose()
+	ctx := v8go.NewContext(iso)
+	defer ctx.Close()
+
+	value, err := ctx.RunScript(`'`+escapeCompilerInput([]byte(input))+`'`, "escape.js")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value.String() != input {
+		t.Errorf("expected %q, got %q", input, value.String())
+	}
+}
+
+func TestE2ERecompileMetadata(t *testing.T) {
+	if solcJS_0_8_29 == "" {
+		t.Skip("embedded soljson is not available")
+	}
+
+	contents, err := readContractsDir("testdata/contracts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewCompilerFromSources(contents, NewCompilerConfig("cancun", true, 200), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	compiled, err := c.Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	contract, _ := compiled.Contract("dummy_token.sol:Token")
+	bytecode, deployedBytecode, _ := contractBytecodes(contract)
+	metadataJSON := []byte(contract["metadata"].(string))
+
+	build, err := RecompileMetadata(metadataJSON, contents, deployedBytecode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if build.Creation || build.Verification.Status != FullMatch {
+		t.Errorf("expected a full runtime match, got %v", build.Verification)
+	}
+
+	build, err = RecompileMetadata(metadataJSON, contents, bytecode+"01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !build.Creation || hex.EncodeToString(build.ConstructorArgs) != "01" {
+		t.Errorf("expected a creation match with arguments, got %+v", build)
+	}
+
+	// a different deployment
+	tampered := strings.Replace(deployedBytecode, "6080604052", "6080604053", 1)
+	if _, err := RecompileMetadata(metadataJSON, contents, tampered); !errors.Is(err, ErrBuildMismatch) {
+		t.Errorf("expected ErrBuildMismatch, got %v", err)
+	}
+	if _, err := RecompileMetadata(metadataJSON, map[string]string{}, deployedBytecode); err == nil || errors.Is(err, ErrBuildMismatch) {
+		t.Errorf("expected a missing source error, got %v", err)
+	}
+}
@@ -0,0 +1,106 @@
+package gosolc
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// evmVersions lists the EVM versions supported by solc, from oldest to newest.
+var evmVersions = []string{
+	"homestead", "tangerineWhistle", "spuriousDragon", "byzantium", "constantinople", "petersburg",
+	"istanbul", "berlin", "london", "paris", "shanghai", "cancun", "prague", "osaka",
+}
+
+// evmVersionIndex returns the position of an EVM version in evmVersions. "frontier" is older than all of them.
+func evmVersionIndex(evmVersion string) (int, error) {
+	if evmVersion == "frontier" {
+		return -1, nil
+	}
+	for i, version := range evmVersions {
+		if version == evmVersion {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown EVM version %q, supported versions: frontier, %s", evmVersion, strings.Join(evmVersions, ", "))
+}
+
+// opcode describes an EVM opcode.
+type opcode struct {
+	name  string
+	since string // EVM version introducing the opcode, empty for frontier
+}
+
+// opcodes maps the defined opcodes to their names and the EVM version introducing them.
+var opcodes = map[byte]opcode{
+	0x00: {"STOP", ""}, 0x01: {"ADD", ""}, 0x02: {"MUL", ""}, 0x03: {"SUB", ""}, 0x04: {"DIV", ""},
+	0x05: {"SDIV", ""}, 0x06: {"MOD", ""}, 0x07: {"SMOD", ""}, 0x08: {"ADDMOD", ""}, 0x09: {"MULMOD", ""},
+	0x0a: {"EXP", ""}, 0x0b: {"SIGNEXTEND", ""},
+
+	0x10: {"LT", ""}, 0x11: {"GT", ""}, 0x12: {"SLT", ""}, 0x13: {"SGT", ""}, 0x14: {"EQ", ""},
+	0x15: {"ISZERO", ""}, 0x16: {"AND", ""}, 0x17: {"OR", ""}, 0x18: {"XOR", ""}, 0x19: {"NOT", ""},
+	0x1a: {"BYTE", ""}, 0x1b: {"SHL", "constantinople"}, 0x1c: {"SHR", "constantinople"},
+	0x1d: {"SAR", "constantinople"}, 0x1e: {"CLZ", "osaka"},
+
+	0x20: {"KECCAK256", ""},
+
+	0x30: {"ADDRESS", ""}, 0x31: {"BALANCE", ""}, 0x32: {"ORIGIN", ""}, 0x33: {"CALLER", ""},
+	0x34: {"CALLVALUE", ""}, 0x35: {"CALLDATALOAD", ""}, 0x36: {"CALLDATASIZE", ""}, 0x37: {"CALLDATACOPY", ""},
+	0x38: {"CODESIZE", ""}, 0x39: {"CODECOPY", ""}, 0x3a: {"GASPRICE", ""}, 0x3b: {"EXTCODESIZE", ""},
+	0x3c: {"EXTCODECOPY", ""}, 0x3d: {"RETURNDATASIZE", "byzantium"}, 0x3e: {"RETURNDATACOPY", "byzantium"},
+	0x3f: {"EXTCODEHASH", "constantinople"},
+
+	0x40: {"BLOCKHASH", ""}, 0x41: {"COINBASE", ""}, 0x42: {"TIMESTAMP", ""}, 0x43: {"NUMBER", ""},
+	0x44: {"PREVRANDAO", ""}, 0x45: {"GASLIMIT", ""}, 0x46: {"CHAINID", "istanbul"},
+	0x47: {"SELFBALANCE", "istanbul"}, 0x48: {"BASEFEE", "london"}, 0x49: {"BLOBHASH", "cancun"},
+	0x4a: {"BLOBBASEFEE", "cancun"},
+
+	0x50: {"POP", ""}, 0x51: {"MLOAD", ""}, 0x52: {"MSTORE", ""}, 0x53: {"MSTORE8", ""}, 0x54: {"SLOAD", ""},
+	0x55: {"SSTORE", ""}, 0x56: {"JUMP", ""}, 0x57: {"JUMPI", ""}, 0x58: {"PC", ""}, 0x59: {"MSIZE", ""},
+	0x5a: {"GAS", ""}, 0x5b: {"JUMPDEST", ""}, 0x5c: {"TLOAD", "cancun"}, 0x5d: {"TSTORE", "cancun"},
+	0x5e: {"MCOPY", "cancun"}, 0x5f: {"PUSH0", "shanghai"},
+
+	0xa0: {"LOG0", ""}, 0xa1: {"LOG1", ""}, 0xa2: {"LOG2", ""}, 0xa3: {"LOG3", ""}, 0xa4: {"LOG4", ""},
+
+	0xf0: {"CREATE", ""}, 0xf1: {"CALL", ""}, 0xf2: {"CALLCODE", ""}, 0xf3: {"RETURN", ""},
+	0xf4: {"DELEGATECALL", "homestead"}, 0xf5: {"CREATE2", "constantinople"}, 0xfa: {"STATICCALL", "byzantium"},
+	0xfd: {"REVERT", "byzantium"}, 0xfe: {"INVALID", ""}, 0xff: {"SELFDESTRUCT", ""},
+}
+
+func init() {
+	for i := 1; i <= 32; i++ {
+		opcodes[byte(0x5f+i)] = opcode{"PUSH" + strconv.Itoa(i), ""}
+	}
+	for i := 1; i <= 16; i++ {
+		opcodes[byte(0x7f+i)] = opcode{"DUP" + strconv.Itoa(i), ""}
+		opcodes[byte(0x8f+i)] = opcode{"SWAP" + strconv.Itoa(i), ""}
+	}
+}
+
+// OpcodeName returns the mnemonic of an opcode, or "UNDEFINED(0x..)" for undefined opcodes.
+func OpcodeName(op byte) string {
+	if o, ok := opcodes[op]; ok {
+		return o.name
+	}
+	return fmt.Sprintf("UNDEFINED(0x%02x)", op)
+}
+
+// OpcodeSupported reports whether an opcode is defined in an EVM version.
+func OpcodeSupported(op byte, evmVersion string) (bool, error) {
+	target, err := evmVersionIndex(evmVersion)
+	if err != nil {
+		return false, err
+	}
+	o, ok := opcodes[op]
+	if !ok {
+		return false, nil
+	}
+	if o.since == "" {
+		return true, nil
+	}
+	since, err := evmVersionIndex(o.since)
+	if err != nil {
+		return false, err
+	}
+	return since <= target, nil
+}
@@ -0,0 +1,166 @@
+package gosolc
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultRunsValues are the optimizer runs values tried by SearchOptimizerRuns by default, from optimizing for
+// deployment cost to optimizing for execution cost.
+var DefaultRunsValues = []uint{1, 10, 50, 100, 200, 500, 1000, 2000, 5000, 10000, 20000, 50000, 100000, 1000000}
+
+// RunsSearch configures SearchOptimizerRuns.
+type RunsSearch struct {
+	Contract       string // Fully qualified name of the target contract
+	Runs           []uint // Runs values to compile with, defaults to DefaultRunsValues
+	SizeBudget     int    // Maximum runtime size in bytes, defaults to the SizeLimits of the compiler or EIP-170
+	InitcodeBudget int    // Maximum initcode size in bytes, defaults to the SizeLimits of the compiler or EIP-3860
+	Parallelism    int    // Number of concurrent compilations, defaults to the number of CPUs
+}
+
+// RunsSample is the target contract compiled with a runs value.
+type RunsSample struct {
+	Runs         uint          `json:"runs"`
+	RuntimeSize  int           `json:"runtimeSize"`
+	InitcodeSize int           `json:"initcodeSize"`
+	FitsBudget   bool          `json:"fitsBudget"`
+	GasEstimates *GasEstimates `json:"gasEstimates"`
+}
+
+// RunsCurve is the size and gas trade-off of the target contract across runs values.
+type RunsCurve struct {
+	Contract       string        `json:"contract"`
+	SizeBudget     int           `json:"sizeBudget"`
+	InitcodeBudget int           `json:"initcodeBudget"`
+	Samples        []*RunsSample `json:"samples"`               // Ordered by runs
+	Recommended    *uint         `json:"recommended,omitempty"` // Largest runs value fitting the budgets, nil if none does
+}
+
+// SearchOptimizerRuns compiles the sources with the optimizer enabled for each runs value of the search, using
+// up to Parallelism compilers at a time, and records the runtime and initcode sizes and the gas estimates of the
+// target contract. It recommends the largest runs value whose sizes fit the budgets: the one producing the
+// cheapest calls that can still be deployed. Compiler overrides are not supported.
+func (c Compiler) SearchOptimizerRuns(search RunsSearch) (*RunsCurve, error) {
+	if len(c.CompilerConfig.Overrides) > 0 {
+		return nil, fmt.Errorf("SearchOptimizerRuns doesn't support compiler overrides")
+	}
+	if !strings.Contains(search.Contract, ":") {
+		return nil, fmt.Errorf("contract %q must be a fully qualified name (file.sol:Contract)", search.Contract)
+	}
+
+	limits := c.CompilerConfig.SizeLimits
+	if limits == nil {
+		limits, _ = SizeProfile("ethereum")
+	}
+	curve := &RunsCurve{Contract: search.Contract, SizeBudget: search.SizeBudget, InitcodeBudget: search.InitcodeBudget}
+	if curve.SizeBudget <= 0 {
+		curve.SizeBudget = limits.Runtime
+	}
+	if curve.InitcodeBudget <= 0 {
+		curve.InitcodeBudget = limits.Initcode
+	}
+
+	runs := search.Runs
+	if len(runs) == 0 {
+		runs = DefaultRunsValues
+	}
+	parallelism := search.Parallelism
+	if parallelism <= 0 {
+		parallelism = runtime.NumCPU()
+	}
+
+	samples := make([]*RunsSample, len(runs))
+	errs := make([]error, len(runs))
+	sem := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	for i, r := range runs {
+		wg.Add(1)
+		go func(i int, r uint) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			samples[i], errs[i] = c.compileRunsSample(search.Contract, r)
+		}(i, r)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile with %d runs: %w", runs[i], err)
+		}
+	}
+
+	curve.Samples = samples
+	curve.recommend()
+	return curve, nil
+}
+
+// recommend sorts the samples by runs, checks them against the budgets and sets the recommended runs value.
+func (c *RunsCurve) recommend() {
+	sort.Slice(c.Samples, func(i, j int) bool { return c.Samples[i].Runs < c.Samples[j].Runs })
+	c.Recommended = nil
+	for _, sample := range c.Samples {
+		sample.FitsBudget = (c.SizeBudget <= 0 || sample.RuntimeSize <= c.SizeBudget) &&
+			(c.InitcodeBudget <= 0 || sample.InitcodeSize <= c.InitcodeBudget)
+		if sample.FitsBudget {
+			runs := sample.Runs
+			c.Recommended = &runs
+		}
+	}
+}
+
+// compileRunsSample compiles the sources with the given optimizer runs, selecting only the outputs of the target
+// contract that the search records.
+func (c Compiler) compileRunsSample(fqName string, runs uint) (*RunsSample, error) {
+	config := *c.CompilerConfig
+	config.SolcOptimizer = &SolcOptimizerConfig{Enabled: true, Runs: int(runs)}
+	sub := Compiler{CompilerConfig: &config, Sources: c.Sources, SolcJs: c.SolcJs}
+
+	file, name := splitFullyQualifiedName(fqName)
+	input := sub.compilerInput(sub.Sources)
+	input["settings"].(map[string]interface{})["outputSelection"] = map[string]map[string][]string{
+		file: {name: []string{"evm.bytecode.object", "evm.deployedBytecode.object", "evm.gasEstimates"}},
+	}
+	inputJSON, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal compiler input JSON: %v", err)
+	}
+
+	output, err := runSolc(sub.SolcJs, strings.ReplaceAll(string(inputJSON), `'`, `\'`))
+	if err != nil {
+		return nil, err
+	}
+	contracts, ok := output["contracts"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid contracts output")
+	}
+	contract, err := CompilerOutput(contracts).Contract(fqName)
+	if err != nil {
+		return nil, err
+	}
+	gasEstimates, err := CompilerOutput(contracts).GasEstimates(fqName)
+	if err != nil {
+		return nil, err
+	}
+	bytecode, deployedBytecode, err := contractBytecodes(contract)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RunsSample{
+		Runs:         runs,
+		RuntimeSize:  len(deployedBytecode) / 2,
+		InitcodeSize: len(bytecode) / 2,
+		GasEstimates: gasEstimates,
+	}, nil
+}
+
+// JSON returns the curve as indented JSON.
+func (c *RunsCurve) JSON() ([]byte, error) {
+	return json.MarshalIndent(c, "", "  ")
+}
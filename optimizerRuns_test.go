@@ -0,0 +1,67 @@
+package gosolc
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestRunsCurveRecommend(t *testing.T) {
+	curve := &RunsCurve{SizeBudget: 1000, InitcodeBudget: 2000, Samples: []*RunsSample{
+		{Runs: 10000, RuntimeSize: 1100, InitcodeSize: 1500},
+		{Runs: 1, RuntimeSize: 800, InitcodeSize: 1000},
+		{Runs: 1000, RuntimeSize: 950, InitcodeSize: 1900},
+		{Runs: 200, RuntimeSize: 900, InitcodeSize: 2100}, // initcode over budget
+	}}
+	curve.recommend()
+
+	if curve.Recommended == nil || *curve.Recommended != 1000 {
+		t.Fatalf("expected 1000 runs, got %v", curve.Recommended)
+	}
+	for i, expected := range []bool{true, false, true, false} {
+		if curve.Samples[i].FitsBudget != expected {
+			t.Errorf("sample %d (%d runs): expected fits %v", i, curve.Samples[i].Runs, expected)
+		}
+	}
+
+	curve.SizeBudget = 500
+	curve.recommend()
+	if curve.Recommended != nil {
+		t.Errorf("expected no recommendation, got %d", *curve.Recommended)
+	}
+	data, err := curve.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := decoded["recommended"]; ok || len(decoded["samples"].([]interface{})) != 4 {
+		t.Errorf("unexpected JSON %s", data)
+	}
+}
+
+func TestE2ESearchOptimizerRuns(t *testing.T) {
+	if solcJS_0_8_29 == "" {
+		t.Skip("embedded soljson is not available")
+	}
+
+	c, err := NewCompiler("testdata/contracts", NewCompilerConfig("cancun", false, 0), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	curve, err := c.SearchOptimizerRuns(RunsSearch{Contract: "dummy_token.sol:Token", Runs: []uint{1, 200, 10000}, Parallelism: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(curve.Samples) != 3 || curve.Recommended == nil || *curve.Recommended != 10000 {
+		t.Errorf("unexpected curve %+v", curve)
+	}
+	if curve.Samples[0].RuntimeSize == 0 || curve.Samples[0].GasEstimates == nil {
+		t.Errorf("unexpected sample %+v", curve.Samples[0])
+	}
+
+	if _, err := c.SearchOptimizerRuns(RunsSearch{Contract: "Token"}); err == nil {
+		t.Error("expected an error for a bare contract name")
+	}
+}
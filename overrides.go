@@ -0,0 +1,190 @@
+package gosolc
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+)
+
+// CompilerOverride replaces the compiler configuration for the sources matching Path, like Hardhat `overrides`.
+// Settings left unset in the override are inherited from the base configuration, so ViaIR is a pointer: an
+// override only switches the pipeline when it sets it.
+// Path is either an exact source unit name (e.g. "Vault.sol") or a glob in path.Match syntax (e.g. "libs/*.sol").
+// Exact matches take precedence over globs; otherwise the first matching override wins.
+// SizeLimits can't be overridden: the limits of the base configuration apply to all contracts.
+type CompilerOverride struct {
+	Path            string `json:"path"` // Source unit name or glob the override applies to
+	*CompilerConfig        // Configuration used instead of the base configuration
+}
+
+// NewCompilerOverride creates a new CompilerOverride for the sources matching path.
+func NewCompilerOverride(path string, config *CompilerConfig) *CompilerOverride {
+	return &CompilerOverride{Path: path, CompilerConfig: config}
+}
+
+// overrideFor returns the index of the override applying to the source unit name, or -1 if none does.
+func (cfg *CompilerConfig) overrideFor(name string) (int, error) {
+	for i, o := range cfg.Overrides {
+		if o.Path == name {
+			return i, nil
+		}
+	}
+	for i, o := range cfg.Overrides {
+		matched, err := path.Match(o.Path, name)
+		if err != nil {
+			return -1, fmt.Errorf("invalid override path %q: %v", o.Path, err)
+		}
+		if matched {
+			return i, nil
+		}
+	}
+	return -1, nil
+}
+
+// sourceConfig returns the configuration used to compile roots of the given override index.
+// Settings an override leaves unset (empty EVMVersion, nil optimizer, via-IR, metadata or libraries) are
+// inherited from the base configuration. The returned configuration never has overrides or size limits itself:
+// the limits of the base configuration are checked once on the merged output.
+func (cfg *CompilerConfig) sourceConfig(override int) *CompilerConfig {
+	if override >= 0 {
+		o := *cfg.Overrides[override].CompilerConfig
+		o.Overrides = nil
+		if o.EVMVersion == "" {
+			o.EVMVersion = cfg.EVMVersion
+		}
+		if o.SolcOptimizer == nil {
+			o.SolcOptimizer = cfg.SolcOptimizer
+		}
+		if o.ViaIR == nil {
+			o.ViaIR = cfg.ViaIR
+		}
+		if o.Metadata == nil {
+			o.Metadata = cfg.Metadata
+		}
+		if o.Libraries == nil {
+			o.Libraries = cfg.Libraries
+		}
+		o.SizeLimits = nil
+		return &o
+	}
+	base := *cfg
+	base.Overrides = nil
+	base.SizeLimits = nil
+	return &base
+}
+
+// sourceGroup is a set of root sources compiled with the same configuration, plus their import closures.
+type sourceGroup struct {
+	override int      // Index of the override used, -1 for the base configuration
+	roots    []string // Sources whose contracts are emitted by the group
+	sources  []string // Roots plus their import closures
+}
+
+// groupSources splits roots into groups that share a configuration, so that every group can be
+// compiled by a single solc invocation.
+func (cfg *CompilerConfig) groupSources(roots []string, infos map[string]*sourceInfo) ([]*sourceGroup, error) {
+	for _, o := range cfg.Overrides {
+		if o.SizeLimits != nil {
+			return nil, fmt.Errorf("override %q sets size limits, which only apply to the base configuration", o.Path)
+		}
+	}
+
+	groups := map[int]*sourceGroup{}
+	groupSources := map[int]map[string]bool{}
+	for _, root := range roots {
+		override, err := cfg.overrideFor(root)
+		if err != nil {
+			return nil, err
+		}
+
+		closure, err := importClosure(root, infos)
+		if err != nil {
+			return nil, err
+		}
+
+		group, ok := groups[override]
+		if !ok {
+			group = &sourceGroup{override: override}
+			groups[override] = group
+			groupSources[override] = map[string]bool{}
+		}
+		group.roots = append(group.roots, root)
+		for _, name := range closure {
+			groupSources[override][name] = true
+		}
+	}
+
+	result := make([]*sourceGroup, 0, len(groups))
+	for override, group := range groups {
+		for name := range groupSources[override] {
+			group.sources = append(group.sources, name)
+		}
+		sort.Strings(group.roots)
+		sort.Strings(group.sources)
+		result = append(result, group)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].override < result[j].override })
+
+	return result, nil
+}
+
+// compileWithOverrides compiles every group of sources sharing a configuration with its own solc
+// invocation and merges the contracts of their roots into a single output.
+func (c Compiler) compileWithOverrides() (CompilerOutput, error) {
+	contents, err := sourceContents(c.Sources)
+	if err != nil {
+		return nil, err
+	}
+
+	infos, err := analyzeSources(contents)
+	if err != nil {
+		return nil, err
+	}
+
+	roots := make([]string, 0, len(contents))
+	for name := range contents {
+		roots = append(roots, name)
+	}
+
+	groups, err := c.CompilerConfig.groupSources(roots, infos)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := CompilerOutput{}
+	for _, group := range groups {
+		sub := Compiler{
+			CompilerConfig: c.CompilerConfig.sourceConfig(group.override),
+			Sources:        map[string]map[string]string{},
+			SolcJs:         c.SolcJs,
+		}
+		for _, name := range group.sources {
+			sub.Sources[name] = c.Sources[name]
+		}
+		sub.CompilerInput, err = sub.getInputJSON()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get input JSON: %v", err)
+		}
+
+		output, err := sub.compile()
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile %s: %w", group.describe(c.CompilerConfig), err)
+		}
+		for _, root := range group.roots {
+			if fileContracts, ok := output[root]; ok {
+				merged[root] = fileContracts
+			}
+		}
+	}
+
+	return merged, nil
+}
+
+// describe returns a short description of the configuration used by the group, for error messages.
+func (g *sourceGroup) describe(cfg *CompilerConfig) string {
+	if g.override < 0 {
+		return "sources with the base configuration"
+	}
+	return "sources matching override " + strconv.Quote(cfg.Overrides[g.override].Path)
+}
@@ -0,0 +1,93 @@
+package gosolc
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestOverrideInheritsBaseConfig(t *testing.T) {
+	base := NewCompilerConfig("cancun", true, 200)
+	base.Metadata = &SolcMetadataConfig{BytecodeHash: "none"}
+	base.Libraries = map[string]map[string]string{"libs/Math.sol": {"Math": "0x5FbDB2315678afecb367f032d93F642f64180aa3"}}
+	base.SizeLimits = &SizeLimits{Runtime: EIP170RuntimeSizeLimit}
+	viaIR := true
+	base.Overrides = []*CompilerOverride{
+		NewCompilerOverride("Vault.sol", &CompilerConfig{ViaIR: &viaIR}),
+		NewCompilerOverride("Legacy.sol", NewCompilerConfig("london", false, 0)),
+	}
+
+	cfg := base.sourceConfig(0)
+	if cfg.EVMVersion != "cancun" || cfg.SolcOptimizer != base.SolcOptimizer || !*cfg.ViaIR || cfg.Overrides != nil {
+		t.Fatalf("unexpected override configuration: %+v", cfg)
+	}
+	if cfg.Metadata != base.Metadata || !reflect.DeepEqual(cfg.Libraries, base.Libraries) {
+		t.Fatalf("override dropped base settings: %+v", cfg)
+	}
+	// size limits are checked once on the merged output, not by the group compilations
+	if cfg.SizeLimits != nil || base.sourceConfig(-1).SizeLimits != nil {
+		t.Fatalf("unexpected size limits in group configuration: %+v", cfg)
+	}
+
+	cfg = base.sourceConfig(1)
+	if cfg.EVMVersion != "london" || cfg.SolcOptimizer.Enabled || cfg.ViaIR != nil || cfg.Libraries == nil {
+		t.Fatalf("unexpected override configuration: %+v", cfg)
+	}
+
+	c := Compiler{CompilerConfig: base.sourceConfig(0), Sources: map[string]map[string]string{"Vault.sol": {"content": "contract Vault {}"}}}
+	input, err := c.getInputJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var parsed struct {
+		Settings map[string]interface{} `json:"settings"`
+	}
+	if err := json.Unmarshal([]byte(input), &parsed); err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Settings["evmVersion"] != "cancun" || parsed.Settings["libraries"] == nil || parsed.Settings["metadata"] == nil {
+		t.Errorf("unexpected settings %v", parsed.Settings)
+	}
+}
+
+func TestOverrideSizeLimitsRejected(t *testing.T) {
+	base := NewCompilerConfig("cancun", true, 200)
+	base.Overrides = []*CompilerOverride{
+		NewCompilerOverride("Vault.sol", &CompilerConfig{SizeLimits: &SizeLimits{Runtime: 1}}),
+	}
+	infos := map[string]*sourceInfo{"Vault.sol": {}}
+	if _, err := base.groupSources([]string{"Vault.sol"}, infos); err == nil || !strings.Contains(err.Error(), `override "Vault.sol" sets size limits`) {
+		t.Errorf("expected an error for size limits on an override, got %v", err)
+	}
+}
+
+func TestOverrideInheritsViaIR(t *testing.T) {
+	viaIR, legacy := true, false
+	base := NewCompilerConfig("cancun", true, 200)
+	base.ViaIR = &viaIR
+	base.Overrides = []*CompilerOverride{
+		NewCompilerOverride("Vault.sol", &CompilerConfig{EVMVersion: "paris"}),
+		NewCompilerOverride("Legacy.sol", &CompilerConfig{ViaIR: &legacy}),
+	}
+
+	// an override that only changes the EVM version stays on the IR pipeline
+	c := Compiler{CompilerConfig: base.sourceConfig(0), Sources: map[string]map[string]string{"Vault.sol": {"content": "contract Vault {}"}}}
+	input, err := c.getInputJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var parsed struct {
+		Settings map[string]interface{} `json:"settings"`
+	}
+	if err := json.Unmarshal([]byte(input), &parsed); err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Settings["viaIR"] != true || parsed.Settings["evmVersion"] != "paris" {
+		t.Errorf("unexpected settings %v", parsed.Settings)
+	}
+
+	if cfg := base.sourceConfig(1); *cfg.ViaIR {
+		t.Error("expected the override to switch back to the legacy pipeline")
+	}
+}
@@ -0,0 +1,277 @@
+package gosolc
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// solcVersion is a solc release version (major.minor.patch).
+type solcVersion struct {
+	major, minor, patch int
+}
+
+// parseSolcVersion parses versions such as "0.8.29", "v0.8.29" or "0.8.29+commit.ab55807c".
+func parseSolcVersion(s string) (solcVersion, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.IndexAny(s, "+-"); i >= 0 {
+		s = s[:i]
+	}
+
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 {
+		return solcVersion{}, fmt.Errorf("expected major.minor.patch, got %q", s)
+	}
+
+	var nums [3]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return solcVersion{}, fmt.Errorf("invalid version component %q", part)
+		}
+		nums[i] = n
+	}
+
+	return solcVersion{major: nums[0], minor: nums[1], patch: nums[2]}, nil
+}
+
+// String returns the version in major.minor.patch form.
+func (v solcVersion) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.major, v.minor, v.patch)
+}
+
+// compare returns -1, 0 or 1 if v is lower than, equal to or greater than o.
+func (v solcVersion) compare(o solcVersion) int {
+	switch {
+	case v.major != o.major:
+		return cmpInt(v.major, o.major)
+	case v.minor != o.minor:
+		return cmpInt(v.minor, o.minor)
+	default:
+		return cmpInt(v.patch, o.patch)
+	}
+}
+
+func cmpInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// versionComparator is a single bound of a version range, e.g. ">=0.8.0".
+type versionComparator struct {
+	op      string
+	version solcVersion
+}
+
+func (c versionComparator) matches(v solcVersion) bool {
+	cmp := v.compare(c.version)
+	switch c.op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	default:
+		return cmp == 0
+	}
+}
+
+// versionRange is a pragma version expression: a union ("||") of comparator sets that must all match.
+// The zero value matches every version.
+type versionRange [][]versionComparator
+
+// matches reports whether v satisfies the range.
+func (r versionRange) matches(v solcVersion) bool {
+	if len(r) == 0 {
+		return true
+	}
+	for _, set := range r {
+		ok := true
+		for _, c := range set {
+			if !c.matches(v) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// intersect returns a range that matches only the versions matched by both r and o.
+func (r versionRange) intersect(o versionRange) versionRange {
+	if len(r) == 0 {
+		return o
+	}
+	if len(o) == 0 {
+		return r
+	}
+
+	var result versionRange
+	for _, a := range r {
+		for _, b := range o {
+			set := make([]versionComparator, 0, len(a)+len(b))
+			set = append(set, a...)
+			set = append(set, b...)
+			result = append(result, set)
+		}
+	}
+	return result
+}
+
+// versionTermRegexp matches a single term of a pragma expression, e.g. "^0.8.0", ">= 0.7" or "0.8.x".
+var versionTermRegexp = regexp.MustCompile(`^(\^|~|>=|<=|>|<|=)?\s*v?((?:\d+|[xX*])(?:\.(?:\d+|[xX*])){0,2})`)
+
+// parseVersionRange parses the version expression of a `pragma solidity` directive,
+// following the npm semver rules used by solc.
+func parseVersionRange(expr string) (versionRange, error) {
+	var r versionRange
+	for _, alternative := range strings.Split(expr, "||") {
+		set, err := parseComparatorSet(strings.TrimSpace(alternative))
+		if err != nil {
+			return nil, fmt.Errorf("invalid version expression %q: %v", expr, err)
+		}
+		r = append(r, set)
+	}
+	return r, nil
+}
+
+// parseComparatorSet parses whitespace separated terms (and hyphen ranges) that must all match.
+func parseComparatorSet(s string) ([]versionComparator, error) {
+	if parts := strings.SplitN(s, " - ", 2); len(parts) == 2 {
+		lower, err := parsePartialVersion(strings.TrimSpace(parts[0]))
+		if err != nil {
+			return nil, err
+		}
+		upper, err := parsePartialVersion(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, err
+		}
+		return append(expandComparator(">=", lower), expandComparator("<=", upper)...), nil
+	}
+
+	set := []versionComparator{}
+	for s = strings.TrimSpace(s); s != ""; s = strings.TrimSpace(s) {
+		m := versionTermRegexp.FindStringSubmatch(s)
+		if m == nil {
+			return nil, fmt.Errorf("unexpected %q", s)
+		}
+		s = s[len(m[0]):]
+
+		partial, err := parsePartialVersion(m[2])
+		if err != nil {
+			return nil, err
+		}
+		set = append(set, expandComparator(m[1], partial)...)
+	}
+	return set, nil
+}
+
+// partialVersion is a version whose trailing components may be omitted or wildcards (-1).
+type partialVersion [3]int
+
+func parsePartialVersion(s string) (partialVersion, error) {
+	p := partialVersion{-1, -1, -1}
+	for i, part := range strings.Split(strings.TrimPrefix(s, "v"), ".") {
+		if i > 2 {
+			return p, fmt.Errorf("too many version components in %q", s)
+		}
+		if part == "x" || part == "X" || part == "*" {
+			break
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return p, fmt.Errorf("invalid version component %q", part)
+		}
+		p[i] = n
+	}
+	return p, nil
+}
+
+// specified returns the number of leading components that are set.
+func (p partialVersion) specified() int {
+	for i, n := range p {
+		if n < 0 {
+			return i
+		}
+	}
+	return 3
+}
+
+// floor returns the lowest version matched by p.
+func (p partialVersion) floor() solcVersion {
+	v := [3]int{}
+	for i := 0; i < p.specified(); i++ {
+		v[i] = p[i]
+	}
+	return solcVersion{major: v[0], minor: v[1], patch: v[2]}
+}
+
+// next returns the lowest version above every version matched by p.
+func (p partialVersion) next() solcVersion {
+	switch p.specified() {
+	case 1:
+		return solcVersion{major: p[0] + 1}
+	case 2:
+		return solcVersion{major: p[0], minor: p[1] + 1}
+	default:
+		return solcVersion{major: p[0], minor: p[1], patch: p[2] + 1}
+	}
+}
+
+// expandComparator converts an operator applied to a partial version into plain comparators.
+func expandComparator(op string, p partialVersion) []versionComparator {
+	n := p.specified()
+	if n == 0 {
+		// "*" or "x" matches everything
+		return nil
+	}
+
+	switch op {
+	case "^":
+		upper := p.next()
+		switch {
+		case p[0] > 0 || n == 1:
+			upper = solcVersion{major: p[0] + 1}
+		case p[1] > 0 || n == 2:
+			upper = solcVersion{minor: p[1] + 1}
+		}
+		return []versionComparator{{">=", p.floor()}, {"<", upper}}
+	case "~":
+		upper := solcVersion{major: p[0] + 1}
+		if n > 1 {
+			upper = solcVersion{major: p[0], minor: p[1] + 1}
+		}
+		return []versionComparator{{">=", p.floor()}, {"<", upper}}
+	case ">=":
+		return []versionComparator{{">=", p.floor()}}
+	case ">":
+		if n < 3 {
+			return []versionComparator{{">=", p.next()}}
+		}
+		return []versionComparator{{">", p.floor()}}
+	case "<":
+		return []versionComparator{{"<", p.floor()}}
+	case "<=":
+		if n < 3 {
+			return []versionComparator{{"<", p.next()}}
+		}
+		return []versionComparator{{"<=", p.floor()}}
+	default:
+		if n < 3 {
+			return []versionComparator{{">=", p.floor()}, {"<", p.next()}}
+		}
+		return []versionComparator{{"=", p.floor()}}
+	}
+}
@@ -0,0 +1,235 @@
+package gosolc
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// Project compiles every Solidity file in a contracts directory, like a Hardhat project.
+// Sources are grouped into compilation units by their pragma version ranges and import closures,
+// so files pinned to 0.7.6 and files requiring ^0.8.20 build side by side. Each unit is compiled
+// with the highest registered solc-js version (see RegisterSolcJs) that satisfies all of its pragmas.
+type Project struct {
+	ContractsDir string            // Directory containing the Solidity contracts
+	Config       *CompilerConfig   // Compiler configuration shared by all units
+	Parallelism  int               // Maximum number of units compiled concurrently (defaults to the number of CPUs)
+	Contents     map[string]string // Raw source contents keyed by source unit name
+}
+
+// CompilationUnit is a set of sources compiled together by a single solc invocation.
+type CompilationUnit struct {
+	Version  string   `json:"version"`            // solc version used for the unit
+	Roots    []string `json:"roots"`              // Sources whose contracts are emitted by the unit
+	Sources  []string `json:"sources"`            // Roots plus their import closures
+	Override string   `json:"override,omitempty"` // Path of the CompilerOverride used by the unit, if any
+
+	override int // Index of the override used, -1 for the base configuration
+}
+
+// ProjectOutput is the merged output of all compilation units of a project.
+type ProjectOutput struct {
+	Units            []*CompilationUnit     `json:"units"`            // Compilation units that were compiled
+	Contracts        map[string]interface{} `json:"contracts"`        // Contract outputs keyed by fully qualified name (file.sol:Name)
+	CompilerVersions map[string]string      `json:"compilerVersions"` // solc version keyed by fully qualified name
+}
+
+// NewProject creates a new Project for the specified contracts directory and configuration.
+// config: The compiler configuration ( generated from gosolc.NewCompilerConfig() ). If nil, the default configuration is used
+func NewProject(contractsDir string, config *CompilerConfig) (*Project, error) {
+	if config == nil {
+		config = defaultConfig
+	}
+
+	contents, err := readContractsDir(contractsDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read contracts directory: %v", err)
+	}
+
+	return &Project{
+		ContractsDir: contractsDir,
+		Config:       config,
+		Contents:     contents,
+	}, nil
+}
+
+// CompilationUnits plans the compilation units of the project without compiling them.
+// Every source is compiled as a root exactly once, with the highest registered version
+// satisfying the pragmas of its whole import closure. Sources matching a CompilerOverride
+// are split into their own units.
+func (p *Project) CompilationUnits() ([]*CompilationUnit, error) {
+	infos, err := analyzeSources(p.Contents)
+	if err != nil {
+		return nil, err
+	}
+
+	var available []solcVersion
+	for _, version := range RegisteredSolcVersions() {
+		v, _ := parseSolcVersion(version)
+		available = append(available, v)
+	}
+
+	versionRoots := map[solcVersion][]string{}
+	for name := range infos {
+		closure, err := importClosure(name, infos)
+		if err != nil {
+			return nil, err
+		}
+
+		var r versionRange
+		for _, dep := range closure {
+			r = r.intersect(infos[dep].pragma)
+		}
+
+		found := false
+		for i := len(available) - 1; i >= 0; i-- {
+			if r.matches(available[i]) {
+				versionRoots[available[i]] = append(versionRoots[available[i]], name)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("no registered solc version satisfies the pragmas of %s and its imports (registered: %s)",
+				name, strings.Join(RegisteredSolcVersions(), ", "))
+		}
+	}
+
+	var units []*CompilationUnit
+	for _, version := range available {
+		roots, ok := versionRoots[version]
+		if !ok {
+			continue
+		}
+
+		groups, err := p.Config.groupSources(roots, infos)
+		if err != nil {
+			return nil, err
+		}
+		for _, group := range groups {
+			unit := &CompilationUnit{
+				Version:  version.String(),
+				Roots:    group.roots,
+				Sources:  group.sources,
+				override: group.override,
+			}
+			if group.override >= 0 {
+				unit.Override = p.Config.Overrides[group.override].Path
+			}
+			units = append(units, unit)
+		}
+	}
+
+	return units, nil
+}
+
+// Build compiles all compilation units of the project, in parallel, and merges their outputs.
+// With SizeLimits, contracts over the limits are reported by an error wrapping ErrSizeLimitExceeded, returned
+// with the output.
+func (p *Project) Build() (*ProjectOutput, error) {
+	units, err := p.CompilationUnits()
+	if err != nil {
+		return nil, err
+	}
+
+	parallelism := p.Parallelism
+	if parallelism <= 0 {
+		parallelism = runtime.NumCPU()
+	}
+
+	outputs := make([]CompilerOutput, len(units))
+	errs := make([]error, len(units))
+	sem := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	for i, unit := range units {
+		wg.Add(1)
+		go func(i int, unit *CompilationUnit) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			outputs[i], errs[i] = p.compileUnit(unit)
+		}(i, unit)
+	}
+	wg.Wait()
+
+	out := &ProjectOutput{
+		Units:            units,
+		Contracts:        map[string]interface{}{},
+		CompilerVersions: map[string]string{},
+	}
+	for i, unit := range units {
+		if errs[i] != nil {
+			return nil, fmt.Errorf("failed to compile unit with solc %s: %w", unit.Version, errs[i])
+		}
+		for _, root := range unit.Roots {
+			fileContracts, _ := outputs[i][root].(map[string]interface{})
+			for name, contract := range fileContracts {
+				fqName := root + ":" + name
+				out.Contracts[fqName] = contract
+				out.CompilerVersions[fqName] = unit.Version
+			}
+		}
+	}
+
+	if p.Config.SizeLimits != nil {
+		return out, out.CompilerOutput().checkSizeLimits(p.Config.SizeLimits)
+	}
+	return out, nil
+}
+
+// compileUnit compiles the sources of a unit with the solc-js registered for its version.
+func (p *Project) compileUnit(unit *CompilationUnit) (CompilerOutput, error) {
+	solcJs, ok := solcJsForVersion(unit.Version)
+	if !ok {
+		return nil, fmt.Errorf("solc %s is not registered", unit.Version)
+	}
+
+	contents := make(map[string]string, len(unit.Sources))
+	for _, name := range unit.Sources {
+		contents[name] = p.Contents[name]
+	}
+
+	sources, err := sourcesMap(contents)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Compiler{
+		CompilerConfig: p.Config.sourceConfig(unit.override),
+		Sources:        sources,
+		SolcJs:         solcJs,
+	}
+	c.CompilerInput, err = c.getInputJSON()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get input JSON: %v", err)
+	}
+
+	return c.compile()
+}
+
+// CompilerOutput returns the merged contracts in the file keyed CompilerOutput format, so that
+// helpers such as GetContractByteCodes can be used on a project build.
+func (out *ProjectOutput) CompilerOutput() CompilerOutput {
+	contracts := CompilerOutput{}
+	for fqName, contract := range out.Contracts {
+		file, name := splitFullyQualifiedName(fqName)
+		fileContracts, ok := contracts[file].(map[string]interface{})
+		if !ok {
+			fileContracts = map[string]interface{}{}
+			contracts[file] = fileContracts
+		}
+		fileContracts[name] = contract
+	}
+	return contracts
+}
+
+// splitFullyQualifiedName splits "path/file.sol:Name" into its source unit name and contract name.
+func splitFullyQualifiedName(fqName string) (string, string) {
+	i := strings.LastIndex(fqName, ":")
+	if i < 0 {
+		return "", fqName
+	}
+	return fqName[:i], fqName[i+1:]
+}
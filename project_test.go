@@ -0,0 +1,198 @@
+package gosolc
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestVersionRange(t *testing.T) {
+	tests := []struct {
+		expr    string
+		matches []string
+		rejects []string
+	}{
+		{"^0.8.0", []string{"0.8.0", "0.8.29"}, []string{"0.7.6", "0.9.0"}},
+		{"^0.0.3", []string{"0.0.3"}, []string{"0.0.4"}},
+		{"~0.8.1", []string{"0.8.1", "0.8.29"}, []string{"0.8.0", "0.9.0"}},
+		{"0.7.6", []string{"0.7.6"}, []string{"0.7.5", "0.7.7"}},
+		{">=0.6.0 <0.8.0", []string{"0.6.0", "0.7.6"}, []string{"0.5.17", "0.8.0"}},
+		{">0.7", []string{"0.8.0"}, []string{"0.7.6"}},
+		{"<=0.7", []string{"0.7.6"}, []string{"0.8.0"}},
+		{"0.8.x", []string{"0.8.0", "0.8.29"}, []string{"0.7.6"}},
+		{"0.6.0 - 0.7.6", []string{"0.6.12", "0.7.6"}, []string{"0.7.7"}},
+		{"^0.7.0 || ^0.8.20", []string{"0.7.6", "0.8.20"}, []string{"0.8.19"}},
+		{">= 0.8.0", []string{"0.8.0"}, []string{"0.7.6"}},
+	}
+
+	for _, tt := range tests {
+		r, err := parseVersionRange(tt.expr)
+		if err != nil {
+			t.Fatalf("parseVersionRange(%q): %v", tt.expr, err)
+		}
+		for _, v := range tt.matches {
+			if !r.matches(mustParseSolcVersion(t, v)) {
+				t.Errorf("%q should match %s", tt.expr, v)
+			}
+		}
+		for _, v := range tt.rejects {
+			if r.matches(mustParseSolcVersion(t, v)) {
+				t.Errorf("%q should not match %s", tt.expr, v)
+			}
+		}
+	}
+}
+
+func TestCompilationUnits(t *testing.T) {
+	solcJsRegistryMu.Lock()
+	solcJsRegistry["0.7.6"] = ""
+	solcJsRegistryMu.Unlock()
+	defer func() {
+		solcJsRegistryMu.Lock()
+		delete(solcJsRegistry, "0.7.6")
+		solcJsRegistryMu.Unlock()
+	}()
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"Legacy.sol":   "pragma solidity 0.7.6;\nimport \"./Math.sol\";\ncontract Legacy {}",
+		"Math.sol":     "// pragma solidity ^0.8.0;\npragma solidity >=0.7.0 <0.9.0;\nlibrary Math {}",
+		"Modern.sol":   "pragma solidity ^0.8.20;\nimport {Math} from './Math.sol';\ncontract Modern {}",
+		"Unpinned.sol": "contract Unpinned {}",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	p, err := NewProject(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	units, err := p.CompilationUnits()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []*CompilationUnit{
+		{Version: "0.7.6", Roots: []string{"Legacy.sol"}, Sources: []string{"Legacy.sol", "Math.sol"}, override: -1},
+		{Version: "0.8.29", Roots: []string{"Math.sol", "Modern.sol", "Unpinned.sol"}, Sources: []string{"Math.sol", "Modern.sol", "Unpinned.sol"}, override: -1},
+	}
+	if !reflect.DeepEqual(units, expected) {
+		t.Fatalf("unexpected units: %+v %+v", *units[0], *units[1])
+	}
+
+	p.Config = NewCompilerConfig("cancun", true, 200)
+	viaIR := true
+	p.Config.Overrides = []*CompilerOverride{
+		NewCompilerOverride("M*.sol", NewCompilerConfig("cancun", true, 1000000)),
+		NewCompilerOverride("Modern.sol", &CompilerConfig{EVMVersion: "cancun", ViaIR: &viaIR}),
+	}
+	units, err = p.CompilationUnits()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected = []*CompilationUnit{
+		{Version: "0.7.6", Roots: []string{"Legacy.sol"}, Sources: []string{"Legacy.sol", "Math.sol"}, override: -1},
+		{Version: "0.8.29", Roots: []string{"Unpinned.sol"}, Sources: []string{"Unpinned.sol"}, override: -1},
+		{Version: "0.8.29", Roots: []string{"Math.sol"}, Sources: []string{"Math.sol"}, Override: "M*.sol", override: 0},
+		{Version: "0.8.29", Roots: []string{"Modern.sol"}, Sources: []string{"Math.sol", "Modern.sol"}, Override: "Modern.sol", override: 1},
+	}
+	if !reflect.DeepEqual(units, expected) {
+		t.Fatalf("unexpected units with overrides: %+v", units)
+	}
+	if cfg := p.Config.sourceConfig(1); !*cfg.ViaIR || cfg.Overrides != nil {
+		t.Fatalf("unexpected override configuration: %+v", cfg)
+	}
+
+	p.Contents["Broken.sol"] = "pragma solidity ^0.6.0;\ncontract Broken {}"
+	if _, err := p.CompilationUnits(); err == nil {
+		t.Fatal("expected an error for an unsatisfiable pragma")
+	}
+}
+
+// stubSolcJs is a solc-js stand-in whose contracts, named after their source files, have one byte of code per
+// character of their source.
+const stubSolcJs = `
+Module._solidity_compile = function() {};
+Module.cwrap = function() {
+	return function(input) {
+		var sources = JSON.parse(input).sources, contracts = {};
+		for (var name in sources) {
+			var code = new Array(sources[name].content.length + 1).join('00');
+			var evm = {bytecode: {object: code, linkReferences: {}}, deployedBytecode: {object: code}};
+			contracts[name] = {};
+			contracts[name][name.replace('.sol', '')] = {abi: [], evm: evm};
+		}
+		return JSON.stringify({contracts: contracts});
+	};
+};
+`
+
+func TestProjectBuildSizeLimits(t *testing.T) {
+	solcJsRegistryMu.Lock()
+	solcJsRegistry["0.7.6"] = stubSolcJs
+	solcJsRegistryMu.Unlock()
+	defer func() {
+		solcJsRegistryMu.Lock()
+		delete(solcJsRegistry, "0.7.6")
+		solcJsRegistryMu.Unlock()
+	}()
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"Big.sol":   "pragma solidity 0.7.6;\ncontract Big {}\n" + strings.Repeat("// padding\n", 10),
+		"Small.sol": "pragma solidity 0.7.6;\ncontract Small {}",
+		"Tuned.sol": "pragma solidity 0.7.6;\ncontract Tuned {}\n" + strings.Repeat("// padding\n", 10),
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	p, err := NewProject(dir, NewCompilerConfig("istanbul", true, 200))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Config.SizeLimits = &SizeLimits{Runtime: 100}
+	// the override has no limits of its own: the merged output is checked against the base ones
+	viaIR := true
+	p.Config.Overrides = []*CompilerOverride{
+		NewCompilerOverride("Tuned.sol", &CompilerConfig{EVMVersion: "istanbul", ViaIR: &viaIR}),
+	}
+
+	out, err := p.Build()
+	if !errors.Is(err, ErrSizeLimitExceeded) {
+		t.Fatalf("expected a size limit error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "Big.sol:Big") || !strings.Contains(err.Error(), "Tuned.sol:Tuned") || strings.Contains(err.Error(), "Small.sol:Small") {
+		t.Errorf("unexpected contracts over the limits: %v", err)
+	}
+	if strings.Count(err.Error(), "Big.sol:Big") != 1 {
+		t.Errorf("expected the contracts to be reported once: %v", err)
+	}
+	if out == nil || len(out.Units) != 2 || len(out.Contracts) != 3 {
+		t.Fatalf("expected the output with the error, got %+v", out)
+	}
+
+	p.Config.SizeLimits.Runtime = 1000
+	if _, err := p.Build(); err != nil {
+		t.Fatalf("unexpected error below the limits: %v", err)
+	}
+}
+
+func mustParseSolcVersion(t *testing.T, s string) solcVersion {
+	t.Helper()
+	v, err := parseSolcVersion(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
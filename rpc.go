@@ -0,0 +1,105 @@
+package gosolc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+)
+
+// RPCClient is a minimal Ethereum JSON-RPC client over HTTP.
+type RPCClient struct {
+	URL        string
+	HTTPClient *http.Client // Defaults to http.DefaultClient
+
+	id atomic.Uint64
+}
+
+// NewRPCClient creates a JSON-RPC client for an endpoint, e.g. "http://localhost:8545".
+func NewRPCClient(url string) *RPCClient {
+	return &RPCClient{URL: url}
+}
+
+// rpcError is an error returned by a JSON-RPC endpoint.
+type rpcError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *rpcError) Error() string {
+	return fmt.Sprintf("rpc error %d: %s", e.Code, e.Message)
+}
+
+// Call calls a JSON-RPC method and decodes its result into result.
+func (c *RPCClient) Call(ctx context.Context, result interface{}, method string, params ...interface{}) error {
+	if params == nil {
+		params = []interface{}{}
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      c.id.Add(1),
+		"method":  method,
+		"params":  params,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s request failed: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read %s response: %w", method, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s request failed: %s", method, resp.Status)
+	}
+
+	var response struct {
+		Result json.RawMessage `json:"result"`
+		Error  *rpcError       `json:"error"`
+	}
+	if err := json.Unmarshal(data, &response); err != nil {
+		return fmt.Errorf("invalid %s response: %v", method, err)
+	}
+	if response.Error != nil {
+		return response.Error
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(response.Result, result); err != nil {
+		return fmt.Errorf("invalid %s result: %v", method, err)
+	}
+	return nil
+}
+
+// GetCode returns the code of an account at a block ("latest", "pending" or a hex block number).
+func (c *RPCClient) GetCode(ctx context.Context, address Address, block string) ([]byte, error) {
+	if block == "" {
+		block = "latest"
+	}
+	var code string
+	if err := c.Call(ctx, &code, "eth_getCode", address.Hex(), block); err != nil {
+		return nil, err
+	}
+	return decodeHex(code)
+}
@@ -0,0 +1,193 @@
+package gosolc
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// SelectorGroup is a named set of contracts whose functions are called through a single address, e.g. a proxy
+// and its implementation, or the facets of an EIP-2535 diamond.
+type SelectorGroup struct {
+	Name      string
+	Contracts []string // Fully qualified or unique bare contract names
+}
+
+// SelectorFunction is a function of a contract of a selector group.
+type SelectorFunction struct {
+	Contract  string // Fully qualified name of the contract
+	Signature string // e.g. "transfer(address,uint256)"
+}
+
+// SelectorCollision is a selector used by functions of several contracts of a group, so that calls can only be
+// routed to one of them.
+type SelectorCollision struct {
+	Group     string
+	Selector  string // 0x prefixed 4-byte selector
+	Functions []SelectorFunction
+}
+
+// String returns the collision in the "group: selector: contract.signature, ..." format.
+func (c *SelectorCollision) String() string {
+	functions := make([]string, len(c.Functions))
+	for i, function := range c.Functions {
+		functions[i] = function.Contract + "." + function.Signature
+	}
+	return fmt.Sprintf("%s: %s: %s", c.Group, c.Selector, strings.Join(functions, ", "))
+}
+
+// SelectorCollisions returns the selectors shared by functions of different contracts of each group, ordered by
+// group and selector. A function inherited by several contracts of a group collides too, since a proxy or a
+// diamond can only route its selector to one of them. Selectors are read from evm.methodIdentifiers, or computed
+// from the ABI for outputs without it (e.g. artifacts).
+func (contracts CompilerOutput) SelectorCollisions(groups ...SelectorGroup) ([]*SelectorCollision, error) {
+	var collisions []*SelectorCollision
+	for _, group := range groups {
+		functions, selectors, err := contracts.groupSelectors(group.Contracts)
+		if err != nil {
+			return nil, fmt.Errorf("selector group %s: %v", group.Name, err)
+		}
+		for _, selector := range selectors {
+			if len(functions[selector]) > 1 {
+				collisions = append(collisions, &SelectorCollision{Group: group.Name, Selector: selector, Functions: functions[selector]})
+			}
+		}
+	}
+	return collisions, nil
+}
+
+// groupSelectors returns the functions of contracts by selector, and the sorted selectors.
+func (contracts CompilerOutput) groupSelectors(names []string) (map[string][]SelectorFunction, []string, error) {
+	functions := map[string][]SelectorFunction{}
+	for _, name := range names {
+		fqName, _, err := contracts.resolveContract(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		ids, err := contracts.selectors(fqName)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		signatures := make([]string, 0, len(ids))
+		for signature := range ids {
+			signatures = append(signatures, signature)
+		}
+		sort.Strings(signatures)
+		for _, signature := range signatures {
+			selector := "0x" + ids[signature]
+			functions[selector] = append(functions[selector], SelectorFunction{Contract: fqName, Signature: signature})
+		}
+	}
+
+	selectors := make([]string, 0, len(functions))
+	for selector := range functions {
+		selectors = append(selectors, selector)
+	}
+	sort.Strings(selectors)
+	return functions, selectors, nil
+}
+
+// selectors returns the method identifiers of a contract, computed from its ABI if the output has none.
+func (contracts CompilerOutput) selectors(fqName string) (map[string]string, error) {
+	if ids, err := contracts.GetMethodIdentifiers(fqName); err == nil {
+		return ids, nil
+	}
+	abi, err := contracts.ContractABI(fqName)
+	if err != nil {
+		return nil, err
+	}
+	return abi.MethodIdentifiers(), nil
+}
+
+// DiamondFacetCut is the FacetCut adding the functions of a facet to a diamond (see diamondCut in EIP-2535).
+type DiamondFacetCut struct {
+	Facet      string   // Fully qualified name of the facet contract
+	Action     string   // FacetCutAction, always "Add"
+	Selectors  []string // 0x prefixed selectors routed to the facet, sorted
+	Signatures []string // Signatures of the selectors
+}
+
+// Diamond is an EIP-2535 diamond composed of facets.
+type Diamond struct {
+	ABI        *ABI                 // Merged ABI of the facets: their functions, events and errors
+	Cuts       []*DiamondFacetCut   // Facet cuts, in the order of the facets, without those no selector is routed to
+	Collisions []*SelectorCollision // Selectors of several facets, routed to the first one
+}
+
+// Diamond composes facets into a diamond: it merges their ABIs and builds the cut table routing each selector to
+// a facet. A selector of several facets is routed to the first facet listing it and reported in Collisions,
+// which the caller decides to accept (e.g. a shared supportsInterface) or not.
+func (contracts CompilerOutput) Diamond(facets ...string) (*Diamond, error) {
+	collisions, err := contracts.SelectorCollisions(SelectorGroup{Name: "diamond", Contracts: facets})
+	if err != nil {
+		return nil, err
+	}
+	diamond := &Diamond{ABI: &ABI{}, Collisions: collisions}
+
+	routed := map[string]bool{}
+	events, errors := map[string]bool{}, map[string]bool{}
+	for _, facet := range facets {
+		fqName, _, err := contracts.resolveContract(facet)
+		if err != nil {
+			return nil, err
+		}
+		abi, err := contracts.ContractABI(fqName)
+		if err != nil {
+			return nil, err
+		}
+
+		cut := &DiamondFacetCut{Facet: fqName, Action: "Add"}
+		for _, function := range abi.Functions {
+			selector := selectorHex(function)
+			if routed[selector] {
+				continue
+			}
+			routed[selector] = true
+			diamond.ABI.Functions = append(diamond.ABI.Functions, function)
+			cut.Selectors = append(cut.Selectors, selector)
+		}
+		sort.Strings(cut.Selectors)
+		ids := abi.MethodIdentifiers()
+		bySelector := make(map[string]string, len(ids))
+		for signature, id := range ids {
+			bySelector["0x"+id] = signature
+		}
+		for _, selector := range cut.Selectors {
+			cut.Signatures = append(cut.Signatures, bySelector[selector])
+		}
+		// diamondCut reverts on a cut without selectors
+		if len(cut.Selectors) > 0 {
+			diamond.Cuts = append(diamond.Cuts, cut)
+		}
+
+		for _, event := range abi.Events {
+			if !events[eventSignature(event)] {
+				events[eventSignature(event)] = true
+				diamond.ABI.Events = append(diamond.ABI.Events, event)
+			}
+		}
+		for _, e := range abi.Errors {
+			if !errors[e.Signature()] {
+				errors[e.Signature()] = true
+				diamond.ABI.Errors = append(diamond.ABI.Errors, e)
+			}
+		}
+	}
+	return diamond, nil
+}
+
+// Table formats the selector-to-facet cut table of the diamond, one row per selector.
+func (d *Diamond) Table() string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Selector\tSignature\tFacet")
+	for _, cut := range d.Cuts {
+		for i, selector := range cut.Selectors {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", selector, cut.Signatures[i], cut.Facet)
+		}
+	}
+	w.Flush()
+	return b.String()
+}
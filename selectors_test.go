@@ -0,0 +1,76 @@
+package gosolc
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSelectorCollisions(t *testing.T) {
+	output := loadTestOutput(t, "testdata_output.json")
+
+	collisions, err := output.SelectorCollisions(
+		SelectorGroup{Name: "token", Contracts: []string{"dummy_token.sol:Token"}},
+		SelectorGroup{Name: "proxy", Contracts: []string{"ERC20", "Token"}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(collisions) != 2 {
+		t.Fatalf("expected 2 collisions, got %v", collisions)
+	}
+	if s := collisions[0].String(); s != "proxy: 0x06fdde03: dummy_ERC20.sol:ERC20.name(), dummy_token.sol:Token.name()" {
+		t.Errorf("unexpected collision %q", s)
+	}
+
+	if _, err := output.SelectorCollisions(SelectorGroup{Name: "missing", Contracts: []string{"Missing"}}); err == nil {
+		t.Error("expected an error for a missing contract")
+	}
+}
+
+func TestDiamond(t *testing.T) {
+	var output CompilerOutput
+	err := json.Unmarshal([]byte(`{
+		"facets/Loupe.sol": {"LoupeFacet": {"abi": [
+			{"type": "function", "name": "facets", "inputs": [], "outputs": [{"name": "", "type": "address[]"}], "stateMutability": "view"},
+			{"type": "function", "name": "supportsInterface", "inputs": [{"name": "id", "type": "bytes4"}], "outputs": [{"name": "", "type": "bool"}], "stateMutability": "view"}
+		]}},
+		"facets/Token.sol": {"TokenFacet": {"abi": [
+			{"type": "function", "name": "transfer", "inputs": [{"name": "to", "type": "address"}, {"name": "amount", "type": "uint256"}], "outputs": [{"name": "", "type": "bool"}], "stateMutability": "nonpayable"},
+			{"type": "function", "name": "supportsInterface", "inputs": [{"name": "id", "type": "bytes4"}], "outputs": [{"name": "", "type": "bool"}], "stateMutability": "view"},
+			{"type": "event", "name": "Transfer", "inputs": [{"name": "from", "type": "address", "indexed": true}], "anonymous": false},
+			{"type": "error", "name": "Insufficient", "inputs": []},
+			{"type": "receive", "stateMutability": "payable"}
+		]}},
+		"facets/Introspection.sol": {"IntrospectionFacet": {"abi": [
+			{"type": "function", "name": "supportsInterface", "inputs": [{"name": "id", "type": "bytes4"}], "outputs": [{"name": "", "type": "bool"}], "stateMutability": "view"}
+		]}}
+	}`), &output)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diamond, err := output.Diamond("LoupeFacet", "TokenFacet", "IntrospectionFacet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diamond.Collisions) != 1 || diamond.Collisions[0].Selector != "0x01ffc9a7" {
+		t.Errorf("expected the supportsInterface collision, got %v", diamond.Collisions)
+	}
+	// all the selectors of IntrospectionFacet are routed to LoupeFacet, an empty cut would revert
+	if len(diamond.Cuts) != 2 {
+		t.Errorf("expected no cut for IntrospectionFacet, got %d cuts", len(diamond.Cuts))
+	}
+	if len(diamond.ABI.Functions) != 3 || len(diamond.ABI.Events) != 1 || len(diamond.ABI.Errors) != 1 || diamond.ABI.Receive != nil {
+		t.Errorf("unexpected merged ABI %+v", diamond.ABI)
+	}
+	if cut := diamond.Cuts[1]; fmt.Sprint(cut.Selectors, cut.Signatures) != "[0xa9059cbb] [transfer(address,uint256)]" {
+		t.Errorf("unexpected cut %+v", cut)
+	}
+
+	lines := strings.Split(strings.TrimSpace(diamond.Table()), "\n")
+	if len(lines) != 4 || strings.Join(strings.Fields(lines[1]), " ") != "0x01ffc9a7 supportsInterface(bytes4) facets/Loupe.sol:LoupeFacet" {
+		t.Errorf("unexpected table:\n%s", diamond.Table())
+	}
+}
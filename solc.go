@@ -3,6 +3,7 @@ package gosolc
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"rogchap.com/v8go"
 )
@@ -13,10 +14,22 @@ type SolcOptimizerConfig struct {
 	Runs    int  `json:"runs"`    // Number of optimization runs
 }
 
+// SolcMetadataConfig holds the metadata settings passed to solc
+type SolcMetadataConfig struct {
+	UseLiteralContent bool   `json:"useLiteralContent,omitempty"` // Store source contents instead of hashes in the metadata
+	BytecodeHash      string `json:"bytecodeHash,omitempty"`      // Metadata hash appended to the bytecode: "ipfs" (default), "bzzr1" or "none"
+	AppendCBOR        *bool  `json:"appendCBOR,omitempty"`        // Whether the CBOR metadata is appended to the bytecode (defaults to true)
+}
+
 // CompilerOutput is a map of contract names to their compiled output
 type CompilerConfig struct {
-	EVMVersion    string               `json:"evmVersion"` // EVM version to use for compilation
-	SolcOptimizer *SolcOptimizerConfig `json:"optimizer"`  // Optimizer configuration
+	EVMVersion    string                       `json:"evmVersion"`           // EVM version to use for compilation
+	SolcOptimizer *SolcOptimizerConfig         `json:"optimizer"`            // Optimizer configuration
+	ViaIR         *bool                        `json:"viaIR,omitempty"`      // Compile through the Yul IR pipeline (nil for the legacy pipeline)
+	Metadata      *SolcMetadataConfig          `json:"metadata,omitempty"`   // Metadata configuration (optional)
+	Libraries     map[string]map[string]string `json:"libraries,omitempty"`  // Library addresses linked at compile time, keyed by source unit and library name (optional)
+	Overrides     []*CompilerOverride          `json:"overrides,omitempty"`  // Per-source configuration overrides (optional)
+	SizeLimits    *SizeLimits                  `json:"sizeLimits,omitempty"` // Code size limits enforced by Compile (optional)
 }
 
 // defaultConfig is the default compiler configuration
@@ -50,14 +63,27 @@ type Compiler struct {
 // config: The compiler configuration ( generated from gosolc.NewCompilerConfig() )
 // solcJsPath: The path to the solc-js file (optional). If not provided, a default version will be used
 func NewCompiler(contractsDir string, config *CompilerConfig, solcJsPath string) (*Compiler, error) {
+	contents, err := readContractsDir(contractsDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read contracts directory: %v", err)
+	}
+
+	return NewCompilerFromSources(contents, config, solcJsPath)
+}
+
+// NewCompilerFromSources creates a new Compiler instance for in-memory sources
+// sources: Source unit names (e.g. "Token.sol") mapped to their Solidity source code
+// config: The compiler configuration ( generated from gosolc.NewCompilerConfig() )
+// solcJsPath: The path to the solc-js file (optional). If not provided, a default version will be used
+func NewCompilerFromSources(sources map[string]string, config *CompilerConfig, solcJsPath string) (*Compiler, error) {
 	c := &Compiler{
 		CompilerConfig: config,
 	}
 
 	var err error
-	c.Sources, err = contractsDirToSourcesMap(contractsDir)
+	c.Sources, err = sourcesMap(sources)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read contracts directory: %v", err)
+		return nil, fmt.Errorf("failed to read sources: %v", err)
 	}
 
 	c.CompilerInput, err = c.getInputJSON()
@@ -93,20 +119,55 @@ func NewDefaultCompiler(contractsDir string) (*Compiler, error) {
 }
 
 // Compile() compiles the Solidity contracts using the solc-js compiler
+// Errors reported by solc (not warnings) fail the compilation with an error listing their messages.
+// Sources matching a CompilerOverride are compiled by separate solc invocations and merged transparently.
+// With SizeLimits, contracts over the limits are reported by an error wrapping ErrSizeLimitExceeded, returned
+// with the output.
 func (c Compiler) Compile() (CompilerOutput, error) {
+	contracts, err := c.compile()
+	if err != nil {
+		return nil, err
+	}
+	if c.CompilerConfig.SizeLimits != nil {
+		return contracts, contracts.checkSizeLimits(c.CompilerConfig.SizeLimits)
+	}
+	return contracts, nil
+}
+
+// compile compiles the sources without checking the size limits, for the builds that check them once on
+// their merged output.
+func (c Compiler) compile() (CompilerOutput, error) {
+	if len(c.CompilerConfig.Overrides) > 0 {
+		return c.compileWithOverrides()
+	}
+
+	output, err := runSolc(c.SolcJs, c.CompilerInput)
+	if err != nil {
+		return nil, err
+	}
+
+	contracts, ok := output["contracts"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid contracts output")
+	}
+
+	return contracts, nil
+}
+
+// runSolc loads solcJs into a fresh V8 isolate, compiles the (escaped) standard JSON input
+// and returns the parsed standard JSON output. Errors reported by solc are returned as an error.
+func runSolc(solcJs string, compilerInput string) (map[string]interface{}, error) {
 	iso := v8go.NewIsolate()
 	defer iso.Dispose()
 
 	ctx := v8go.NewContext(iso)
 	defer ctx.Close()
 
-	script := fmt.Sprintf(wrapperScript, c.SolcJs)
-	_, err := ctx.RunScript(script, "soljson_wrapper.js")
-	if err != nil {
-		return nil, fmt.Errorf("failed to load solc-js: %w", err)
+	if err := loadSolcJs(ctx, solcJs); err != nil {
+		return nil, err
 	}
 
-	compileScript := fmt.Sprintf(`solc.compile('%s', '')`, c.CompilerInput)
+	compileScript := fmt.Sprintf(`solc.compile('%s', '')`, compilerInput)
 
 	outputVal, err := ctx.RunScript(compileScript, "compile.js")
 	if err != nil {
@@ -120,12 +181,71 @@ func (c Compiler) Compile() (CompilerOutput, error) {
 		return nil, fmt.Errorf("failed to parse compiler output: %v", err)
 	}
 
-	contracts, ok := output["contracts"].(map[string]interface{})
-	if !ok {
-		return nil, fmt.Errorf("invalid contracts output")
+	if err := compilerErrors(output); err != nil {
+		return nil, err
 	}
 
-	return contracts, nil
+	return output, nil
+}
+
+// loadSolcJs runs solcJs with the wrapper script in ctx, defining solc.compile (and solc.version).
+func loadSolcJs(ctx *v8go.Context, solcJs string) error {
+	script := fmt.Sprintf(wrapperScript, solcJs)
+	if _, err := ctx.RunScript(script, "soljson_wrapper.js"); err != nil {
+		return fmt.Errorf("failed to load solc-js: %w", err)
+	}
+	return nil
+}
+
+// solcLongVersion returns the full version string of a solc-js build, e.g. "0.8.29+commit.ab55807c.Emscripten.clang".
+func solcLongVersion(solcJs string) (string, error) {
+	iso := v8go.NewIsolate()
+	defer iso.Dispose()
+
+	ctx := v8go.NewContext(iso)
+	defer ctx.Close()
+
+	if err := loadSolcJs(ctx, solcJs); err != nil {
+		return "", err
+	}
+
+	versionVal, err := ctx.RunScript(`typeof solc.version === 'function' ? solc.version() : ''`, "version.js")
+	if err != nil {
+		return "", fmt.Errorf("failed to get solc version: %w", err)
+	}
+	if versionVal.String() == "" {
+		return "", fmt.Errorf("solidity_version not available in solc-js")
+	}
+	return versionVal.String(), nil
+}
+
+// LongVersion returns the full version string of the solc-js build used by the compiler,
+// e.g. "0.8.29+commit.ab55807c.Emscripten.clang".
+func (c Compiler) LongVersion() (string, error) {
+	return solcLongVersion(c.SolcJs)
+}
+
+// compilerErrors returns an error listing the messages of all errors (not warnings) in the solc output.
+func compilerErrors(output map[string]interface{}) error {
+	errs, _ := output["errors"].([]interface{})
+
+	var messages []string
+	for _, e := range errs {
+		entry, ok := e.(map[string]interface{})
+		if !ok || entry["severity"] != "error" {
+			continue
+		}
+		message, _ := entry["formattedMessage"].(string)
+		if message == "" {
+			message, _ = entry["message"].(string)
+		}
+		messages = append(messages, strings.TrimSpace(message))
+	}
+
+	if len(messages) > 0 {
+		return fmt.Errorf("solc reported errors:\n%s", strings.Join(messages, "\n"))
+	}
+	return nil
 }
 
 // CompileAndWriteOutput compiles the Solidity contracts and writes the output to files in ./solc-go-build
@@ -1,6 +1,53 @@
 package gosolc
 
-import "testing"
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCompilerErrors(t *testing.T) {
+	output := map[string]interface{}{
+		"errors": []interface{}{
+			map[string]interface{}{"severity": "warning", "formattedMessage": "Warning: Unused local variable."},
+			map[string]interface{}{"severity": "error", "formattedMessage": "ParserError: Expected ';' but got '}'\n"},
+			map[string]interface{}{"severity": "error", "message": "Undeclared identifier."},
+		},
+	}
+	err := compilerErrors(output)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if err.Error() != "solc reported errors:\nParserError: Expected ';' but got '}'\nUndeclared identifier." {
+		t.Errorf("unexpected error %q", err)
+	}
+
+	output["errors"] = output["errors"].([]interface{})[:1]
+	if err := compilerErrors(output); err != nil {
+		t.Errorf("unexpected error for warnings only: %v", err)
+	}
+}
+
+func TestE2ECompileErrors(t *testing.T) {
+	if solcJS_0_8_29 == "" {
+		t.Skip("embedded soljson is not available")
+	}
+
+	dir := t.TempDir()
+	source := "pragma solidity ^0.8.0;\ncontract Broken { function f() public { uint x = 1 } }\n"
+	if err := os.WriteFile(filepath.Join(dir, "Broken.sol"), []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewCompiler(dir, NewCompilerConfig("cancun", false, 0), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	compiled, err := c.Compile()
+	if err == nil || compiled != nil || !strings.Contains(err.Error(), "ParserError") {
+		t.Fatalf("expected a ParserError, got %v", err)
+	}
+}
 
 func TestE2ECompile(t *testing.T) {
 	// Define the path to the solc-js file
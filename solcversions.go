@@ -1,6 +1,72 @@
 package gosolc
 
-import _ "embed"
+import (
+	_ "embed"
+	"fmt"
+	"sort"
+	"sync"
+)
 
 //go:embed solc-bin/soljson-v0.8.29+commit.ab55807c.js
 var solcJS_0_8_29 string
+
+// solcJsRegistry maps a solc version (e.g. "0.8.29") to the solc-js source used to compile with it.
+// The embedded 0.8.29 build is always registered.
+var (
+	solcJsRegistryMu sync.RWMutex
+	solcJsRegistry   = map[string]string{
+		"0.8.29": solcJS_0_8_29,
+	}
+)
+
+// RegisterSolcJs registers a solc-js build for the given version so that projects can compile
+// sources whose pragma requires it. Registering an already known version replaces it.
+func RegisterSolcJs(version string, solcJs string) error {
+	v, err := parseSolcVersion(version)
+	if err != nil {
+		return fmt.Errorf("invalid solc version %q: %v", version, err)
+	}
+
+	solcJsRegistryMu.Lock()
+	defer solcJsRegistryMu.Unlock()
+	solcJsRegistry[v.String()] = solcJs
+
+	return nil
+}
+
+// RegisterSolcJsFromPath reads the solc-js file at path and registers it for the given version.
+func RegisterSolcJsFromPath(version string, path string) error {
+	solcJSBytes, err := solcJsFromPath(path)
+	if err != nil {
+		return fmt.Errorf("failed to read solc-js: %v", err)
+	}
+	return RegisterSolcJs(version, string(solcJSBytes))
+}
+
+// RegisteredSolcVersions returns the registered solc versions in ascending order.
+func RegisteredSolcVersions() []string {
+	solcJsRegistryMu.RLock()
+	defer solcJsRegistryMu.RUnlock()
+
+	versions := make([]solcVersion, 0, len(solcJsRegistry))
+	for version := range solcJsRegistry {
+		v, _ := parseSolcVersion(version)
+		versions = append(versions, v)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i].compare(versions[j]) < 0 })
+
+	result := make([]string, len(versions))
+	for i, v := range versions {
+		result[i] = v.String()
+	}
+	return result
+}
+
+// solcJsForVersion returns the registered solc-js source for the given version.
+func solcJsForVersion(version string) (string, bool) {
+	solcJsRegistryMu.RLock()
+	defer solcJsRegistryMu.RUnlock()
+
+	solcJs, ok := solcJsRegistry[version]
+	return solcJs, ok
+}
@@ -0,0 +1,179 @@
+package gosolc
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// SourceMapEntry is the source range of an instruction in a solc source map.
+type SourceMapEntry struct {
+	Start         int    // Byte offset in the source
+	Length        int    // Length in bytes
+	SourceID      int    // Source id, -1 for instructions without source (e.g. the metadata)
+	Jump          string // "i" into a function, "o" out of a function or "-" for regular jumps and other instructions
+	ModifierDepth int
+}
+
+// SourceMap is a decoded source map, with one entry per instruction of the bytecode.
+type SourceMap []SourceMapEntry
+
+// ParseSourceMap decodes a compressed solc source map ("s:l:f:j:m;..."), in which empty fields
+// repeat the value of the previous entry.
+func ParseSourceMap(sourceMap string) (SourceMap, error) {
+	if sourceMap == "" {
+		return SourceMap{}, nil
+	}
+
+	current := SourceMapEntry{SourceID: -1, Jump: "-"}
+	items := strings.Split(sourceMap, ";")
+	entries := make(SourceMap, 0, len(items))
+	for i, item := range items {
+		fields := strings.Split(item, ":")
+		if len(fields) > 5 {
+			return nil, fmt.Errorf("invalid source map entry %d %q", i, item)
+		}
+
+		for j, field := range fields {
+			if field == "" {
+				continue
+			}
+			if j == 3 {
+				if field != "i" && field != "o" && field != "-" {
+					return nil, fmt.Errorf("invalid jump type %q in source map entry %d", field, i)
+				}
+				current.Jump = field
+				continue
+			}
+
+			value, err := strconv.Atoi(field)
+			if err != nil {
+				return nil, fmt.Errorf("invalid source map entry %d %q: %v", i, item, err)
+			}
+			switch j {
+			case 0:
+				current.Start = value
+			case 1:
+				current.Length = value
+			case 2:
+				current.SourceID = value
+			case 4:
+				current.ModifierDepth = value
+			}
+		}
+		entries = append(entries, current)
+	}
+
+	return entries, nil
+}
+
+// libraryPlaceholderRegexp matches the placeholders of unlinked libraries in bytecode.
+var libraryPlaceholderRegexp = regexp.MustCompile(`__\$[0-9a-fA-F]{34}\$__`)
+
+// decodeBytecode decodes hex bytecode; placeholders of unlinked libraries are decoded as zero addresses.
+func decodeBytecode(bytecode string) ([]byte, error) {
+	bytecode = libraryPlaceholderRegexp.ReplaceAllString(bytecode, strings.Repeat("0", 40))
+	code, err := decodeHex(bytecode)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bytecode: %v", err)
+	}
+	return code, nil
+}
+
+// InstructionIndex returns the index of the instruction starting at pc, skipping PUSH immediates.
+// Source map entries are indexed by instruction.
+func InstructionIndex(code []byte, pc int) (int, error) {
+	if pc < 0 || pc >= len(code) {
+		return 0, fmt.Errorf("pc %d is outside of the bytecode (%d bytes)", pc, len(code))
+	}
+
+	index := 0
+	for offset := 0; offset < len(code); index++ {
+		if offset == pc {
+			return index, nil
+		}
+		if offset > pc {
+			break
+		}
+		offset += 1 + pushSize(code[offset])
+	}
+	return 0, fmt.Errorf("pc %d is not the start of an instruction", pc)
+}
+
+// pushSize returns the size of the immediate of PUSH1 to PUSH32, 0 for other opcodes.
+func pushSize(opcode byte) int {
+	if opcode >= 0x60 && opcode <= 0x7f {
+		return int(opcode-0x60) + 1
+	}
+	return 0
+}
+
+// PCLocation is the source location of the instruction at a program counter.
+type PCLocation struct {
+	*SourceLocation
+	PC          int
+	Instruction int    // Index of the instruction in the bytecode
+	Jump        string // Jump type of the instruction, see SourceMapEntry
+	Snippet     string // Source code of the range
+}
+
+// PCLocation maps a program counter of the runtime bytecode (deployed is true) or creation bytecode of a
+// contract to its source location, through the instruction index and the source map of the bytecode.
+// Instructions without source, e.g. in compiler generated code, return an error.
+func (out *StandardOutput) PCLocation(fqName string, pc int, deployed bool) (*PCLocation, error) {
+	contract, err := out.Contracts.Contract(fqName)
+	if err != nil {
+		return nil, err
+	}
+
+	key := "bytecode"
+	if deployed {
+		key = "deployedBytecode"
+	}
+	evm, _ := contract["evm"].(map[string]interface{})
+	bytecode, _ := evm[key].(map[string]interface{})
+	object, ok := bytecode["object"].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid %s output for contract %s", key, fqName)
+	}
+	rawSourceMap, ok := bytecode["sourceMap"].(string)
+	if !ok {
+		return nil, fmt.Errorf("no %s source map for contract %s", key, fqName)
+	}
+
+	code, err := decodeBytecode(object)
+	if err != nil {
+		return nil, err
+	}
+	sourceMap, err := ParseSourceMap(rawSourceMap)
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := InstructionIndex(code, pc)
+	if err != nil {
+		return nil, err
+	}
+	if index >= len(sourceMap) {
+		return nil, fmt.Errorf("pc %d (instruction %d) is not covered by the source map", pc, index)
+	}
+
+	entry := sourceMap[index]
+	name, source, ok := out.SourceByID(entry.SourceID)
+	if !ok {
+		return nil, fmt.Errorf("pc %d (instruction %d) has no source location", pc, index)
+	}
+	location, err := source.location(name, entry.Start, entry.Length)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PCLocation{
+		SourceLocation: location,
+		PC:             pc,
+		Instruction:    index,
+		Jump:           entry.Jump,
+		Snippet:        source.Content[entry.Start : entry.Start+entry.Length],
+	}, nil
+}
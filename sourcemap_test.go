@@ -0,0 +1,57 @@
+package gosolc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSourceMap(t *testing.T) {
+	sourceMap, err := ParseSourceMap("1:2:1;:9;2:1:2;;;:::o:1;5:3::i")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := SourceMap{
+		{Start: 1, Length: 2, SourceID: 1, Jump: "-"},
+		{Start: 1, Length: 9, SourceID: 1, Jump: "-"},
+		{Start: 2, Length: 1, SourceID: 2, Jump: "-"},
+		{Start: 2, Length: 1, SourceID: 2, Jump: "-"},
+		{Start: 2, Length: 1, SourceID: 2, Jump: "-"},
+		{Start: 2, Length: 1, SourceID: 2, Jump: "o", ModifierDepth: 1},
+		{Start: 5, Length: 3, SourceID: 2, Jump: "i", ModifierDepth: 1},
+	}
+	if !reflect.DeepEqual(sourceMap, expected) {
+		t.Errorf("unexpected source map:\nexpected %v\ngot      %v", expected, sourceMap)
+	}
+
+	if _, err := ParseSourceMap("1:2:1:x"); err == nil {
+		t.Error("expected an error for an invalid jump type")
+	}
+}
+
+func TestPCLocation(t *testing.T) {
+	out := loadASTTestOutput(t)
+
+	// PUSH1 0x80, PUSH1 0x40, MSTORE, PUSH20 <unlinked library>, POP, STOP
+	out.Contracts = CompilerOutput{"Counter.sol": map[string]interface{}{"Counter": map[string]interface{}{
+		"evm": map[string]interface{}{"deployedBytecode": map[string]interface{}{
+			"object":    "608060405273__$0123456789abcdef0123456789abcdef01$__5000",
+			"sourceMap": "85:16:0:-:0;;;249:15::i;;::-1:o",
+		}},
+	}}}
+
+	location, err := out.PCLocation("Counter", 26, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if location.Instruction != 4 || location.String() != "Counter.sol:11:9" || location.Jump != "i" || location.Snippet != "count += amount" {
+		t.Errorf("unexpected location %+v %+v", location, location.SourceLocation)
+	}
+
+	if _, err := out.PCLocation("Counter", 6, true); err == nil {
+		t.Error("expected an error for a pc inside a PUSH immediate")
+	}
+	if _, err := out.PCLocation("Counter", 27, true); err == nil {
+		t.Error("expected an error for an instruction without source")
+	}
+}
@@ -0,0 +1,219 @@
+package gosolc
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// StandardOutput is the output of the Solidity compiler including the per-source outputs that Compile()
+// drops: the source ids and ASTs, alongside the raw source contents and the compiler warnings.
+type StandardOutput struct {
+	Contracts CompilerOutput           // Contracts keyed by file and contract name, as returned by Compile()
+	Sources   map[string]*SourceOutput // Per-source outputs keyed by source unit name
+	Warnings  []string                 // Formatted messages of warnings and infos reported by solc
+
+	nodes map[int64]Node // Index of all AST nodes by id, built on first lookup
+}
+
+// SourceOutput is the output of solc for a single source unit.
+type SourceOutput struct {
+	ID      int         // Source id, used as file index in src locations and source maps
+	AST     *SourceUnit // Typed compact JSON AST
+	Content string      // Raw source content
+
+	lines []int // Byte offsets of the start of each line, built on first lookup
+}
+
+// SourceLocation is the resolved location of a src attribute ("start:length:sourceId").
+// Lines and columns are 1-based, columns count characters.
+type SourceLocation struct {
+	File      string
+	Start     int // Byte offset in the source
+	Length    int // Length in bytes
+	Line      int
+	Column    int
+	EndLine   int
+	EndColumn int
+}
+
+// String returns the location in the file:line:column format.
+func (l *SourceLocation) String() string {
+	return fmt.Sprintf("%s:%d:%d", l.File, l.Line, l.Column)
+}
+
+// CompileStandard compiles the Solidity contracts like Compile() and also returns the per-source outputs.
+// Source ids are specific to a solc invocation, so it can't be used with compiler overrides.
+func (c Compiler) CompileStandard() (*StandardOutput, error) {
+	if len(c.CompilerConfig.Overrides) > 0 {
+		return nil, fmt.Errorf("CompileStandard doesn't support compiler overrides, their sources are compiled separately")
+	}
+
+	output, err := runSolc(c.SolcJs, c.CompilerInput)
+	if err != nil {
+		return nil, err
+	}
+
+	contents, err := sourceContents(c.Sources)
+	if err != nil {
+		return nil, err
+	}
+
+	return newStandardOutput(output, contents)
+}
+
+// newStandardOutput builds a StandardOutput from the raw solc output and the compiled source contents.
+func newStandardOutput(output map[string]interface{}, contents map[string]string) (*StandardOutput, error) {
+	contracts, ok := output["contracts"].(map[string]interface{})
+	if !ok {
+		contracts = map[string]interface{}{}
+	}
+
+	out := &StandardOutput{
+		Contracts: contracts,
+		Sources:   map[string]*SourceOutput{},
+	}
+
+	sources, _ := output["sources"].(map[string]interface{})
+	for name, source := range sources {
+		sourceOutput, _ := source.(map[string]interface{})
+		id, ok := sourceOutput["id"].(float64)
+		if !ok {
+			return nil, fmt.Errorf("invalid source output for %s", name)
+		}
+
+		var ast *SourceUnit
+		if sourceOutput["ast"] != nil {
+			node, err := decodeASTValue(sourceOutput["ast"])
+			if err != nil {
+				return nil, fmt.Errorf("invalid AST of %s: %v", name, err)
+			}
+			if ast, ok = node.(*SourceUnit); !ok {
+				return nil, fmt.Errorf("invalid AST of %s: expected SourceUnit, got %s", name, node.Base().NodeType)
+			}
+		}
+
+		out.Sources[name] = &SourceOutput{ID: int(id), AST: ast, Content: contents[name]}
+	}
+
+	errs, _ := output["errors"].([]interface{})
+	for _, e := range errs {
+		entry, ok := e.(map[string]interface{})
+		if !ok || entry["severity"] == "error" {
+			continue
+		}
+		message, _ := entry["formattedMessage"].(string)
+		if message == "" {
+			message, _ = entry["message"].(string)
+		}
+		out.Warnings = append(out.Warnings, strings.TrimSpace(message))
+	}
+
+	return out, nil
+}
+
+// SourceNames returns the source unit names ordered by source id.
+func (out *StandardOutput) SourceNames() []string {
+	names := make([]string, 0, len(out.Sources))
+	for name := range out.Sources {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return out.Sources[names[i]].ID < out.Sources[names[j]].ID })
+	return names
+}
+
+// SourceByID returns the name and output of the source with the given id.
+func (out *StandardOutput) SourceByID(id int) (string, *SourceOutput, bool) {
+	for name, source := range out.Sources {
+		if source.ID == id {
+			return name, source, true
+		}
+	}
+	return "", nil, false
+}
+
+// NodeByID returns the AST node with the given id, searching all sources.
+func (out *StandardOutput) NodeByID(id int64) (Node, bool) {
+	if out.nodes == nil {
+		out.nodes = map[int64]Node{}
+		for _, source := range out.Sources {
+			if source.AST == nil {
+				continue
+			}
+			Inspect(source.AST, func(node Node) bool {
+				if node != nil {
+					out.nodes[node.Base().ID] = node
+				}
+				return true
+			})
+		}
+	}
+
+	node, ok := out.nodes[id]
+	return node, ok
+}
+
+// Location resolves a src attribute ("start:length:sourceId") to its file, line and column.
+func (out *StandardOutput) Location(src string) (*SourceLocation, error) {
+	parts := strings.Split(src, ":")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("invalid src %q", src)
+	}
+
+	var values [3]int
+	for i, part := range parts {
+		value, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid src %q: %v", src, err)
+		}
+		values[i] = value
+	}
+
+	name, source, ok := out.SourceByID(values[2])
+	if !ok {
+		return nil, fmt.Errorf("src %q refers to unknown source %d", src, values[2])
+	}
+	return source.location(name, values[0], values[1])
+}
+
+// NodeLocation resolves the src attribute of a node.
+func (out *StandardOutput) NodeLocation(node Node) (*SourceLocation, error) {
+	return out.Location(node.Base().Src)
+}
+
+// location returns the location of a byte range of the source.
+func (s *SourceOutput) location(name string, start, length int) (*SourceLocation, error) {
+	if start < 0 || length < 0 || start+length > len(s.Content) {
+		return nil, fmt.Errorf("range %d:%d is outside of %s", start, length, name)
+	}
+
+	line, column := s.position(start)
+	endLine, endColumn := s.position(start + length)
+	return &SourceLocation{
+		File:      name,
+		Start:     start,
+		Length:    length,
+		Line:      line,
+		Column:    column,
+		EndLine:   endLine,
+		EndColumn: endColumn,
+	}, nil
+}
+
+// position returns the 1-based line and column of a byte offset of the source.
+func (s *SourceOutput) position(offset int) (int, int) {
+	if s.lines == nil {
+		s.lines = []int{0}
+		for i := 0; i < len(s.Content); i++ {
+			if s.Content[i] == '\n' {
+				s.lines = append(s.lines, i+1)
+			}
+		}
+	}
+
+	line := sort.Search(len(s.lines), func(i int) bool { return s.lines[i] > offset }) - 1
+	column := utf8.RuneCountInString(s.Content[s.lines[line]:offset]) + 1
+	return line + 1, column
+}
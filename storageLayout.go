@@ -0,0 +1,338 @@
+package gosolc
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// StorageLayout is the storageLayout output of a contract: its state variables, in declaration order
+// (base contracts first), and the types they use.
+type StorageLayout struct {
+	Storage []*StorageVariable      `json:"storage"`
+	Types   map[string]*StorageType `json:"types"` // Keyed by type identifier, e.g. "t_mapping(t_address,t_uint256)"
+}
+
+// StorageVariable is a state variable, or a struct member, and its position in storage.
+type StorageVariable struct {
+	ASTID    int64    `json:"astId"`    // AST id of the declaration
+	Contract string   `json:"contract"` // Fully qualified name of the declaring contract
+	Label    string   `json:"label"`    // Variable name
+	Offset   int      `json:"offset"`   // Byte offset in the slot, from the right
+	Slot     *big.Int `json:"slot"`     // Slot, relative to the struct for members
+	Type     string   `json:"type"`     // Type identifier, a key of StorageLayout.Types
+
+	RenamedFrom string `json:"-"` // Previous name from a `@custom:oz-renamed-from` annotation, see StandardOutput.StorageLayout
+}
+
+// UnmarshalJSON decodes the decimal string slot of the compiler output.
+func (v *StorageVariable) UnmarshalJSON(data []byte) error {
+	type storageVariable StorageVariable
+	aux := struct {
+		*storageVariable
+		Slot string `json:"slot"`
+	}{storageVariable: (*storageVariable)(v)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	slot, ok := new(big.Int).SetString(aux.Slot, 10)
+	if !ok {
+		return fmt.Errorf("invalid slot %q of %s", aux.Slot, v.Label)
+	}
+	v.Slot = slot
+	return nil
+}
+
+// StorageType describes a type used in storage.
+type StorageType struct {
+	Encoding      string             `json:"encoding"`          // "inplace", "mapping", "dynamic_array" or "bytes"
+	Label         string             `json:"label"`             // Canonical type name, e.g. "mapping(address => uint256)"
+	NumberOfBytes int                `json:"numberOfBytes"`     // Bytes used, a multiple of 32 for types occupying whole slots
+	Key           string             `json:"key,omitempty"`     // Key type of mappings
+	Value         string             `json:"value,omitempty"`   // Value type of mappings
+	Base          string             `json:"base,omitempty"`    // Element type of arrays
+	Members       []*StorageVariable `json:"members,omitempty"` // Members of structs
+}
+
+// UnmarshalJSON decodes the decimal string numberOfBytes of the compiler output.
+func (t *StorageType) UnmarshalJSON(data []byte) error {
+	type storageType StorageType
+	aux := struct {
+		*storageType
+		NumberOfBytes string `json:"numberOfBytes"`
+	}{storageType: (*storageType)(t)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	n, err := strconv.Atoi(aux.NumberOfBytes)
+	if err != nil {
+		return fmt.Errorf("invalid numberOfBytes %q of %s", aux.NumberOfBytes, t.Label)
+	}
+	t.NumberOfBytes = n
+	return nil
+}
+
+// StorageLayout returns the storage layout of a contract.
+func (contracts CompilerOutput) StorageLayout(fqName string) (*StorageLayout, error) {
+	contract, err := contracts.Contract(fqName)
+	if err != nil {
+		return nil, err
+	}
+	if contract["storageLayout"] == nil {
+		return nil, fmt.Errorf("storage layout of contract %s not found in compiler output", fqName)
+	}
+	var layout StorageLayout
+	if err := decodeOutput(contract["storageLayout"], &layout); err != nil {
+		return nil, fmt.Errorf("invalid storage layout for contract %s: %v", fqName, err)
+	}
+	return &layout, nil
+}
+
+// renamedFromRegexp matches the `@custom:oz-renamed-from <name>` NatSpec annotation of a renamed state variable.
+var renamedFromRegexp = regexp.MustCompile(`@(?:custom:)?oz-renamed-from[ \t]+([A-Za-z_$][A-Za-z0-9_$]*)`)
+
+// StorageLayout returns the storage layout of a contract, with the previous names of the variables annotated
+// with `/// @custom:oz-renamed-from <name>` that CompareStorageLayouts accepts as renames.
+func (out *StandardOutput) StorageLayout(fqName string) (*StorageLayout, error) {
+	layout, err := out.Contracts.StorageLayout(fqName)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range layout.Storage {
+		node, ok := out.NodeByID(v.ASTID)
+		if !ok {
+			continue
+		}
+		if decl, ok := node.(*VariableDeclaration); ok {
+			if m := renamedFromRegexp.FindStringSubmatch(documentation(decl.Documentation)); m != nil {
+				v.RenamedFrom = m[1]
+			}
+		}
+	}
+	return layout, nil
+}
+
+// Type returns the type of a variable, or a placeholder if the type is not listed.
+func (l *StorageLayout) Type(v *StorageVariable) *StorageType {
+	if t, ok := l.Types[v.Type]; ok {
+		return t
+	}
+	return &StorageType{Label: v.Type}
+}
+
+// slots returns the number of slots used by a type from the start of its slot.
+func (t *StorageType) slots(offset int) int64 {
+	return int64((offset + t.NumberOfBytes + 31) / 32)
+}
+
+// Table renders the layout as a table of slots, offsets, sizes, names, types and declaring contracts.
+// Members of structs stored in place are listed below their variable, with their absolute slots.
+func (l *StorageLayout) Table() string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Slot\tOffset\tBytes\tName\tType\tContract")
+	for _, v := range l.Storage {
+		l.writeRows(w, v, new(big.Int), v.Label, v.Contract)
+	}
+	w.Flush()
+	return b.String()
+}
+
+// writeRows writes the row of a variable and of its struct members.
+func (l *StorageLayout) writeRows(w *tabwriter.Writer, v *StorageVariable, base *big.Int, name, contract string) {
+	slot := new(big.Int).Add(base, v.Slot)
+	t := l.Type(v)
+	fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%s\t%s\n", slot, v.Offset, t.NumberOfBytes, name, t.Label, contract)
+	if t.Encoding == "inplace" {
+		for _, member := range t.Members {
+			l.writeRows(w, member, slot, name+"."+member.Label, "")
+		}
+	}
+}
+
+// StorageChangeKind classifies a difference between two storage layouts.
+type StorageChangeKind string
+
+const (
+	StorageRemoved   StorageChangeKind = "removed" // The variable no longer exists
+	StorageMoved     StorageChangeKind = "moved"   // The variable moved to another slot or offset, e.g. after a reorder or insertion
+	StorageRetyped   StorageChangeKind = "retyped" // The variable has an incompatible type
+	StorageShrunk    StorageChangeKind = "shrunk"  // The variable has a smaller type of the same kind, e.g. uint128 instead of uint256
+	StorageGapMisuse StorageChangeKind = "gap"     // A __gap array does not end where it used to, so the variables after it moved
+	StorageRenamed   StorageChangeKind = "renamed" // A variable annotated as renamed from the old one has a compatible type at the same position
+)
+
+// StorageChange is a difference between the storage layouts of two versions of a contract.
+type StorageChange struct {
+	Kind    StorageChangeKind
+	Old     *StorageVariable // Variable of the old layout
+	New     *StorageVariable // Matching variable of the new layout, nil if removed
+	Message string
+}
+
+// Breaking reports whether the change corrupts the storage of a proxy upgraded to the new layout.
+// Annotated renames keep the storage intact.
+func (c *StorageChange) Breaking() bool {
+	return c.Kind != StorageRenamed
+}
+
+// String returns the change as "kind: message".
+func (c *StorageChange) String() string {
+	return string(c.Kind) + ": " + c.Message
+}
+
+// CompareStorageLayouts compares the layout of a deployed implementation with the layout of its upgrade.
+// Variables are matched by declaring contract and name, or by the previous name of a RenamedFrom annotation:
+// another variable taking the place of a removed one is not a rename, it reads the old value. Variables appended
+// after the old ones and variables taking the place of a __gap that shrank accordingly are not reported. Changes
+// are ordered by old slot.
+func CompareStorageLayouts(oldLayout, newLayout *StorageLayout) []*StorageChange {
+	newVars := map[string]*StorageVariable{}
+	renamed := map[string]*StorageVariable{}
+	newAt := map[string]*StorageVariable{}
+	for _, v := range newLayout.Storage {
+		newVars[v.Contract+":"+v.Label] = v
+		if v.RenamedFrom != "" {
+			renamed[v.Contract+":"+v.RenamedFrom] = v
+		}
+		newAt[v.Slot.String()+":"+strconv.Itoa(v.Offset)] = v
+	}
+
+	var changes []*StorageChange
+	for _, o := range oldLayout.Storage {
+		oldType := oldLayout.Type(o)
+		n, ok := newVars[o.Contract+":"+o.Label]
+
+		if isStorageGap(o) {
+			// the gap, or the last variable of the contract if the gap was used up, must end at the same slot
+			oldEnd := new(big.Int).Add(o.Slot, big.NewInt(oldType.slots(o.Offset)))
+			newEnd := new(big.Int)
+			if ok {
+				newEnd.Add(n.Slot, big.NewInt(newLayout.Type(n).slots(n.Offset)))
+			} else {
+				for _, v := range newLayout.Storage {
+					if end := new(big.Int).Add(v.Slot, big.NewInt(newLayout.Type(v).slots(v.Offset))); v.Contract == o.Contract && end.Cmp(newEnd) > 0 {
+						newEnd = end
+					}
+				}
+			}
+			if oldEnd.Cmp(newEnd) != 0 {
+				changes = append(changes, &StorageChange{Kind: StorageGapMisuse, Old: o, New: n,
+					Message: fmt.Sprintf("%s of %s: reserved slots end before slot %s instead of %s, the gap must shrink by the slots of the variables added before it", o.Label, o.Contract, newEnd, oldEnd)})
+			}
+			continue
+		}
+
+		if !ok {
+			n, ok = renamed[o.Contract+":"+o.Label]
+			if !ok {
+				message := fmt.Sprintf("%s of %s at slot %s was removed", o.Label, o.Contract, o.Slot)
+				if r, found := newAt[o.Slot.String()+":"+strconv.Itoa(o.Offset)]; found && r.Contract == o.Contract {
+					message += fmt.Sprintf(", %s now reads its value (annotate it with @custom:oz-renamed-from %s if it was renamed)", r.Label, o.Label)
+				}
+				changes = append(changes, &StorageChange{Kind: StorageRemoved, Old: o, Message: message})
+				continue
+			}
+			if o.Slot.Cmp(n.Slot) == 0 && o.Offset == n.Offset && storageTypesCompatible(oldLayout, o.Type, newLayout, n.Type, false) {
+				changes = append(changes, &StorageChange{Kind: StorageRenamed, Old: o, New: n,
+					Message: fmt.Sprintf("%s of %s was renamed to %s", o.Label, o.Contract, n.Label)})
+				continue
+			}
+		}
+
+		if o.Slot.Cmp(n.Slot) != 0 || o.Offset != n.Offset {
+			changes = append(changes, &StorageChange{Kind: StorageMoved, Old: o, New: n,
+				Message: fmt.Sprintf("%s of %s moved from slot %s offset %d to slot %s offset %d", o.Label, o.Contract, o.Slot, o.Offset, n.Slot, n.Offset)})
+		}
+
+		if !storageTypesCompatible(oldLayout, o.Type, newLayout, n.Type, false) {
+			newType := newLayout.Type(n)
+			kind := StorageRetyped
+			if newType.Encoding == oldType.Encoding && newType.NumberOfBytes < oldType.NumberOfBytes && storageTypeFamily(newType.Label) == storageTypeFamily(oldType.Label) {
+				kind = StorageShrunk
+			}
+			changes = append(changes, &StorageChange{Kind: kind, Old: o, New: n,
+				Message: fmt.Sprintf("%s of %s changed type from %s to %s", o.Label, o.Contract, oldType.Label, newType.Label)})
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		if c := changes[i].Old.Slot.Cmp(changes[j].Old.Slot); c != 0 {
+			return c < 0
+		}
+		return changes[i].Old.Offset < changes[j].Old.Offset
+	})
+	return changes
+}
+
+// isStorageGap reports whether a variable is a __gap array reserving slots for future variables.
+func isStorageGap(v *StorageVariable) bool {
+	return strings.HasPrefix(v.Label, "__gap")
+}
+
+// storageTypesCompatible reports whether values stored with the old type are read back unchanged with the new type.
+// Structs may only grow where their storage is not followed by other data, i.e. as values of mappings.
+func storageTypesCompatible(oldLayout *StorageLayout, oldID string, newLayout *StorageLayout, newID string, appendable bool) bool {
+	o, ok := oldLayout.Types[oldID]
+	if !ok {
+		return oldID == newID
+	}
+	n, ok := newLayout.Types[newID]
+	if !ok || o.Encoding != n.Encoding {
+		return false
+	}
+
+	switch {
+	case o.Encoding == "mapping":
+		return storageTypesCompatible(oldLayout, o.Key, newLayout, n.Key, false) && storageTypesCompatible(oldLayout, o.Value, newLayout, n.Value, true)
+	case o.Encoding == "dynamic_array":
+		return storageTypesCompatible(oldLayout, o.Base, newLayout, n.Base, false)
+	case o.Base != "":
+		// static arrays
+		return o.NumberOfBytes == n.NumberOfBytes && storageTypesCompatible(oldLayout, o.Base, newLayout, n.Base, false)
+	case o.Members != nil:
+		if len(n.Members) < len(o.Members) || (len(n.Members) > len(o.Members) && !appendable) {
+			return false
+		}
+		for i, member := range o.Members {
+			m := n.Members[i]
+			if member.Slot.Cmp(m.Slot) != 0 || member.Offset != m.Offset || !storageTypesCompatible(oldLayout, member.Type, newLayout, m.Type, false) {
+				return false
+			}
+		}
+		return appendable || o.NumberOfBytes == n.NumberOfBytes
+	}
+
+	// value types, contracts and enums are compatible with the same size and kind
+	if o.NumberOfBytes != n.NumberOfBytes {
+		return false
+	}
+	if o.Label == n.Label {
+		return true
+	}
+	family := storageTypeFamily(o.Label)
+	return family != "" && family == storageTypeFamily(n.Label)
+}
+
+// storageTypeFamily returns the kind of a value type label: "uint", "int", "bytes" (fixed size), "address" (including
+// contracts) or "enum". Values of the same kind and size are stored identically, e.g. contract IERC20 and address payable.
+func storageTypeFamily(label string) string {
+	switch {
+	case strings.HasPrefix(label, "uint"):
+		return "uint"
+	case strings.HasPrefix(label, "int"):
+		return "int"
+	case strings.HasPrefix(label, "bytes") && label != "bytes":
+		return "bytes"
+	case strings.HasPrefix(label, "address"), strings.HasPrefix(label, "contract "):
+		return "address"
+	case strings.HasPrefix(label, "enum "):
+		return "enum"
+	}
+	return ""
+}
@@ -0,0 +1,148 @@
+package gosolc
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+// storageLayoutJSON builds a storageLayout output from storage entries (label, slot, offset, type, contract).
+func storageLayoutJSON(t *testing.T, entries ...[5]string) *StorageLayout {
+	t.Helper()
+	types := `{
+		"t_address": {"encoding": "inplace", "label": "address", "numberOfBytes": "20"},
+		"t_contract(IERC20)4": {"encoding": "inplace", "label": "contract IERC20", "numberOfBytes": "20"},
+		"t_bool": {"encoding": "inplace", "label": "bool", "numberOfBytes": "1"},
+		"t_uint256": {"encoding": "inplace", "label": "uint256", "numberOfBytes": "32"},
+		"t_int256": {"encoding": "inplace", "label": "int256", "numberOfBytes": "32"},
+		"t_uint128": {"encoding": "inplace", "label": "uint128", "numberOfBytes": "16"},
+		"t_array(t_uint256)49_storage": {"base": "t_uint256", "encoding": "inplace", "label": "uint256[49]", "numberOfBytes": "1568"},
+		"t_array(t_uint256)48_storage": {"base": "t_uint256", "encoding": "inplace", "label": "uint256[48]", "numberOfBytes": "1536"},
+		"t_mapping(t_address,t_uint256)": {"encoding": "mapping", "key": "t_address", "label": "mapping(address => uint256)", "numberOfBytes": "32", "value": "t_uint256"},
+		"t_mapping(t_address,t_struct(Position)20_storage)": {"encoding": "mapping", "key": "t_address", "label": "mapping(address => struct Token.Position)", "numberOfBytes": "32", "value": "t_struct(Position)20_storage"},
+		"t_struct(Position)20_storage": {"encoding": "inplace", "label": "struct Token.Position", "numberOfBytes": "64", "members": [
+			{"astId": 17, "contract": "Token.sol:Token", "label": "amount", "offset": 0, "slot": "0", "type": "t_uint256"},
+			{"astId": 19, "contract": "Token.sol:Token", "label": "owner", "offset": 0, "slot": "1", "type": "t_address"}
+		]}
+	}`
+	var storage []map[string]interface{}
+	for i, e := range entries {
+		storage = append(storage, map[string]interface{}{"astId": i + 1, "label": e[0], "slot": e[1], "offset": json.Number(e[2]), "type": e[3], "contract": e[4]})
+	}
+	data, _ := json.Marshal(map[string]interface{}{"storage": storage, "types": json.RawMessage(types)})
+
+	var layout StorageLayout
+	if err := json.Unmarshal(data, &layout); err != nil {
+		t.Fatal(err)
+	}
+	return &layout
+}
+
+func TestStorageLayoutTable(t *testing.T) {
+	layout := storageLayoutJSON(t,
+		[5]string{"owner", "0", "0", "t_address", "Base.sol:Base"},
+		[5]string{"paused", "0", "20", "t_bool", "Base.sol:Base"},
+		[5]string{"positions", "1", "0", "t_mapping(t_address,t_struct(Position)20_storage)", "Token.sol:Token"},
+		[5]string{"last", "2", "0", "t_struct(Position)20_storage", "Token.sol:Token"},
+	)
+
+	table := layout.Table()
+	lines := strings.Split(strings.TrimSpace(table), "\n")
+	if len(lines) != 7 {
+		t.Fatalf("expected 7 lines, got:\n%s", table)
+	}
+	if fields := strings.Fields(lines[2]); strings.Join(fields, " ") != "0 20 1 paused bool Base.sol:Base" {
+		t.Errorf("unexpected row %q", lines[2])
+	}
+	if fields := strings.Fields(lines[6]); strings.Join(fields, " ") != "3 0 20 last.owner address" {
+		t.Errorf("unexpected struct member row %q", lines[6])
+	}
+}
+
+func TestCompareStorageLayouts(t *testing.T) {
+	base := [][5]string{
+		{"owner", "0", "0", "t_address", "Base.sol:Base"},
+		{"paused", "0", "20", "t_bool", "Base.sol:Base"},
+		{"__gap", "1", "0", "t_array(t_uint256)49_storage", "Base.sol:Base"},
+		{"balances", "50", "0", "t_mapping(t_address,t_uint256)", "Token.sol:Token"},
+		{"total", "51", "0", "t_uint256", "Token.sol:Token"},
+	}
+	old := storageLayoutJSON(t, base...)
+
+	tests := []struct {
+		name        string
+		entries     [][5]string
+		renamedFrom map[string]string // previous names of new variables, by label
+		expected    []StorageChangeKind
+	}{
+		{"identical", base, nil, nil},
+		{"appended", append(base[:5:5], [5]string{"cap", "52", "0", "t_uint256", "Token.sol:Token"}), nil, nil},
+		{"gap consumed", [][5]string{base[0], base[1], {"fee", "1", "0", "t_uint256", "Base.sol:Base"}, {"__gap", "2", "0", "t_array(t_uint256)48_storage", "Base.sol:Base"}, base[3], base[4]}, nil, nil},
+		{"gap not shrunk", [][5]string{base[0], base[1], {"fee", "1", "0", "t_uint256", "Base.sol:Base"}, {"__gap", "2", "0", "t_array(t_uint256)49_storage", "Base.sol:Base"}, {"balances", "51", "0", "t_mapping(t_address,t_uint256)", "Token.sol:Token"}, {"total", "52", "0", "t_uint256", "Token.sol:Token"}},
+			nil, []StorageChangeKind{StorageGapMisuse, StorageMoved, StorageMoved}},
+		{"reordered", [][5]string{base[0], base[1], base[2], {"total", "50", "0", "t_uint256", "Token.sol:Token"}, {"balances", "51", "0", "t_mapping(t_address,t_uint256)", "Token.sol:Token"}},
+			nil, []StorageChangeKind{StorageMoved, StorageMoved}},
+		{"removed", base[:4], nil, []StorageChangeKind{StorageRemoved}},
+		{"retyped", [][5]string{base[0], base[1], base[2], base[3], {"total", "51", "0", "t_int256", "Token.sol:Token"}}, nil, []StorageChangeKind{StorageRetyped}},
+		{"shrunk", [][5]string{base[0], base[1], base[2], base[3], {"total", "51", "0", "t_uint128", "Token.sol:Token"}}, nil, []StorageChangeKind{StorageShrunk}},
+		{"renamed", [][5]string{{"admin", "0", "0", "t_contract(IERC20)4", "Base.sol:Base"}, base[1], base[2], base[3], base[4]}, map[string]string{"admin": "owner"}, []StorageChangeKind{StorageRenamed}},
+		// an unrelated variable taking the place of a removed one reads its value
+		{"replaced", [][5]string{base[0], base[1], base[2], base[3], {"fee", "51", "0", "t_uint256", "Token.sol:Token"}}, nil, []StorageChangeKind{StorageRemoved}},
+		{"renamed and moved", [][5]string{base[0], base[1], base[2], {"supply", "50", "0", "t_uint256", "Token.sol:Token"}, {"balances", "51", "0", "t_mapping(t_address,t_uint256)", "Token.sol:Token"}},
+			map[string]string{"supply": "total"}, []StorageChangeKind{StorageMoved, StorageMoved}},
+	}
+	for _, test := range tests {
+		layout := storageLayoutJSON(t, test.entries...)
+		for _, v := range layout.Storage {
+			v.RenamedFrom = test.renamedFrom[v.Label]
+		}
+		changes := CompareStorageLayouts(old, layout)
+		var kinds []StorageChangeKind
+		for _, change := range changes {
+			kinds = append(kinds, change.Kind)
+		}
+		if fmt.Sprint(kinds) != fmt.Sprint(test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, changes)
+		}
+	}
+}
+
+func TestStandardOutputStorageLayoutRenamedFrom(t *testing.T) {
+	data, err := os.ReadFile("testdata/output/upgrade_output.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var output map[string]interface{}
+	if err := json.Unmarshal(data, &output); err != nil {
+		t.Fatal(err)
+	}
+	out, err := newStandardOutput(output, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out.Contracts["Upgradeable.sol"].(map[string]interface{})["Base"].(map[string]interface{})["storageLayout"] = map[string]interface{}{
+		"storage": []interface{}{
+			map[string]interface{}{"astId": 3, "contract": "Upgradeable.sol:Base", "label": "fee", "offset": 0, "slot": "0", "type": "t_uint256"},
+		},
+		"types": map[string]interface{}{
+			"t_uint256": map[string]interface{}{"encoding": "inplace", "label": "uint256", "numberOfBytes": "32"},
+		},
+	}
+	node, _ := out.NodeByID(3)
+	node.(*VariableDeclaration).Documentation = &StructuredDocumentation{Text: "@notice Protocol fee\n@custom:oz-renamed-from rate"}
+
+	layout, err := out.StorageLayout("Upgradeable.sol:Base")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if layout.Storage[0].RenamedFrom != "rate" {
+		t.Fatalf("expected fee to be renamed from rate, got %q", layout.Storage[0].RenamedFrom)
+	}
+
+	old := storageLayoutJSON(t, [5]string{"rate", "0", "0", "t_uint256", "Upgradeable.sol:Base"})
+	if changes := CompareStorageLayouts(old, layout); len(changes) != 1 || changes[0].Kind != StorageRenamed || changes[0].Breaking() {
+		t.Errorf("expected a non-breaking rename, got %v", changes)
+	}
+}
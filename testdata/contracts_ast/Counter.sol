@@ -0,0 +1,14 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+/// @title A simple counter
+contract Counter {
+    uint256 public count;
+
+    event Incremented(address indexed by, uint256 amount);
+
+    function increment(uint256 amount) external {
+        count += amount;
+        emit Incremented(msg.sender, amount);
+    }
+}
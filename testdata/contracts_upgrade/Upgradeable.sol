@@ -0,0 +1,35 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+contract Base {
+    uint256 public fee = 10;
+    address public immutable token;
+
+    constructor(address _token) {
+        token = _token;
+    }
+
+    function kill(address payable recipient) external {
+        selfdestruct(recipient);
+    }
+}
+
+/// @custom:oz-upgrades-unsafe-allow state-variable-immutable
+contract Vault is Base {
+    uint256 public constant MAX = 100;
+    uint256 public immutable cap;
+
+    /// @custom:oz-upgrades-unsafe-allow constructor
+    constructor() Base(msg.sender) {
+        cap = MAX;
+    }
+
+    function forward(address target, bytes calldata data) external {
+        // @custom:oz-upgrades-unsafe-allow delegatecall
+        target.delegatecall(data);
+    }
+
+    function exec(address target, bytes calldata data) external {
+        target.delegatecall(data);
+    }
+}
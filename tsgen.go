@@ -0,0 +1,228 @@
+package gosolc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GenerateTypeScript generates a TypeScript module for the ABI of a contract. The module exports the ABI
+// as a `<name>Abi` constant declared `as const`, so viem and abitype infer the types of calls, events and
+// errors from it, and TypeScript declarations of the contract structs, functions, events and errors:
+//
+//	export const TokenAbi = [...] as const;
+//	export interface TokenFunctions { balanceOf: { inputs: readonly [account: Address]; outputs: readonly [bigint]; ... } }
+//
+// Types follow abitype: integers up to 48 bits are numbers, larger ones bigints, addresses and bytes are
+// 0x-prefixed strings. Overloaded functions, events and errors are keyed by signature instead of name.
+func GenerateTypeScript(name string, abi *ABI) ([]byte, error) {
+	g := &tsGenerator{structs: map[string]string{}}
+	var body bytes.Buffer
+
+	abiJSON, err := json.MarshalIndent(abi, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(&body, "export const %sAbi = %s as const;\n\n", name, abiJSON)
+	fmt.Fprintf(&body, "export type %sAbi = typeof %sAbi;\n\n", name, name)
+
+	if abi.Constructor != nil {
+		inputs, err := g.tuple(abi.Constructor.Inputs)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&body, "export type %sConstructorArgs = %s;\n\n", name, inputs)
+	}
+
+	fmt.Fprintf(&body, "export interface %sFunctions {\n", name)
+	for _, function := range abi.Functions {
+		inputs, err := g.tuple(function.Inputs)
+		if err != nil {
+			return nil, err
+		}
+		outputs, err := g.tuple(function.Outputs)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&body, "  %s: {\n    inputs: %s;\n    outputs: %s;\n    stateMutability: %q;\n  };\n",
+			tsEntryKey(function, abi.Functions), inputs, outputs, function.StateMutability)
+	}
+	body.WriteString("}\n\n")
+
+	for _, group := range []struct {
+		suffix  string
+		entries []*ABIEntry
+	}{
+		{"Events", abi.Events},
+		{"Errors", abi.Errors},
+	} {
+		fmt.Fprintf(&body, "export interface %s%s {\n", name, group.suffix)
+		for _, entry := range group.entries {
+			fields, err := g.object(entry.Inputs, group.suffix == "Events")
+			if err != nil {
+				return nil, err
+			}
+			fmt.Fprintf(&body, "  %s: %s;\n", tsEntryKey(entry, group.entries), fields)
+		}
+		body.WriteString("}\n\n")
+	}
+
+	var src bytes.Buffer
+	src.WriteString("// Code generated by gosolc. DO NOT EDIT.\n\n")
+	src.WriteString("export type Address = `0x${string}`;\n")
+	src.WriteString("export type Hex = `0x${string}`;\n\n")
+	structNames := make([]string, 0, len(g.structs))
+	for structName := range g.structs {
+		structNames = append(structNames, structName)
+	}
+	sort.Strings(structNames)
+	for _, structName := range structNames {
+		src.WriteString(g.structs[structName])
+	}
+	src.Write(bytes.TrimRight(body.Bytes(), "\n"))
+	src.WriteString("\n")
+	return src.Bytes(), nil
+}
+
+// tsGenerator accumulates the struct declarations of a TypeScript module.
+type tsGenerator struct {
+	structs map[string]string // interface declarations keyed by name
+}
+
+// tuple returns the readonly labeled tuple type of parameters, e.g. readonly [to: Address, value: bigint].
+func (g *tsGenerator) tuple(params []ABIParameter) (string, error) {
+	var elements []string
+	for i, param := range params {
+		t, err := g.tsType(param)
+		if err != nil {
+			return "", err
+		}
+		label := param.Name
+		if label == "" {
+			label = fmt.Sprintf("arg%d", i)
+		}
+		elements = append(elements, label+": "+t)
+	}
+	return "readonly [" + strings.Join(elements, ", ") + "]", nil
+}
+
+// object returns the object type of named parameters. Indexed dynamic event parameters are logged as hashes.
+func (g *tsGenerator) object(params []ABIParameter, event bool) (string, error) {
+	var fields []string
+	for i, param := range params {
+		t, err := g.tsType(param)
+		if err != nil {
+			return "", err
+		}
+		if event && param.Indexed {
+			abiType, err := param.ABIType()
+			if err != nil {
+				return "", err
+			}
+			if abiType.IsDynamic() || abiType.Kind == ArrayKind || abiType.Kind == TupleKind {
+				t = "Hex"
+			}
+		}
+		key := param.Name
+		if key == "" {
+			key = fmt.Sprintf("arg%d", i)
+		}
+		fields = append(fields, key+": "+t)
+	}
+	if len(fields) == 0 {
+		return "{}", nil
+	}
+	return "{ " + strings.Join(fields, "; ") + " }", nil
+}
+
+// tsType returns the TypeScript type of an ABI parameter, declaring interfaces for structs.
+func (g *tsGenerator) tsType(param ABIParameter) (string, error) {
+	if i := strings.LastIndex(param.Type, "["); i >= 0 && strings.HasSuffix(param.Type, "]") {
+		elem := param
+		elem.Type = param.Type[:i]
+		if j := strings.LastIndex(param.InternalType, "["); j >= 0 {
+			elem.InternalType = param.InternalType[:j]
+		}
+		elemType, err := g.tsType(elem)
+		if err != nil {
+			return "", err
+		}
+		length := strings.TrimSuffix(param.Type[i+1:], "]")
+		if n, err := strconv.Atoi(length); err == nil && n <= 8 {
+			items := make([]string, n)
+			for k := range items {
+				items[k] = elemType
+			}
+			return "readonly [" + strings.Join(items, ", ") + "]", nil
+		}
+		return "readonly " + tsParenthesize(elemType) + "[]", nil
+	}
+
+	t, err := param.ABIType()
+	if err != nil {
+		return "", err
+	}
+
+	switch t.Kind {
+	case UintKind, IntKind:
+		if t.Size <= 48 {
+			return "number", nil
+		}
+		return "bigint", nil
+	case AddressKind:
+		return "Address", nil
+	case BoolKind:
+		return "boolean", nil
+	case FixedBytesKind, FunctionKind, BytesKind:
+		return "Hex", nil
+	case StringKind:
+		return "string", nil
+	case TupleKind:
+		fields, err := g.object(param.Components, false)
+		if err != nil {
+			return "", err
+		}
+		if !strings.HasPrefix(param.InternalType, "struct ") {
+			return fields, nil
+		}
+		solidityName := strings.TrimPrefix(param.InternalType, "struct ")
+		name := strings.ReplaceAll(solidityName, ".", "_")
+		g.structs[name] = fmt.Sprintf("/** Solidity struct %s */\nexport interface %s %s\n\n", solidityName, name, fields)
+		return name, nil
+	}
+	return "", fmt.Errorf("unsupported type %s", param.Type)
+}
+
+// tsParenthesize wraps readonly types in parentheses so they can take an array suffix.
+func tsParenthesize(t string) string {
+	if strings.HasPrefix(t, "readonly ") {
+		return "(" + t + ")"
+	}
+	return t
+}
+
+// tsEntryKey returns the property key of an entry: its name, or its signature if the name is overloaded.
+func tsEntryKey(entry *ABIEntry, entries []*ABIEntry) string {
+	for _, other := range entries {
+		if other != entry && other.Name == entry.Name {
+			return strconv.Quote(entry.Signature())
+		}
+	}
+	return entry.Name
+}
+
+// contractTypeScript generates the TypeScript module of an untyped contract output.
+func contractTypeScript(name string, contract interface{}) ([]byte, error) {
+	contractOutput, ok := contract.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid contract output")
+	}
+	abi, err := contractABI(contractOutput)
+	if err != nil {
+		return nil, err
+	}
+	return GenerateTypeScript(name, abi)
+}
@@ -0,0 +1,34 @@
+package gosolc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateTypeScript(t *testing.T) {
+	contracts := loadTestOutput(t, "abi_output.json")
+	abi, err := contracts.ContractABI("Token.sol:Token")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src, err := GenerateTypeScript("Token", abi)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		"] as const;\n",
+		"export interface Multicall3_Call3 { target: Address; allowFailure: boolean; callData: Hex }",
+		"export type TokenConstructorArgs = readonly [name_: string, symbol_: string];",
+		"inputs: readonly [calls: readonly Multicall3_Call3[]];",
+		`"safeTransferFrom(address,address,uint256,bytes)": {`,
+		"outputs: readonly [arg0: number];", // decimals() returns uint8
+		"Transfer: { from: Address; to: Address; value: bigint };",
+		"ERC20InsufficientBalance: { sender: Address; balance: bigint; needed: bigint };",
+	} {
+		if !strings.Contains(string(src), expected) {
+			t.Errorf("generated TypeScript is missing %q", expected)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package gosolc
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Address is a 20 byte Ethereum address.
+type Address [20]byte
+
+// Hash is a 32 byte hash, e.g. an event topic.
+type Hash [32]byte
+
+// HexToAddress parses a hex address, with or without 0x prefix.
+func HexToAddress(s string) (Address, error) {
+	var a Address
+	b, err := decodeHex(s)
+	if err != nil {
+		return a, fmt.Errorf("invalid address %q: %v", s, err)
+	}
+	if len(b) != len(a) {
+		return a, fmt.Errorf("invalid address %q: expected 20 bytes, got %d", s, len(b))
+	}
+	copy(a[:], b)
+	return a, nil
+}
+
+// Hex returns the EIP-55 checksummed hex representation of the address.
+func (a Address) Hex() string {
+	lower := hex.EncodeToString(a[:])
+	hash := hex.EncodeToString(keccak256([]byte(lower)))
+
+	checksummed := []byte(lower)
+	for i, c := range checksummed {
+		if c >= 'a' && hash[i] >= '8' {
+			checksummed[i] = c - 'a' + 'A'
+		}
+	}
+	return "0x" + string(checksummed)
+}
+
+// String implements fmt.Stringer.
+func (a Address) String() string {
+	return a.Hex()
+}
+
+// HexToHash parses a 32 byte hex hash, with or without 0x prefix.
+func HexToHash(s string) (Hash, error) {
+	var h Hash
+	b, err := decodeHex(s)
+	if err != nil {
+		return h, fmt.Errorf("invalid hash %q: %v", s, err)
+	}
+	if len(b) != len(h) {
+		return h, fmt.Errorf("invalid hash %q: expected 32 bytes, got %d", s, len(b))
+	}
+	copy(h[:], b)
+	return h, nil
+}
+
+// Hex returns the 0x prefixed hex representation of the hash.
+func (h Hash) Hex() string {
+	return "0x" + hex.EncodeToString(h[:])
+}
+
+// String implements fmt.Stringer.
+func (h Hash) String() string {
+	return h.Hex()
+}
+
+// decodeHex decodes a hex string with an optional 0x prefix.
+func decodeHex(s string) ([]byte, error) {
+	s = strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(s), "0x"), "0X")
+	return hex.DecodeString(s)
+}
@@ -0,0 +1,257 @@
+package gosolc
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// UpgradeViolationKind is the kind of an upgrade safety violation. The values are the names accepted by the
+// `@custom:oz-upgrades-unsafe-allow` annotation.
+type UpgradeViolationKind string
+
+const (
+	UpgradeConstructor       UpgradeViolationKind = "constructor"               // Constructor with logic or parameters
+	UpgradeSelfdestruct      UpgradeViolationKind = "selfdestruct"              // selfdestruct call
+	UpgradeDelegatecall      UpgradeViolationKind = "delegatecall"              // delegatecall
+	UpgradeImmutable         UpgradeViolationKind = "state-variable-immutable"  // Immutable state variable
+	UpgradeStateAssignment   UpgradeViolationKind = "state-variable-assignment" // State variable with an initial value
+	UpgradeExternalLibraries UpgradeViolationKind = "external-library-linking"  // Linked external library
+)
+
+// UpgradeViolation is a construct that is unsafe in the implementation contract of a proxy.
+type UpgradeViolation struct {
+	Kind     UpgradeViolationKind
+	Contract string          // Fully qualified name of the contract the construct is declared in
+	Message  string          // Description of the violation
+	Location *SourceLocation // Location of the offending node, nil if unknown
+}
+
+// String returns the violation in the "file:line:column: kind: message" format.
+func (v *UpgradeViolation) String() string {
+	if v.Location == nil {
+		return fmt.Sprintf("%s: %s: %s", v.Contract, v.Kind, v.Message)
+	}
+	return fmt.Sprintf("%v: %s: %s", v.Location, v.Kind, v.Message)
+}
+
+// upgradesAllowRegexp matches the annotations that allow unsafe constructs, in NatSpec or plain comments:
+// "@custom:oz-upgrades-unsafe-allow constructor delegatecall".
+var upgradesAllowRegexp = regexp.MustCompile(`@(?:custom:)?oz-upgrades-unsafe-allow(?:-reachable)?((?:[ \t]+[a-z-]+)+)`)
+
+// yulUnsafeCallRegexp matches calls to delegatecall and selfdestruct in the JSON of a Yul AST.
+var yulUnsafeCallRegexp = regexp.MustCompile(`"name"\s*:\s*"(delegatecall|selfdestruct)"`)
+
+// parseUpgradesAllow returns the violation kinds allowed by the annotations of comments.
+func parseUpgradesAllow(comments ...string) map[UpgradeViolationKind]bool {
+	allowed := map[UpgradeViolationKind]bool{}
+	for _, comment := range comments {
+		for _, match := range upgradesAllowRegexp.FindAllStringSubmatch(comment, -1) {
+			for _, kind := range strings.Fields(match[1]) {
+				allowed[UpgradeViolationKind(kind)] = true
+			}
+		}
+	}
+	return allowed
+}
+
+// ValidateUpgradeSafety checks that a contract can be used as the implementation of a proxy: its code and
+// the code of its base contracts must not have constructors with logic or parameters, selfdestruct or
+// delegatecall, immutable variables or state variables with initial values, and it must not link external
+// libraries. Constructs annotated with `/// @custom:oz-upgrades-unsafe-allow <kinds>` are allowed, the annotation
+// applying to the whole contract, a function or a state variable when in their NatSpec, and to the next line
+// when in a plain comment. Violations are returned in source order, base contracts first.
+func (out *StandardOutput) ValidateUpgradeSafety(fqName string) ([]*UpgradeViolation, error) {
+	fqName, _, err := out.Contracts.resolveContract(fqName)
+	if err != nil {
+		return nil, err
+	}
+	file, name := splitFullyQualifiedName(fqName)
+	source, ok := out.Sources[file]
+	if !ok || source.AST == nil {
+		return nil, fmt.Errorf("AST of %s not found in compiler output", file)
+	}
+
+	var contract *ContractDefinition
+	for _, def := range FindNodes[*ContractDefinition](source.AST) {
+		if def.Name == name {
+			contract = def
+		}
+	}
+	if contract == nil {
+		return nil, fmt.Errorf("contract %s not found in AST of %s", name, file)
+	}
+
+	v := &upgradeValidator{out: out}
+	for i := len(contract.LinearizedBaseContracts) - 1; i >= 0; i-- {
+		node, ok := out.NodeByID(contract.LinearizedBaseContracts[i])
+		def, isContract := node.(*ContractDefinition)
+		if !ok || !isContract {
+			return nil, fmt.Errorf("base contract %d of %s not found in AST", contract.LinearizedBaseContracts[i], fqName)
+		}
+		if err := v.checkContract(def); err != nil {
+			return nil, err
+		}
+	}
+
+	references, deployedReferences, err := out.Contracts.GetLinkReferences(fqName)
+	if err != nil {
+		return nil, err
+	}
+	libraries := map[string]bool{}
+	for _, refs := range []LinkReferences{references, deployedReferences} {
+		for libraryFile, fileReferences := range refs {
+			for library := range fileReferences {
+				libraries[libraryFile+":"+library] = true
+			}
+		}
+	}
+	names := make([]string, 0, len(libraries))
+	for library := range libraries {
+		names = append(names, library)
+	}
+	sort.Strings(names)
+	for _, library := range names {
+		v.report(contract, UpgradeExternalLibraries, fqName, parseUpgradesAllow(documentation(contract.Documentation)),
+			fmt.Sprintf("linked external library %s is not deployed with the proxy", library))
+	}
+
+	return v.violations, v.err
+}
+
+// upgradeValidator collects the violations of ValidateUpgradeSafety.
+type upgradeValidator struct {
+	out        *StandardOutput
+	violations []*UpgradeViolation
+	err        error
+}
+
+// checkContract checks the declarations of a contract, not including its base contracts.
+func (v *upgradeValidator) checkContract(def *ContractDefinition) error {
+	fqName, err := v.out.contractName(def)
+	if err != nil {
+		return err
+	}
+	contractDoc := documentation(def.Documentation)
+
+	for _, node := range def.Nodes {
+		switch node := node.(type) {
+		case *VariableDeclaration:
+			if !node.StateVariable {
+				continue
+			}
+			allowed := parseUpgradesAllow(contractDoc, documentation(node.Documentation))
+			switch {
+			case node.Mutability == "immutable":
+				v.report(node, UpgradeImmutable, fqName, allowed, fmt.Sprintf("state variable %s is immutable, its value is stored in the implementation code", node.Name))
+			case node.Mutability == "mutable" && node.Value != nil:
+				v.report(node, UpgradeStateAssignment, fqName, allowed, fmt.Sprintf("state variable %s has an initial value, which is only set in the implementation storage", node.Name))
+			}
+
+		case *FunctionDefinition:
+			allowed := parseUpgradesAllow(contractDoc, documentation(node.Documentation))
+			if node.Kind == "constructor" && v.constructorHasLogic(fqName, node) {
+				v.report(node, UpgradeConstructor, fqName, allowed, "constructor is not run in the context of the proxy, use an initializer")
+			}
+			if node.Body != nil {
+				v.checkCode(node.Body, fqName, allowed)
+			}
+
+		case *ModifierDefinition:
+			if node.Body != nil {
+				v.checkCode(node.Body, fqName, parseUpgradesAllow(contractDoc, documentation(node.Documentation)))
+			}
+		}
+	}
+	return v.err
+}
+
+// constructorHasLogic reports whether a constructor has statements, modifiers or parameters (in the ABI).
+func (v *upgradeValidator) constructorHasLogic(fqName string, constructor *FunctionDefinition) bool {
+	if constructor.Body != nil && len(constructor.Body.Statements) > 0 {
+		return true
+	}
+	for _, modifier := range constructor.Modifiers {
+		if modifier.Kind != "baseConstructorSpecifier" {
+			return true
+		}
+	}
+	if abi, err := v.out.Contracts.ContractABI(fqName); err == nil && abi.Constructor != nil {
+		return len(abi.Constructor.Inputs) > 0
+	}
+	return constructor.Parameters != nil && len(constructor.Parameters.Parameters) > 0
+}
+
+// checkCode reports the selfdestruct and delegatecall calls of a function or modifier body.
+func (v *upgradeValidator) checkCode(body Node, fqName string, allowed map[UpgradeViolationKind]bool) {
+	Inspect(body, func(node Node) bool {
+		switch node := node.(type) {
+		case *FunctionCall:
+			if id, ok := node.Expression.(*Identifier); ok && (id.Name == "selfdestruct" || id.Name == "suicide") {
+				v.report(node, UpgradeSelfdestruct, fqName, allowed, "selfdestruct can destroy the implementation and brick the proxies using it")
+			}
+		case *MemberAccess:
+			if node.MemberName == "delegatecall" {
+				v.report(node, UpgradeDelegatecall, fqName, allowed, "delegatecall from the implementation can run selfdestruct in its context")
+			}
+		case *InlineAssembly:
+			for _, match := range yulUnsafeCallRegexp.FindAllSubmatch(node.AST, -1) {
+				kind := UpgradeViolationKind(match[1])
+				v.report(node, kind, fqName, allowed, fmt.Sprintf("inline assembly uses %s", kind))
+			}
+		}
+		return true
+	})
+}
+
+// report records a violation unless it is allowed, either by the given annotations or by a comment on the
+// lines preceding the node.
+func (v *upgradeValidator) report(node Node, kind UpgradeViolationKind, fqName string, allowed map[UpgradeViolationKind]bool, message string) {
+	if allowed[kind] {
+		return
+	}
+	location, err := v.out.NodeLocation(node)
+	if err != nil {
+		if v.err == nil {
+			v.err = err
+		}
+		return
+	}
+	if parseUpgradesAllow(v.out.Sources[location.File].precedingComments(location.Start))[kind] {
+		return
+	}
+	v.violations = append(v.violations, &UpgradeViolation{Kind: kind, Contract: fqName, Message: message, Location: location})
+}
+
+// contractName returns the fully qualified name of a contract definition.
+func (out *StandardOutput) contractName(def *ContractDefinition) (string, error) {
+	for name, source := range out.Sources {
+		if source.AST != nil && source.AST.ID == def.Scope {
+			return name + ":" + def.Name, nil
+		}
+	}
+	return "", fmt.Errorf("source unit of contract %s not found", def.Name)
+}
+
+// precedingComments returns the comment lines directly above the line of a byte offset of the source.
+func (s *SourceOutput) precedingComments(offset int) string {
+	lines := strings.Split(s.Content[:strings.LastIndex(s.Content[:offset], "\n")+1], "\n")
+	var comments []string
+	for i := len(lines) - 2; i >= 0; i-- {
+		line := strings.TrimSpace(lines[i])
+		if !strings.HasPrefix(line, "//") && !strings.HasPrefix(line, "/*") && !strings.HasPrefix(line, "*") {
+			break
+		}
+		comments = append(comments, line)
+	}
+	return strings.Join(comments, "\n")
+}
+
+// documentation returns the text of a NatSpec comment, or "" for nil.
+func documentation(doc *StructuredDocumentation) string {
+	if doc == nil {
+		return ""
+	}
+	return doc.Text
+}
@@ -0,0 +1,60 @@
+package gosolc
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+func TestValidateUpgradeSafety(t *testing.T) {
+	data, err := os.ReadFile("testdata/output/upgrade_output.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile("testdata/contracts_upgrade/Upgradeable.sol")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var output map[string]interface{}
+	if err := json.Unmarshal(data, &output); err != nil {
+		t.Fatal(err)
+	}
+	out, err := newStandardOutput(output, map[string]string{"Upgradeable.sol": string(content)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	violations, err := out.ValidateUpgradeSafety("Vault")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the immutable, the constructor and the delegatecall of forward are allowed in Vault
+	expected := []struct {
+		kind     UpgradeViolationKind
+		contract string
+		line     int
+	}{
+		{UpgradeStateAssignment, "Upgradeable.sol:Base", 5},
+		{UpgradeImmutable, "Upgradeable.sol:Base", 6},
+		{UpgradeConstructor, "Upgradeable.sol:Base", 8},
+		{UpgradeSelfdestruct, "Upgradeable.sol:Base", 13},
+		{UpgradeDelegatecall, "Upgradeable.sol:Vault", 33},
+	}
+	if len(violations) != len(expected) {
+		t.Fatalf("expected %d violations, got %v", len(expected), violations)
+	}
+	for i, e := range expected {
+		v := violations[i]
+		if v.Kind != e.kind || v.Contract != e.contract || v.Location.Line != e.line {
+			t.Errorf("violation %d: expected %s in %s at line %d, got %v", i, e.kind, e.contract, e.line, v)
+		}
+	}
+	if s := violations[4].String(); s != "Upgradeable.sol:33:9: delegatecall: delegatecall from the implementation can run selfdestruct in its context" {
+		t.Errorf("unexpected string %q", s)
+	}
+
+	allowed := parseUpgradesAllow("@custom:oz-upgrades-unsafe-allow constructor state-variable-immutable\n@notice x")
+	if len(allowed) != 2 || !allowed[UpgradeConstructor] || !allowed[UpgradeImmutable] {
+		t.Errorf("unexpected allowed kinds %v", allowed)
+	}
+}
@@ -0,0 +1,214 @@
+package gosolc
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// StandardJSONInput returns the standard JSON input compiling the source of a contract, exactly as passed to
+// solc by Compile, with unescaped source contents. If the source matches a CompilerOverride, it is the input
+// of the solc invocation compiling it: the override configuration and the sources sharing it.
+func (c Compiler) StandardJSONInput(fqName string) ([]byte, error) {
+	input, _, err := c.contractInput(fqName)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(input, "", "  ")
+}
+
+// contractInput returns the standard JSON input compiling the source of fqName and the configuration it uses.
+func (c Compiler) contractInput(fqName string) (map[string]interface{}, *CompilerConfig, error) {
+	if !strings.Contains(fqName, ":") {
+		return nil, nil, fmt.Errorf("contract name %s is not fully qualified (file.sol:Name)", fqName)
+	}
+	file, _ := splitFullyQualifiedName(fqName)
+	if _, ok := c.Sources[file]; !ok {
+		return nil, nil, fmt.Errorf("source %s not found", file)
+	}
+
+	contents, err := sourceContents(c.Sources)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	config := c.CompilerConfig
+	names := make([]string, 0, len(contents))
+	for name := range contents {
+		names = append(names, name)
+	}
+
+	if len(c.CompilerConfig.Overrides) > 0 {
+		infos, err := analyzeSources(contents)
+		if err != nil {
+			return nil, nil, err
+		}
+		groups, err := c.CompilerConfig.groupSources(names, infos)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, group := range groups {
+			if slices.Contains(group.roots, file) {
+				config = c.CompilerConfig.sourceConfig(group.override)
+				names = group.sources
+				break
+			}
+		}
+	}
+
+	sources := make(map[string]map[string]string, len(names))
+	for _, name := range names {
+		sources[name] = map[string]string{"content": contents[name]}
+	}
+	return Compiler{CompilerConfig: config}.compilerInput(sources), config, nil
+}
+
+// EtherscanVerification is the payload of the Etherscan verifysourcecode API using the
+// "solidity-standard-json-input" code format.
+type EtherscanVerification struct {
+	ContractName         string // Fully qualified name, e.g. "Token.sol:Token"
+	CompilerVersion      string // Compiler version, e.g. "v0.8.29+commit.ab55807c"
+	SourceCode           string // Standard JSON input
+	ConstructorArguments string // ABI-encoded constructor arguments (hex, without 0x)
+	OptimizationUsed     bool
+	Runs                 int
+	EVMVersion           string
+}
+
+// EtherscanVerification returns the Etherscan verification payload of a contract.
+// The compiler version is read from the solc-js build in use; constructorArgs are the
+// ABI-encoded constructor arguments appended to the deployed creation bytecode.
+func (c Compiler) EtherscanVerification(fqName string, constructorArgs []byte) (*EtherscanVerification, error) {
+	input, config, err := c.contractInput(fqName)
+	if err != nil {
+		return nil, err
+	}
+	sourceCode, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal compiler input JSON: %v", err)
+	}
+
+	longVersion, err := c.LongVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	verification := &EtherscanVerification{
+		ContractName:         fqName,
+		CompilerVersion:      "v" + etherscanCompilerVersion(longVersion),
+		SourceCode:           string(sourceCode),
+		ConstructorArguments: hex.EncodeToString(constructorArgs),
+		EVMVersion:           config.EVMVersion,
+	}
+	if config.SolcOptimizer != nil {
+		verification.OptimizationUsed = config.SolcOptimizer.Enabled
+		verification.Runs = config.SolcOptimizer.Runs
+	}
+	return verification, nil
+}
+
+// etherscanCompilerVersion strips the platform suffix of a long version: "0.8.29+commit.ab55807c.Emscripten.clang"
+// becomes "0.8.29+commit.ab55807c".
+func etherscanCompilerVersion(longVersion string) string {
+	version, build, ok := strings.Cut(longVersion, "+")
+	if !ok {
+		return longVersion
+	}
+	parts := strings.Split(build, ".")
+	if len(parts) > 2 {
+		parts = parts[:2]
+	}
+	return version + "+" + strings.Join(parts, ".")
+}
+
+// Form returns the form values of the verifysourcecode request for the contract deployed at address.
+// The API key ("apikey") and chain id ("chainid") are left to the caller.
+func (v *EtherscanVerification) Form(address Address) url.Values {
+	optimizationUsed := "0"
+	if v.OptimizationUsed {
+		optimizationUsed = "1"
+	}
+	form := url.Values{}
+	form.Set("module", "contract")
+	form.Set("action", "verifysourcecode")
+	form.Set("codeformat", "solidity-standard-json-input")
+	form.Set("contractaddress", address.Hex())
+	form.Set("contractname", v.ContractName)
+	form.Set("compilerversion", v.CompilerVersion)
+	form.Set("sourceCode", v.SourceCode)
+	form.Set("constructorArguements", v.ConstructorArguments) // sic
+	form.Set("optimizationUsed", optimizationUsed)
+	form.Set("runs", strconv.Itoa(v.Runs))
+	if v.EVMVersion != "" {
+		form.Set("evmversion", v.EVMVersion)
+	}
+	return form
+}
+
+// SourcifyBundle holds the files verifying a contract on Sourcify: its metadata.json and the sources it lists.
+type SourcifyBundle struct {
+	Metadata []byte            // metadata.json, as emitted by solc
+	Sources  map[string]string // Source contents keyed by source unit name
+}
+
+// SourcifyBundle returns the Sourcify bundle of a compiled contract. The sources listed in its metadata
+// are taken from the compiler and checked against the keccak256 hashes recorded in the metadata.
+func (c Compiler) SourcifyBundle(contracts CompilerOutput, fqName string) (*SourcifyBundle, error) {
+	contract, err := contracts.Contract(fqName)
+	if err != nil {
+		return nil, err
+	}
+	metadataJSON, ok := contract["metadata"].(string)
+	if !ok || metadataJSON == "" {
+		return nil, fmt.Errorf("metadata of contract %s not found in compiler output", fqName)
+	}
+
+	metadata, err := ParseContractMetadata([]byte(metadataJSON))
+	if err != nil {
+		return nil, fmt.Errorf("contract %s: %v", fqName, err)
+	}
+
+	contents, err := sourceContents(c.Sources)
+	if err != nil {
+		return nil, err
+	}
+	resolved, err := metadata.ResolveSources(contents)
+	if err != nil {
+		return nil, fmt.Errorf("contract %s: %v", fqName, err)
+	}
+
+	bundle := &SourcifyBundle{Metadata: []byte(metadataJSON), Sources: resolved}
+	return bundle, nil
+}
+
+// Files returns the files of the bundle keyed by path, as uploaded to Sourcify.
+func (b *SourcifyBundle) Files() map[string][]byte {
+	files := map[string][]byte{"metadata.json": b.Metadata}
+	for name, content := range b.Sources {
+		files[name] = []byte(content)
+	}
+	return files
+}
+
+// WriteDir writes the files of the bundle to dir, creating the directories of nested source unit names.
+func (b *SourcifyBundle) WriteDir(dir string) error {
+	for name, content := range b.Files() {
+		if !filepath.IsLocal(name) {
+			return fmt.Errorf("source unit name %s is not a local path", name)
+		}
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("failed to create directory for %s: %w", name, err)
+		}
+		if err := os.WriteFile(path, content, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+	return nil
+}
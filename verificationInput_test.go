@@ -0,0 +1,119 @@
+package gosolc
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStandardJSONInput(t *testing.T) {
+	sources := map[string]string{
+		"Token.sol":  "// SPDX-License-Identifier: MIT\npragma solidity ^0.8.0;\nimport \"./Lib.sol\";\ncontract Token { string s = 'it\\'s'; }\n",
+		"Lib.sol":    "pragma solidity ^0.8.0;\nlibrary Lib {}\n",
+		"Legacy.sol": "pragma solidity ^0.8.0;\ncontract Legacy {}\n",
+	}
+	config := NewCompilerConfig("paris", true, 1000)
+	config.Overrides = []*CompilerOverride{NewCompilerOverride("Legacy.sol", NewCompilerConfig("london", false, 0))}
+	c, err := NewCompilerFromSources(sources, config, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := c.StandardJSONInput("Token.sol:Token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var input struct {
+		Language string                       `json:"language"`
+		Sources  map[string]map[string]string `json:"sources"`
+		Settings struct {
+			EVMVersion string              `json:"evmVersion"`
+			Optimizer  SolcOptimizerConfig `json:"optimizer"`
+		} `json:"settings"`
+	}
+	if err := json.Unmarshal(data, &input); err != nil {
+		t.Fatal(err)
+	}
+	if input.Language != "Solidity" || len(input.Sources) != 2 || input.Sources["Token.sol"]["content"] != sources["Token.sol"] {
+		t.Errorf("unexpected sources %v", input.Sources)
+	}
+	if input.Settings.EVMVersion != "paris" || !input.Settings.Optimizer.Enabled || input.Settings.Optimizer.Runs != 1000 {
+		t.Errorf("unexpected settings %+v", input.Settings)
+	}
+
+	// overridden sources are compiled by their own solc invocation
+	data, err = c.StandardJSONInput("Legacy.sol:Legacy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	input.Sources = nil
+	if err := json.Unmarshal(data, &input); err != nil {
+		t.Fatal(err)
+	}
+	if len(input.Sources) != 1 || input.Settings.EVMVersion != "london" {
+		t.Errorf("expected the override input, got %s", data)
+	}
+
+	if _, err := c.StandardJSONInput("Token"); err == nil {
+		t.Error("expected an error for a bare contract name")
+	}
+}
+
+func TestSourcifyBundle(t *testing.T) {
+	sources := map[string]string{"Token.sol": "contract Token {}\n"}
+	c, err := NewCompilerFromSources(sources, NewCompilerConfig("cancun", false, 0), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	metadata := `{"compiler":{"version":"0.8.29+commit.ab55807c"},"language":"Solidity","sources":{"Token.sol":{"keccak256":"0x` +
+		hex.EncodeToString(keccak256([]byte(sources["Token.sol"]))) + `","urls":[]}},"version":1}`
+	contracts := CompilerOutput{"Token.sol": map[string]interface{}{"Token": map[string]interface{}{"metadata": metadata}}}
+
+	bundle, err := c.SourcifyBundle(contracts, "Token.sol:Token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := bundle.Files()
+	if string(files["metadata.json"]) != metadata || string(files["Token.sol"]) != sources["Token.sol"] {
+		t.Errorf("unexpected files %v", files)
+	}
+
+	dir := t.TempDir()
+	if err := bundle.WriteDir(dir); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "Token.sol")); err != nil || string(data) != sources["Token.sol"] {
+		t.Errorf("unexpected Token.sol %q (%v)", data, err)
+	}
+
+	// the source changed after compilation
+	c.Sources["Token.sol"]["content"] = "contract Token { }"
+	if _, err := c.SourcifyBundle(contracts, "Token.sol:Token"); err == nil || !strings.Contains(err.Error(), "does not match") {
+		t.Errorf("expected a changed source error, got %v", err)
+	}
+}
+
+func TestEtherscanCompilerVersion(t *testing.T) {
+	if v := etherscanCompilerVersion("0.8.29+commit.ab55807c.Emscripten.clang"); v != "0.8.29+commit.ab55807c" {
+		t.Errorf("unexpected version %s", v)
+	}
+
+	if solcJS_0_8_29 == "" {
+		t.Skip("embedded soljson is not available")
+	}
+	c, err := NewCompilerFromSources(map[string]string{"Token.sol": "contract Token {}\n"}, NewCompilerConfig("cancun", true, 200), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	verification, err := c.EtherscanVerification("Token.sol:Token", []byte{1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verification.CompilerVersion != "v0.8.29+commit.ab55807c" || verification.ConstructorArguments != "01" || !verification.OptimizationUsed {
+		t.Errorf("unexpected verification %+v", verification)
+	}
+}
@@ -0,0 +1,175 @@
+package gosolc
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// VerificationStatus is the outcome of comparing deployed bytecode with compiled bytecode.
+type VerificationStatus string
+
+const (
+	FullMatch    VerificationStatus = "full"     // Code and metadata trailer match
+	PartialMatch VerificationStatus = "partial"  // Code matches, the metadata trailer differs (e.g. different sources comments or settings)
+	Mismatch     VerificationStatus = "mismatch" // Code differs
+)
+
+// VerificationResult is the result of VerifyDeployed.
+type VerificationResult struct {
+	Status          VerificationStatus
+	FirstDifference int               // Offset of the first differing byte of the code, -1 if the code matches
+	Libraries       map[string]string // Addresses found at the placeholders of unlinked libraries, by fully qualified name
+	Immutables      map[string]string // Values (hex) of immutable variables, by AST id of their declaration
+}
+
+// VerifyDeployed compares the runtime bytecode of a deployed contract (hex) with its compiled deployed bytecode.
+// The metadata trailers are compared separately, positions of immutable variables (immutableReferences) and
+// of unlinked libraries are masked, as well as the address a library embeds at deployment.
+func (contracts CompilerOutput) VerifyDeployed(fqName, onchainRuntimeHex string) (*VerificationResult, error) {
+	onchain, err := decodeHex(onchainRuntimeHex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid on-chain bytecode: %v", err)
+	}
+	return contracts.verifyDeployed(fqName, onchain)
+}
+
+// VerifyDeployedAt fetches the code at address with eth_getCode and verifies it with VerifyDeployed.
+func (contracts CompilerOutput) VerifyDeployedAt(ctx context.Context, client *RPCClient, fqName string, address Address) (*VerificationResult, error) {
+	code, err := client.GetCode(ctx, address, "latest")
+	if err != nil {
+		return nil, err
+	}
+	if len(code) == 0 {
+		return nil, fmt.Errorf("no code at %s", address)
+	}
+	return contracts.verifyDeployed(fqName, code)
+}
+
+// maskedRange is a range of the runtime code whose content is set at link or deployment time.
+type maskedRange struct {
+	start, length int
+	kind, name    string // "library", "immutable" or "deployment", and what is masked
+}
+
+func (contracts CompilerOutput) verifyDeployed(fqName string, onchain []byte) (*VerificationResult, error) {
+	contract, err := contracts.Contract(fqName)
+	if err != nil {
+		return nil, err
+	}
+	_, deployedBytecode, err := contractBytecodes(contract)
+	if err != nil {
+		return nil, err
+	}
+	compiled, err := decodeBytecode(deployedBytecode)
+	if err != nil {
+		return nil, err
+	}
+	if len(compiled) == 0 {
+		return nil, fmt.Errorf("contract %s has no runtime bytecode (abstract contract or interface)", fqName)
+	}
+
+	masks, err := contracts.maskedRanges(fqName, contract, compiled)
+	if err != nil {
+		return nil, err
+	}
+
+	expected := SplitRuntimeBytecode(compiled)
+	actual := SplitRuntimeBytecode(onchain)
+	result := &VerificationResult{
+		Status:          FullMatch,
+		FirstDifference: -1,
+		Libraries:       map[string]string{},
+		Immutables:      map[string]string{},
+	}
+
+	masked := make([]bool, len(expected.RuntimeCode))
+	for _, m := range masks {
+		if m.start+m.length > len(masked) {
+			return nil, fmt.Errorf("%s %s at %d is outside of the runtime code", m.kind, m.name, m.start)
+		}
+		for i := m.start; i < m.start+m.length; i++ {
+			masked[i] = true
+		}
+		if m.start+m.length <= len(actual.RuntimeCode) {
+			value := actual.RuntimeCode[m.start : m.start+m.length]
+			switch m.kind {
+			case "library":
+				address := Address{}
+				copy(address[:], value)
+				result.Libraries[m.name] = address.Hex()
+			case "immutable":
+				result.Immutables[m.name] = "0x" + hex.EncodeToString(value)
+			}
+		}
+	}
+
+	for i := 0; i < len(expected.RuntimeCode) && i < len(actual.RuntimeCode); i++ {
+		if !masked[i] && expected.RuntimeCode[i] != actual.RuntimeCode[i] {
+			result.FirstDifference = i
+			break
+		}
+	}
+	if result.FirstDifference < 0 && len(expected.RuntimeCode) != len(actual.RuntimeCode) {
+		result.FirstDifference = min(len(expected.RuntimeCode), len(actual.RuntimeCode))
+	}
+
+	switch {
+	case result.FirstDifference >= 0:
+		result.Status = Mismatch
+	case !bytes.Equal(expected.Metadata, actual.Metadata):
+		result.Status = PartialMatch
+	}
+	return result, nil
+}
+
+// maskedRanges returns the ranges of the runtime code set at link or deployment time.
+func (contracts CompilerOutput) maskedRanges(fqName string, contract map[string]interface{}, compiled []byte) ([]maskedRange, error) {
+	var masks []maskedRange
+
+	_, references, err := contracts.GetLinkReferences(fqName)
+	if err != nil {
+		return nil, err
+	}
+	for file, fileReferences := range references {
+		for name, positions := range fileReferences {
+			for _, position := range positions {
+				masks = append(masks, maskedRange{position.Start, position.Length, "library", file + ":" + name})
+			}
+		}
+	}
+
+	evm, _ := contract["evm"].(map[string]interface{})
+	deployed, _ := evm["deployedBytecode"].(map[string]interface{})
+	var immutables map[string][]LinkReference
+	if err := decodeOutput(deployed["immutableReferences"], &immutables); err != nil {
+		return nil, fmt.Errorf("invalid immutable references for contract %s: %v", fqName, err)
+	}
+	for id, positions := range immutables {
+		for _, position := range positions {
+			masks = append(masks, maskedRange{position.Start, position.Length, "immutable", id})
+		}
+	}
+
+	// libraries start with PUSH20 <address> ADDRESS EQ, the address is set at deployment
+	if len(compiled) > 22 && compiled[0] == 0x73 && bytes.Equal(compiled[1:21], make([]byte, 20)) && compiled[21] == 0x30 && compiled[22] == 0x14 {
+		masks = append(masks, maskedRange{1, 20, "deployment", "library address"})
+	}
+
+	sort.Slice(masks, func(i, j int) bool { return masks[i].start < masks[j].start })
+	return masks, nil
+}
+
+// String returns a short description of the result.
+func (r *VerificationResult) String() string {
+	switch r.Status {
+	case FullMatch:
+		return "full match"
+	case PartialMatch:
+		return "partial match (metadata differs)"
+	}
+	return "mismatch at offset " + strconv.Itoa(r.FirstDifference)
+}
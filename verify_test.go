@@ -0,0 +1,176 @@
+package gosolc
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestVerifyDeployed(t *testing.T) {
+	contracts := loadTestOutput(t, "testdata_output.json")
+	contract, _ := contracts.Contract("dummy_ERC20.sol:ERC20")
+	_, deployedBytecode, _ := contractBytecodes(contract)
+	runtime, err := decodeHex(deployedBytecode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	metadataOffset := len(runtime) - 53
+
+	result, err := contracts.VerifyDeployed("dummy_ERC20.sol:ERC20", deployedBytecode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Status != FullMatch || result.FirstDifference != -1 {
+		t.Errorf("expected a full match, got %v", result)
+	}
+
+	// different ipfs hash in the metadata trailer
+	onchain := append([]byte{}, runtime...)
+	onchain[metadataOffset+10] ^= 0xff
+	if result, err = contracts.VerifyDeployed("dummy_ERC20.sol:ERC20", hex.EncodeToString(onchain)); err != nil {
+		t.Fatal(err)
+	}
+	if result.Status != PartialMatch {
+		t.Errorf("expected a partial match, got %v", result)
+	}
+
+	// different code
+	onchain = append([]byte{}, runtime...)
+	onchain[100] ^= 0xff
+	if result, err = contracts.VerifyDeployed("dummy_ERC20.sol:ERC20", hex.EncodeToString(onchain)); err != nil {
+		t.Fatal(err)
+	}
+	if result.Status != Mismatch || result.FirstDifference != 100 {
+		t.Errorf("expected a mismatch at 100, got %v", result)
+	}
+
+	// truncated code
+	if result, err = contracts.VerifyDeployed("dummy_ERC20.sol:ERC20", hex.EncodeToString(runtime[:200])); err != nil {
+		t.Fatal(err)
+	}
+	if result.Status != Mismatch || result.FirstDifference != 200 {
+		t.Errorf("expected a mismatch at 200, got %v", result)
+	}
+
+	// immutable variables are masked
+	contract["evm"].(map[string]interface{})["deployedBytecode"].(map[string]interface{})["immutableReferences"] = map[string]interface{}{
+		"42": []interface{}{map[string]interface{}{"start": 100, "length": 32}},
+	}
+	onchain = append([]byte{}, runtime...)
+	onchain[131] ^= 0xff
+	if result, err = contracts.VerifyDeployed("dummy_ERC20.sol:ERC20", hex.EncodeToString(onchain)); err != nil {
+		t.Fatal(err)
+	}
+	if result.Status != FullMatch {
+		t.Errorf("expected a full match, got %v", result)
+	}
+	if expected := "0x" + hex.EncodeToString(onchain[100:132]); result.Immutables["42"] != expected {
+		t.Errorf("expected immutable 42 to be %s, got %s", expected, result.Immutables["42"])
+	}
+}
+
+func TestVerifyDeployedLibraries(t *testing.T) {
+	contracts := loadTestOutput(t, "testdata_output.json")
+	contract, _ := contracts.Contract("dummy_ERC20.sol:ERC20")
+	deployed := contract["evm"].(map[string]interface{})["deployedBytecode"].(map[string]interface{})
+	runtime, _ := decodeHex(deployed["object"].(string))
+
+	// a library placeholder at offset 100 of the compiled code, the library address in the deployed code
+	placeholder := "__$" + strings.Repeat("ab", 17) + "$__"
+	object := deployed["object"].(string)
+	deployed["object"] = object[:200] + placeholder + object[240:]
+	deployed["linkReferences"] = map[string]interface{}{
+		"libs/Math.sol": map[string]interface{}{"Math": []interface{}{map[string]interface{}{"start": 100, "length": 20}}},
+	}
+	library, _ := HexToAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+	onchain := append([]byte{}, runtime...)
+	copy(onchain[100:120], library[:])
+
+	result, err := contracts.VerifyDeployed("dummy_ERC20.sol:ERC20", hex.EncodeToString(onchain))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Status != FullMatch || result.Libraries["libs/Math.sol:Math"] != library.Hex() {
+		t.Errorf("expected a full match linking Math at %s, got %v %v", library.Hex(), result, result.Libraries)
+	}
+	onchain[120] ^= 0xff
+	if result, _ = contracts.VerifyDeployed("dummy_ERC20.sol:ERC20", hex.EncodeToString(onchain)); result.Status != Mismatch || result.FirstDifference != 120 {
+		t.Errorf("expected a mismatch after the library address, got %v", result)
+	}
+
+	// libraries start with PUSH20 <address> ADDRESS EQ, the address is set at deployment
+	code := "73" + strings.Repeat("00", 20) + "3014" + "6080604052"
+	contracts["libs/Math.sol"] = map[string]interface{}{"Math": map[string]interface{}{
+		"evm": map[string]interface{}{
+			"bytecode":         map[string]interface{}{"object": "6000", "linkReferences": map[string]interface{}{}},
+			"deployedBytecode": map[string]interface{}{"object": code, "linkReferences": map[string]interface{}{}},
+		},
+	}}
+	compiled, _ := decodeHex(code)
+	math, _ := contracts.Contract("libs/Math.sol:Math")
+	masks, err := contracts.maskedRanges("libs/Math.sol:Math", math, compiled)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(masks) != 1 || masks[0] != (maskedRange{1, 20, "deployment", "library address"}) {
+		t.Errorf("expected the library address to be masked, got %v", masks)
+	}
+	onchainLibrary := "73" + hex.EncodeToString(library[:]) + "3014" + "6080604052"
+	if result, err = contracts.VerifyDeployed("libs/Math.sol:Math", onchainLibrary); err != nil || result.Status != FullMatch {
+		t.Errorf("expected a full match of the deployed library, got %v %v", result, err)
+	}
+
+	// PUSH20 <address> not followed by ADDRESS EQ is regular code
+	compiled[21] = 0x31
+	if masks, _ = contracts.maskedRanges("libs/Math.sol:Math", math, compiled); len(masks) != 0 {
+		t.Errorf("unexpected masks %v", masks)
+	}
+}
+
+func TestVerifyDeployedAt(t *testing.T) {
+	contracts := loadTestOutput(t, "testdata_output.json")
+	contract, _ := contracts.Contract("dummy_ERC20.sol:ERC20")
+	_, deployedBytecode, _ := contractBytecodes(contract)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+			Params []string        `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Error(err)
+		}
+		response := map[string]interface{}{"jsonrpc": "2.0", "id": request.ID}
+		switch {
+		case request.Method != "eth_getCode":
+			response["error"] = map[string]interface{}{"code": -32601, "message": "method not found"}
+		case request.Params[0] == "0x0000000000000000000000000000000000000001":
+			response["result"] = "0x" + deployedBytecode
+		default:
+			response["result"] = "0x"
+		}
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	client := NewRPCClient(server.URL)
+	result, err := contracts.VerifyDeployedAt(context.Background(), client, "dummy_ERC20.sol:ERC20", Address{19: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Status != FullMatch {
+		t.Errorf("expected a full match, got %v", result)
+	}
+
+	if _, err := contracts.VerifyDeployedAt(context.Background(), client, "dummy_ERC20.sol:ERC20", Address{19: 2}); err == nil {
+		t.Error("expected an error for an account without code")
+	}
+	if err := client.Call(context.Background(), nil, "eth_chainId"); err == nil {
+		t.Error("expected an rpc error")
+	}
+}
@@ -9,6 +9,9 @@ var Module = {
 	onRuntimeInitialized: function() {
 		if (typeof Module.cwrap === 'function' && typeof Module._solidity_compile === 'function') {
 			solc.compile = Module.cwrap('solidity_compile', 'string', ['string', 'string']);
+			if (typeof Module._solidity_version === 'function') {
+				solc.version = Module.cwrap('solidity_version', 'string', []);
+			}
 		} else {
 			throw new Error('solidity_compile or cwrap not available');
 		}